@@ -24,12 +24,18 @@ func main() {
 
 	ctx := context.Background()
 	userRepo := repository.NewUserRepository(db)
+	ownerRepo := repository.NewTenantOwnerRepository(db)
 
 	// Seed admin user
 	if err := seedAdminUser(ctx, userRepo, cfg); err != nil {
 		log.Printf("Error seeding admin user: %v", err)
 	}
 
+	// Grant the admin its tenants
+	if err := seedAdminTenants(ctx, userRepo, ownerRepo, cfg); err != nil {
+		log.Printf("Error seeding admin tenants: %v", err)
+	}
+
 	log.Println("Auth service seeding completed!")
 }
 
@@ -62,3 +68,17 @@ func seedAdminUser(ctx context.Context, repo repository.UserRepository, cfg *con
 	return repo.Create(ctx, adminUser)
 }
 
+func seedAdminTenants(ctx context.Context, users repository.UserRepository, owners repository.TenantOwnerRepository, cfg *config.Config) error {
+	admin, err := users.GetByEmail(ctx, cfg.Seeder.AdminEmail)
+	if err != nil {
+		return err
+	}
+
+	for _, slug := range cfg.Seeder.AdminTenants {
+		if err := owners.Grant(ctx, &model.TenantOwner{Tenant: slug, UserID: admin.ID}); err != nil {
+			return err
+		}
+		log.Printf("Admin user owns tenant: %s", slug)
+	}
+	return nil
+}
@@ -105,10 +105,18 @@ func (h *AuthHandler) Verify(c *gin.Context) {
 		return
 	}
 
+	// Looked up on every verify, so grants apply without a new login
+	tenants, err := h.service.OwnedTenants(c.Request.Context(), claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load tenants"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"valid":     true,
 		"user_id":   claims.UserID,
 		"role":      claims.Role,
+		"tenants":   tenants,
 		"expires_at": claims.ExpiresAt,
 	})
 }
@@ -30,8 +30,10 @@ func NewServer(cfg *config.Config, zapLogger *zap.Logger) *Server {
 	}
 
 	userRepo := repository.NewUserRepository(db)
+	ownerRepo := repository.NewTenantOwnerRepository(db)
 	authService := service.NewAuthService(
 		userRepo,
+		ownerRepo,
 		cfg.JWT.Secret,
 		int(cfg.JWT.AccessExpiry.Minutes()),
 		int(cfg.JWT.RefreshExpiry.Hours()),
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"time"
 	"github.com/joho/godotenv"
 )
@@ -18,6 +19,7 @@ type SeederConfig struct {
 	AdminEmail    string
 	AdminPassword string
 	AdminName     string
+	AdminTenants  []string
 }
 
 type ServerConfig struct {
@@ -80,6 +82,7 @@ func Load() (*Config, error) {
 			AdminEmail:    getEnv("ADMIN_EMAIL", "admin@portfolio.com"),
 			AdminPassword: getEnv("ADMIN_PASSWORD", "Admin123!"),
 			AdminName:     getEnv("ADMIN_NAME", "Admin User"),
+			AdminTenants:  splitList(getEnv("ADMIN_TENANTS", "default")),
 		},
 	}
 
@@ -93,6 +96,17 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// splitList parses a comma-separated list, skipping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func (c *DatabaseConfig) DSN() string {
 	return "host=" + c.Host + " port=" + c.Port + " user=" + c.User + " password=" + c.Password + " dbname=" + c.DBName + " sslmode=" + c.SSLMode
 }
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+)
+
+// TenantOwner grants a user the admin API of one of the backend's tenants,
+// named by its slug
+type TenantOwner struct {
+	Tenant    string    `gorm:"type:varchar(63);primaryKey" json:"tenant"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (o *TenantOwner) TableName() string {
+	return "tenant_owners"
+}
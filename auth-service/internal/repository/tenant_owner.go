@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"github.com/portfolio/auth-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TenantOwnerRepository interface {
+	// Grant makes a user an owner of a tenant; granting twice is a no-op
+	Grant(ctx context.Context, owner *model.TenantOwner) error
+	ListTenants(ctx context.Context, userID string) ([]string, error)
+}
+
+type tenantOwnerRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantOwnerRepository(db *gorm.DB) TenantOwnerRepository {
+	return &tenantOwnerRepository{db: db}
+}
+
+func (r *tenantOwnerRepository) Grant(ctx context.Context, owner *model.TenantOwner) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(owner).Error
+}
+
+func (r *tenantOwnerRepository) ListTenants(ctx context.Context, userID string) ([]string, error) {
+	tenants := []string{}
+	err := r.db.WithContext(ctx).Model(&model.TenantOwner{}).
+		Where("user_id = ?", userID).
+		Order("tenant").
+		Pluck("tenant", &tenants).Error
+	return tenants, err
+}
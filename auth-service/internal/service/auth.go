@@ -15,18 +15,22 @@ type AuthService interface {
 	Login(ctx context.Context, email, password string) (string, string, *model.User, error)
 	RefreshToken(ctx context.Context, refreshToken string) (string, error)
 	VerifyToken(ctx context.Context, token string) (*jwt.Claims, error)
+	// OwnedTenants returns the slugs of the backend tenants a user owns
+	OwnedTenants(ctx context.Context, userID string) ([]string, error)
 }
 
 type authService struct {
 	userRepo repository.UserRepository
+	ownerRepo repository.TenantOwnerRepository
 	jwtSecret string
 	accessExpiry  int
 	refreshExpiry int
 }
 
-func NewAuthService(userRepo repository.UserRepository, jwtSecret string, accessExpiry, refreshExpiry int) AuthService {
+func NewAuthService(userRepo repository.UserRepository, ownerRepo repository.TenantOwnerRepository, jwtSecret string, accessExpiry, refreshExpiry int) AuthService {
 	return &authService{
 		userRepo:      userRepo,
+		ownerRepo:     ownerRepo,
 		jwtSecret:     jwtSecret,
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
@@ -112,3 +116,6 @@ func (s *authService) VerifyToken(ctx context.Context, token string) (*jwt.Claim
 	return jwt.ValidateToken(token, s.jwtSecret)
 }
 
+func (s *authService) OwnedTenants(ctx context.Context, userID string) ([]string, error) {
+	return s.ownerRepo.ListTenants(ctx, userID)
+}
@@ -0,0 +1,14 @@
+-- Owners of the backend's tenants, by tenant slug. The backend only lets
+-- owners of a tenant use its admin API.
+CREATE TABLE tenant_owners (
+    tenant VARCHAR(63) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant, user_id)
+);
+
+CREATE INDEX idx_tenant_owners_user_id ON tenant_owners(user_id);
+
+-- Existing admins keep managing the portfolio they had, now the default tenant
+INSERT INTO tenant_owners (tenant, user_id)
+SELECT 'default', id FROM users WHERE role = 'admin';
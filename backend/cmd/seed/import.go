@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/config"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+	"gorm.io/gorm"
+)
+
+// Longest value printed in a field change
+const maxChangeWidth = 60
+
+// runImport implements "seed import [-dry-run] <file>"
+func runImport(ctx context.Context, db *gorm.DB, cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "Print what would change without writing anything")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: seed import [-dry-run] <resume.json | linkedin-export.zip>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to read import file: %v", err)
+	}
+
+	// Writes drop the API's cached portfolio, as they would through the API
+	redisCache := cache.NewRedisCache(
+		fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
+		cfg.Redis.Password,
+		cfg.Redis.DB,
+	)
+	importService := service.NewImportService(
+		repository.NewPortfolioRepository(db),
+		service.ResumeRepositories{
+			Experience:     repository.NewExperienceRepository(db),
+			Education:      repository.NewEducationRepository(db),
+			Skills:         repository.NewSkillRepository(db),
+			Certifications: repository.NewCertificationRepository(db),
+			Awards:         repository.NewAwardRepository(db),
+		},
+		repository.NewProjectRepository(db),
+		redisCache,
+	)
+
+	result, err := importService.Import(ctx, data, *dryRun)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+	printImport(result)
+}
+
+func printImport(result *service.ImportResult) {
+	if result.DryRun {
+		fmt.Printf("Dry run of %s import; nothing was written\n", result.Format)
+	} else {
+		fmt.Printf("Imported %s\n", result.Format)
+	}
+
+	fmt.Printf("\nportfolio: %d fields changed\n", len(result.Portfolio))
+	printChanges("  ", result.Portfolio)
+
+	sections := []string{model.ResumeExperience, model.ResumeEducation, model.ResumeSkills, model.ResumeCertifications, model.ResumeAwards}
+	for _, section := range sections {
+		printSection(section, result.Sections[section])
+	}
+	printSection("projects", result.Projects)
+}
+
+func printSection(name string, diff *service.SectionDiff) {
+	fmt.Printf("\n%s: %d created, %d updated, %d unchanged, %d skipped\n", name, len(diff.Created), len(diff.Updated), diff.Unchanged, len(diff.Skipped))
+	for _, label := range diff.Created {
+		fmt.Printf("  + %s\n", label)
+	}
+	for _, entry := range diff.Updated {
+		fmt.Printf("  ~ %s\n", entry.Label)
+		printChanges("      ", entry.Changes)
+	}
+	for _, entry := range diff.Skipped {
+		fmt.Printf("  ! %s: %s\n", entry.Label, entry.Reason)
+	}
+}
+
+func printChanges(indent string, changes []service.FieldChange) {
+	for _, change := range changes {
+		fmt.Printf("%s%s: %s -> %s\n", indent, change.Field, changeValue(change.From), changeValue(change.To))
+	}
+}
+
+func changeValue(value interface{}) string {
+	if value == nil {
+		return "(empty)"
+	}
+	text := strings.ReplaceAll(fmt.Sprintf("%q", fmt.Sprint(value)), `\n`, " ")
+	if runes := []rune(text); len(runes) > maxChangeWidth {
+		text = string(runes[:maxChangeWidth-4]) + `..."`
+	}
+	return text
+}
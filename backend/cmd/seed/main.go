@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"os"
 	"github.com/portfolio/backend/internal/config"
+	"github.com/portfolio/backend/internal/markdown"
 	"github.com/portfolio/backend/internal/model"
 	"github.com/portfolio/backend/internal/repository"
 	"time"
@@ -24,11 +26,30 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
+	if err := repository.RegisterTenantScope(db); err != nil {
+		log.Fatalf("Failed to register tenant scope: %v", err)
+	}
 
 	ctx := context.Background()
 
+	// "seed tenant <slug> <name> [host...]" adds a tenant instead of seeding
+	if len(os.Args) > 1 && os.Args[1] == "tenant" {
+		runTenant(ctx, db, cfg, os.Args[2:])
+		return
+	}
+
+	// Everything else writes into the SEED_TENANT tenant
+	ctx = seedTenantContext(ctx, db, cfg)
+
+	// "seed import <file>" merges a resume file instead of seeding
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		runImport(ctx, db, cfg, os.Args[2:])
+		return
+	}
+
 	// Initialize repositories
 	articleRepo := repository.NewArticleRepository(db)
+	revisionRepo := repository.NewArticleRevisionRepository(db)
 	projectRepo := repository.NewProjectRepository(db)
 	portfolioRepo := repository.NewPortfolioRepository(db)
 
@@ -38,7 +59,8 @@ func main() {
 	}
 
 	// Seed articles
-	if err := seedArticles(ctx, articleRepo); err != nil {
+	renderer := markdown.NewRenderer(cfg.Markdown.HighlightStyle)
+	if err := seedArticles(ctx, articleRepo, revisionRepo, renderer); err != nil {
 		log.Printf("Error seeding articles: %v", err)
 	}
 
@@ -75,7 +97,7 @@ func seedPortfolio(ctx context.Context, repo repository.PortfolioRepository, cfg
 	return repo.CreateOrUpdate(ctx, portfolio)
 }
 
-func seedArticles(ctx context.Context, repo repository.ArticleRepository) error {
+func seedArticles(ctx context.Context, repo repository.ArticleRepository, revisionRepo repository.ArticleRevisionRepository, renderer *markdown.Renderer) error {
 	authorID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
 	now := time.Now()
 
@@ -245,8 +267,20 @@ These strategies reduced our API latency by 70%.`,
 	for _, article := range articles {
 		existing, _ := repo.GetBySlug(ctx, article.Slug)
 		if existing == nil {
+			if rendered, err := renderer.Render(article.Content); err == nil {
+				article.ContentHTML = rendered.HTML
+				for _, entry := range rendered.TOC {
+					article.TOC = append(article.TOC, model.TOCEntry{Level: entry.Level, Text: entry.Text, ID: entry.ID})
+				}
+				article.WordCount = rendered.WordCount
+				article.ReadingTimeMinutes = rendered.ReadingTimeMinutes
+			}
 			if err := repo.Create(ctx, article); err != nil {
 				log.Printf("Error creating article %s: %v", article.Slug, err)
+				continue
+			}
+			if err := revisionRepo.Create(ctx, model.NewArticleRevision(article, article.AuthorID)); err != nil {
+				log.Printf("Error creating revision for article %s: %v", article.Slug, err)
 			}
 		}
 	}
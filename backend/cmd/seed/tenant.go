@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/config"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+	"github.com/portfolio/backend/internal/tenant"
+	"gorm.io/gorm"
+)
+
+// runTenant implements "seed tenant [-site-url URL] [-api-url URL] <slug> <name> [host...]"
+func runTenant(ctx context.Context, db *gorm.DB, cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("tenant", flag.ExitOnError)
+	siteURL := flags.String("site-url", "", "Public website of the tenant (default SITE_URL)")
+	apiURL := flags.String("api-url", "", "API as the tenant reaches it (default API_URL under the path prefix)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: seed tenant [-site-url URL] [-api-url URL] <slug> <name> [host...]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	t := &model.Tenant{
+		Slug:    flags.Arg(0),
+		Name:    flags.Arg(1),
+		Hosts:   model.StringArray(flags.Args()[2:]),
+		SiteURL: *siteURL,
+		APIURL:  *apiURL,
+	}
+	if err := newTenantService(db, cfg).CreateTenant(ctx, t); err != nil {
+		log.Fatalf("Failed to create tenant: %v", err)
+	}
+	log.Printf("Created tenant %s (%s)", t.Slug, t.ID)
+	log.Printf("Grant its owners access in auth-service's tenant_owners table")
+}
+
+// seedTenantContext scopes ctx to the SEED_TENANT tenant. The default tenant
+// is created if it is missing, as on a database migrated by the server alone.
+func seedTenantContext(ctx context.Context, db *gorm.DB, cfg *config.Config) context.Context {
+	tenants := newTenantService(db, cfg)
+	t, err := tenants.GetBySlug(ctx, cfg.Seeder.Tenant)
+	if errors.Is(err, repository.ErrTenantNotFound) && cfg.Seeder.Tenant == model.DefaultTenant {
+		t = &model.Tenant{Slug: model.DefaultTenant, Name: cfg.Seeder.PortfolioName}
+		err = tenants.CreateTenant(ctx, t)
+	}
+	if err != nil {
+		log.Fatalf("Failed to load tenant %s: %v", cfg.Seeder.Tenant, err)
+	}
+	return tenant.NewContext(ctx, t.ID)
+}
+
+func newTenantService(db *gorm.DB, cfg *config.Config) service.TenantService {
+	redisCache := cache.NewRedisCache(
+		fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
+		cfg.Redis.Password,
+		cfg.Redis.DB,
+	)
+	return service.NewTenantService(repository.NewTenantRepository(db), redisCache, cfg.Site.URL, cfg.Site.APIURL, cfg.Tenants.PathPrefix, cfg.Tenants.Fallback)
+}
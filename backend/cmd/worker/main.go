@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/config"
+	"github.com/portfolio/backend/internal/events"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/markdown"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+	"github.com/portfolio/backend/internal/worker"
+	"github.com/portfolio/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// How long shutdown waits for in-flight events before giving up
+const shutdownTimeout = 30 * time.Second
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize logger
+	zapLogger, err := logger.New(cfg.LogLevel)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer zapLogger.Sync()
+
+	// Schema is owned by the server, which migrates on start
+	db, err := gorm.Open(postgres.Open(cfg.Database.DSN()), &gorm.Config{
+		PrepareStmt: true,
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	})
+	if err != nil {
+		zapLogger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	// Handlers run as the tenant of the event they handle
+	if err := repository.RegisterTenantScope(db); err != nil {
+		zapLogger.Fatal("Failed to register tenant scope", zap.Error(err))
+	}
+
+	redisCache := cache.NewRedisCache(
+		fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
+		cfg.Redis.Password,
+		cfg.Redis.DB,
+	)
+	kafkaProducer := kafka.NewProducer(cfg.Kafka.Brokers)
+
+	// Services the event handlers call into
+	articleRepo := repository.NewArticleRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	portfolioRepo := repository.NewPortfolioRepository(db)
+	articleTranslationRepo := repository.NewArticleTranslationRepository(db)
+	renderer := markdown.NewRenderer(cfg.Markdown.HighlightStyle)
+	tenantService := service.NewTenantService(repository.NewTenantRepository(db), redisCache, cfg.Site.URL, cfg.Site.APIURL, cfg.Tenants.PathPrefix, cfg.Tenants.Fallback)
+	articleService := service.NewArticleService(articleRepo, tagRepo, repository.NewArticleRevisionRepository(db), repository.NewMediaRepository(db), articleTranslationRepo, renderer, redisCache, cfg.Locales.Default)
+	feedService := service.NewFeedService(articleRepo, tagRepo, portfolioRepo, articleTranslationRepo, redisCache, tenantService, cfg.Locales.Default, cfg.Locales.Supported)
+	sitemapService := service.NewSitemapService(repository.NewSitemapRepository(db), portfolioRepo, redisCache, tenantService, cfg.Locales.Default)
+	projectRepo := repository.NewProjectRepository(db)
+	portfolioService := service.NewPortfolioService(portfolioRepo, service.ResumeRepositories{
+		Experience:     repository.NewExperienceRepository(db),
+		Education:      repository.NewEducationRepository(db),
+		Skills:         repository.NewSkillRepository(db),
+		Certifications: repository.NewCertificationRepository(db),
+		Awards:         repository.NewAwardRepository(db),
+	}, repository.NewPortfolioTranslationRepository(db), redisCache, cfg.Locales.Default)
+	resumeExportService := service.NewResumeExportService(portfolioService, projectRepo, redisCache, tenantService, cfg.Resume.DefaultTheme)
+	analyticsService := service.NewAnalyticsService(repository.NewAnalyticsRepository(db), kafkaProducer, redisCache, tenantService)
+
+	// Each concern has its own consumer group, so a slow webhook never holds
+	// up cache warming. Groups deduplicate on event ID, as the outbox relay
+	// may publish an event more than once.
+	newConsumer := func(groupID string, topics ...string) *kafka.Consumer {
+		return kafka.NewConsumer(kafka.ConsumerConfig{
+			Brokers:     cfg.Kafka.Brokers,
+			GroupID:     groupID,
+			Topics:      topics,
+			MaxAttempts: cfg.Worker.MaxAttempts,
+			MinBackoff:  cfg.Worker.MinBackoff,
+			MaxBackoff:  cfg.Worker.MaxBackoff,
+			Dedup:       redisCache,
+			DedupTTL:    cfg.Worker.DedupTTL,
+		}, zapLogger)
+	}
+
+	cacheConsumer := newConsumer("backend-cache-warmer", kafka.TopicArticles, kafka.TopicProjects, kafka.TopicResume)
+	events.NewCacheWarmer(articleService, feedService, sitemapService, resumeExportService, zapLogger).Register(cacheConsumer)
+
+	notifyConsumer := newConsumer("backend-notifications", kafka.TopicComments)
+	events.NewCommentNotifier(cfg.Worker.NotifyWebhookURL, zapLogger).Register(notifyConsumer)
+
+	consumers := []*kafka.Consumer{cacheConsumer, notifyConsumer}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+	for _, c := range consumers {
+		wg.Add(1)
+		go func(c *kafka.Consumer) {
+			defer wg.Done()
+			c.Run(ctx)
+		}(c)
+	}
+	if cfg.Analytics.ConsumerEnabled {
+		analyticsConsumer := worker.NewAnalyticsConsumer(analyticsService, cfg.Kafka.Brokers, zapLogger)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			analyticsConsumer.Run(ctx)
+		}()
+	}
+
+	// Metrics and health
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		kafka.WriteMetrics(w, consumers...)
+	})
+	metricsServer := &http.Server{Addr: ":" + cfg.Worker.MetricsPort, Handler: mux}
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			zapLogger.Error("Metrics server failed", zap.Error(err))
+		}
+	}()
+
+	zapLogger.Info("Worker started", zap.String("metrics_port", cfg.Worker.MetricsPort))
+	<-ctx.Done()
+	zapLogger.Info("Shutting down worker")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	metricsServer.Shutdown(shutdownCtx)
+
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		zapLogger.Warn("Consumers did not stop before the shutdown deadline")
+	}
+
+	if err := kafkaProducer.Close(); err != nil {
+		zapLogger.Error("Failed to close Kafka producer", zap.Error(err))
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
@@ -14,5 +14,13 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/spf13/viper v1.18.2
 	github.com/lib/pq v1.10.9
+	github.com/yuin/goldmark v1.7.8
+	github.com/yuin/goldmark-highlighting/v2 v2.0.0-20230729083705-37449abec8cc
+	github.com/alecthomas/chroma/v2 v2.14.0
+	github.com/microcosm-cc/bluemonday v1.0.27
+	github.com/minio/minio-go/v7 v7.0.50
+	golang.org/x/image v0.18.0
+	github.com/jung-kurt/gofpdf v1.16.2
+	gorm.io/driver/sqlite v1.5.6
 )
 
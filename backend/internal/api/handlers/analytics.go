@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type AnalyticsHandler struct {
+	service service.AnalyticsService
+}
+
+func NewAnalyticsHandler(service service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service}
+}
+
+// RecordPageView takes a page view from the site. The body is parsed as JSON
+// whatever its content type, so navigator.sendBeacon can post it as
+// text/plain without a CORS preflight.
+func (h *AnalyticsHandler) RecordPageView(c *gin.Context) {
+	var req struct {
+		Path     string `json:"path" binding:"required,max=2000"`
+		Referrer string `json:"referrer" binding:"max=2000"`
+	}
+
+	if err := c.ShouldBindWith(&req, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recorded, err := h.service.RecordPageView(c.Request.Context(), service.PageViewHit{
+		Path:      req.Path,
+		Referrer:  req.Referrer,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"recorded": recorded})
+}
+
+// GetTopPages ranks paths by views over ?from=&to= (dates, default the last
+// 30 days)
+func (h *AnalyticsHandler) GetTopPages(c *gin.Context) {
+	from, to, ok := analyticsRange(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	pages, err := h.service.GetTopPages(c.Request.Context(), from, to, limit)
+	if err != nil {
+		writeAnalyticsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": pages, "from": from.Format("2006-01-02"), "to": to.Format("2006-01-02")})
+}
+
+func (h *AnalyticsHandler) GetTopReferrers(c *gin.Context) {
+	from, to, ok := analyticsRange(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	referrers, err := h.service.GetTopReferrers(c.Request.Context(), from, to, limit)
+	if err != nil {
+		writeAnalyticsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": referrers, "from": from.Format("2006-01-02"), "to": to.Format("2006-01-02")})
+}
+
+// GetTraffic returns daily views and visitors for the site, or for one page
+// with ?path=
+func (h *AnalyticsHandler) GetTraffic(c *gin.Context) {
+	from, to, ok := analyticsRange(c)
+	if !ok {
+		return
+	}
+
+	traffic, err := h.service.GetTraffic(c.Request.Context(), from, to, c.Query("path"))
+	if err != nil {
+		writeAnalyticsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": traffic, "from": from.Format("2006-01-02"), "to": to.Format("2006-01-02")})
+}
+
+// ExportCSV downloads the daily rollups of the range as CSV
+func (h *AnalyticsHandler) ExportCSV(c *gin.Context) {
+	from, to, ok := analyticsRange(c)
+	if !ok {
+		return
+	}
+
+	pages, err := h.service.ExportDailyPages(c.Request.Context(), from, to)
+	if err != nil {
+		writeAnalyticsError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("analytics-%s-%s.csv", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"day", "path", "referrer_domain", "device", "views", "visitors"})
+	for _, p := range pages {
+		w.Write([]string{
+			p.Day.Format("2006-01-02"),
+			p.Path,
+			p.ReferrerDomain,
+			p.Device,
+			strconv.FormatInt(p.Views, 10),
+			strconv.FormatInt(p.Visitors, 10),
+		})
+	}
+	w.Flush()
+}
+
+// analyticsRange reads the inclusive ?from= and ?to= dates, writing a 400
+// and reporting false if either is malformed
+func analyticsRange(c *gin.Context) (time.Time, time.Time, bool) {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -29)
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter"})
+			return time.Time{}, time.Time{}, false
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter"})
+			return time.Time{}, time.Time{}, false
+		}
+		to = t
+	}
+	return from, to, true
+}
+
+func writeAnalyticsError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidAnalyticsRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/portfolio/backend/internal/model"
 	"github.com/portfolio/backend/internal/repository"
 	"github.com/portfolio/backend/internal/service"
-	"strconv"
+	"time"
 	"github.com/google/uuid"
 
 	"github.com/gin-gonic/gin"
@@ -21,16 +21,37 @@ func NewArticleHandler(service service.ArticleService) *ArticleHandler {
 }
 
 func (h *ArticleHandler) GetArticles(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, limit := pageQuery(c, 10)
 
-	articles, total, err := h.service.GetArticles(c.Request.Context(), page, limit)
+	tag := c.Query("tag")
+
+	if cursor, ok := cursorQuery(c); ok {
+		articles, pagination, err := h.service.GetArticlesByCursor(c.Request.Context(), cursor, limit, tag, requestLocale(c), includeTotal(c))
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidCursor) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		setCursorLinks(c, pagination)
+		c.JSON(http.StatusOK, gin.H{
+			"data":       articles,
+			"pagination": pagination,
+		})
+		return
+	}
+
+	articles, total, err := h.service.GetArticles(c.Request.Context(), page, limit, tag, requestLocale(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	totalPages := (int(total) + limit - 1) / limit
+	setPageLinks(c, page, limit, totalPages)
 	c.JSON(http.StatusOK, gin.H{
 		"data": articles,
 		"pagination": gin.H{
@@ -44,7 +65,7 @@ func (h *ArticleHandler) GetArticles(c *gin.Context) {
 
 func (h *ArticleHandler) GetArticleByID(c *gin.Context) {
 	id := c.Param("id")
-	article, err := h.service.GetArticleByID(c.Request.Context(), id)
+	article, err := h.service.GetArticleByID(c.Request.Context(), id, requestLocale(c))
 	if err != nil {
 		if errors.Is(err, repository.ErrArticleNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
@@ -58,7 +79,7 @@ func (h *ArticleHandler) GetArticleByID(c *gin.Context) {
 
 func (h *ArticleHandler) GetArticleBySlug(c *gin.Context) {
 	slug := c.Param("slug")
-	article, err := h.service.GetArticleBySlug(c.Request.Context(), slug)
+	article, err := h.service.GetArticleBySlug(c.Request.Context(), slug, requestLocale(c))
 	if err != nil {
 		if errors.Is(err, repository.ErrArticleNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
@@ -67,16 +88,24 @@ func (h *ArticleHandler) GetArticleBySlug(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if article.NoIndex {
+		c.Header("X-Robots-Tag", "noindex")
+	}
 	c.JSON(http.StatusOK, article)
 }
 
 func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 	var article struct {
-		Title     string `json:"title" binding:"required"`
-		Slug      string `json:"slug" binding:"required"`
-		Excerpt   string `json:"excerpt"`
-		Content   string `json:"content" binding:"required"`
-		Published bool   `json:"published"`
+		Title        string     `json:"title" binding:"required"`
+		Slug         string     `json:"slug" binding:"required"`
+		Excerpt      string     `json:"excerpt"`
+		Content      string     `json:"content" binding:"required"`
+		Published    bool       `json:"published"`
+		NoIndex      bool       `json:"noindex"`
+		CoverImageID *uuid.UUID `json:"cover_image_id"`
+		Tags         []string   `json:"tags"`
+		PublishAt    *time.Time `json:"publish_at"`
+		UnpublishAt  *time.Time `json:"unpublish_at"`
 	}
 
 	if err := c.ShouldBindJSON(&article); err != nil {
@@ -85,25 +114,39 @@ func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 	}
 
 	// Get author_id from JWT token
-	userID, _ := c.Get("user_id")
-	userIDStr, _ := userID.(string)
-	
-	authorID, err := uuid.Parse(userIDStr)
+	authorID, err := currentUserID(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
 	
 	articleModel := &model.Article{
-		Title:     article.Title,
-		Slug:      article.Slug,
-		Excerpt:   article.Excerpt,
-		Content:   article.Content,
-		Published: article.Published,
-		AuthorID:  authorID,
+		Title:        article.Title,
+		Slug:         article.Slug,
+		Excerpt:      article.Excerpt,
+		Content:      article.Content,
+		Published:    article.Published,
+		NoIndex:      article.NoIndex,
+		CoverImageID: article.CoverImageID,
+		AuthorID:     authorID,
+		Tags:         tagsFromSlugs(article.Tags),
+		PublishAt:    article.PublishAt,
+		UnpublishAt:  article.UnpublishAt,
 	}
 
 	if err := h.service.CreateArticle(c.Request.Context(), articleModel); err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown tag"})
+			return
+		}
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown cover image"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -115,11 +158,16 @@ func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 	id := c.Param("id")
 	
 	var article struct {
-		Title     string `json:"title"`
-		Slug      string `json:"slug"`
-		Excerpt   string `json:"excerpt"`
-		Content   string `json:"content"`
-		Published bool   `json:"published"`
+		Title        string     `json:"title"`
+		Slug         string     `json:"slug"`
+		Excerpt      string     `json:"excerpt"`
+		Content      string     `json:"content"`
+		Published    bool       `json:"published"`
+		NoIndex      bool       `json:"noindex"`
+		CoverImageID *uuid.UUID `json:"cover_image_id"`
+		Tags         []string   `json:"tags"`
+		PublishAt    *time.Time `json:"publish_at"`
+		UnpublishAt  *time.Time `json:"unpublish_at"`
 	}
 
 	if err := c.ShouldBindJSON(&article); err != nil {
@@ -127,19 +175,42 @@ func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 		return
 	}
 
+	editorID, err := currentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
 	articleModel := &model.Article{
-		Title:     article.Title,
-		Slug:      article.Slug,
-		Excerpt:   article.Excerpt,
-		Content:   article.Content,
-		Published: article.Published,
+		Title:        article.Title,
+		Slug:         article.Slug,
+		Excerpt:      article.Excerpt,
+		Content:      article.Content,
+		Published:    article.Published,
+		NoIndex:      article.NoIndex,
+		CoverImageID: article.CoverImageID,
+		Tags:         tagsFromSlugs(article.Tags),
+		PublishAt:    article.PublishAt,
+		UnpublishAt:  article.UnpublishAt,
 	}
 
-	if err := h.service.UpdateArticle(c.Request.Context(), id, articleModel); err != nil {
+	if err := h.service.UpdateArticle(c.Request.Context(), id, articleModel, editorID); err != nil {
 		if errors.Is(err, repository.ErrArticleNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
 			return
 		}
+		if errors.Is(err, repository.ErrTagNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown tag"})
+			return
+		}
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown cover image"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -160,3 +231,120 @@ func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Article deleted successfully"})
 }
 
+// GetPinnedArticles lists the pinned published articles in pinned order
+func (h *ArticleHandler) GetPinnedArticles(c *gin.Context) {
+	articles, err := h.service.GetPinnedArticles(c.Request.Context(), requestLocale(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": articles})
+}
+
+// PinArticles replaces the pinned articles with the listed ones, in order
+func (h *ArticleHandler) PinArticles(c *gin.Context) {
+	var req idsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.PinArticles(c.Request.Context(), req.IDs); err != nil {
+		if errors.Is(err, repository.ErrArticleNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown article"})
+			return
+		}
+		if errors.Is(err, service.ErrDuplicateID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Pinned articles updated successfully"})
+}
+
+// LinkProjects replaces the projects related to the article
+func (h *ArticleHandler) LinkProjects(c *gin.Context) {
+	var req idsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	article, err := h.service.LinkProjects(c.Request.Context(), c.Param("id"), req.IDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrArticleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		case errors.Is(err, repository.ErrProjectNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown project"})
+		case errors.Is(err, service.ErrDuplicateID):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, article)
+}
+
+
+// GetCalendar lists upcoming scheduled publishes and unpublishes. The window
+// defaults to the next 30 days.
+func (h *ArticleHandler) GetCalendar(c *gin.Context) {
+	from := time.Now().UTC()
+	to := from.AddDate(0, 0, 30)
+
+	if v := c.Query("from"); v != "" {
+		t, err := parseCalendarTime(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter"})
+			return
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := parseCalendarTime(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter"})
+			return
+		}
+		to = t
+	}
+
+	entries, err := h.service.GetCalendar(c.Request.Context(), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": entries, "from": from, "to": to})
+}
+
+// parseCalendarTime accepts RFC 3339 timestamps or plain dates
+func parseCalendarTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+// currentUserID returns the authenticated user set by the Auth middleware
+func currentUserID(c *gin.Context) (uuid.UUID, error) {
+	userID, _ := c.Get("user_id")
+	userIDStr, _ := userID.(string)
+	return uuid.Parse(userIDStr)
+}
+
+// tagsFromSlugs keeps nil (tags omitted from the request) distinct from an empty list
+func tagsFromSlugs(slugs []string) []model.Tag {
+	if slugs == nil {
+		return nil
+	}
+	tags := make([]model.Tag, 0, len(slugs))
+	for _, slug := range slugs {
+		tags = append(tags, model.Tag{Slug: slug})
+	}
+	return tags
+}
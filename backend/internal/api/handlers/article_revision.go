@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *ArticleHandler) GetRevisions(c *gin.Context) {
+	id := c.Param("id")
+	includeAutosaves := c.Query("include_autosaves") == "true"
+
+	revisions, err := h.service.ListRevisions(c.Request.Context(), id, includeAutosaves)
+	if err != nil {
+		writeRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": revisions})
+}
+
+func (h *ArticleHandler) GetRevision(c *gin.Context) {
+	revision, err := h.service.GetRevision(c.Request.Context(), c.Param("id"), c.Param("revisionId"))
+	if err != nil {
+		writeRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, revision)
+}
+
+func (h *ArticleHandler) DiffRevision(c *gin.Context) {
+	diff, err := h.service.DiffRevisions(c.Request.Context(), c.Param("id"), c.Param("revisionId"), c.Query("against"))
+	if err != nil {
+		writeRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
+func (h *ArticleHandler) RestoreRevision(c *gin.Context) {
+	editorID, err := currentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	article, err := h.service.RestoreRevision(c.Request.Context(), c.Param("id"), c.Param("revisionId"), editorID)
+	if err != nil {
+		writeRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, article)
+}
+
+func (h *ArticleHandler) AutosaveArticle(c *gin.Context) {
+	var draft struct {
+		Title   string `json:"title"`
+		Slug    string `json:"slug"`
+		Excerpt string `json:"excerpt"`
+		Content string `json:"content"`
+	}
+
+	if err := c.ShouldBindJSON(&draft); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	editorID, err := currentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	revision, err := h.service.AutosaveArticle(c.Request.Context(), c.Param("id"), &model.Article{
+		Title:   draft.Title,
+		Slug:    draft.Slug,
+		Excerpt: draft.Excerpt,
+		Content: draft.Content,
+	}, editorID)
+	if err != nil {
+		writeRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, revision)
+}
+
+func writeRevisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+	case errors.Is(err, repository.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	service service.CommentService
+}
+
+func NewCommentHandler(service service.CommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+// GetArticleComments returns the approved comments on an article as threads
+func (h *CommentHandler) GetArticleComments(c *gin.Context) {
+	comments, err := h.service.GetArticleComments(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": comments})
+}
+
+// CreateComment takes a visitor's comment. It is accepted for moderation
+// rather than published, so the response only acknowledges it, in the same
+// words for honeypot hits that were dropped.
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	var req struct {
+		ParentID    *uuid.UUID `json:"parent_id"`
+		AuthorName  string     `json:"author_name" binding:"required,max=100"`
+		AuthorEmail string     `json:"author_email" binding:"required,email,max=255"`
+		Content     string     `json:"content" binding:"required,max=5000"`
+		// Honeypot: hidden from people by the comment form
+		Website string `json:"website"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, err := h.service.CreateComment(c.Request.Context(), service.CommentSubmission{
+		ArticleID:   c.Param("id"),
+		ParentID:    req.ParentID,
+		AuthorName:  req.AuthorName,
+		AuthorEmail: req.AuthorEmail,
+		Content:     req.Content,
+		Honeypot:    req.Website,
+		IP:          c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrArticleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		case errors.Is(err, service.ErrInvalidParentComment):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrCommentRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Comment submitted for moderation"})
+}
+
+// GetComments is the moderation queue: ?status=pending|approved|spam and
+// ?article_id= narrow it down
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	page, limit := pageQuery(c, 20)
+
+	comments, total, err := h.service.ListComments(c.Request.Context(), page, limit, c.Query("status"), c.Query("article_id"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCommentStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+	c.JSON(http.StatusOK, gin.H{
+		"data": comments,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+		},
+	})
+}
+
+func (h *CommentHandler) UpdateCommentStatus(c *gin.Context) {
+	var req struct {
+		Status string `json:"status" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.service.UpdateCommentStatus(c.Request.Context(), c.Param("id"), req.Status)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCommentStatus):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrCommentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, comment)
+}
+
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	if err := h.service.DeleteComment(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, repository.ErrCommentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
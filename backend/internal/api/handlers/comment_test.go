@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubComments queues every submission except honeypot hits, which it drops
+// the way the real service does
+type stubComments struct {
+	service.CommentService
+}
+
+func (s *stubComments) CreateComment(ctx context.Context, submission service.CommentSubmission) (*model.Comment, error) {
+	if submission.Honeypot != "" {
+		return nil, nil
+	}
+	return &model.Comment{ID: uuid.New(), Status: model.CommentStatusPending}, nil
+}
+
+func postComment(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/articles/:id/comments", NewCommentHandler(&stubComments{}).CreateComment)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/articles/"+uuid.NewString()+"/comments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// Bots must not be able to tell they hit the honeypot
+func TestCreateCommentHoneypotLooksAccepted(t *testing.T) {
+	accepted := postComment(t, `{"author_name":"Ada","author_email":"ada@example.com","content":"Nice post"}`)
+	caught := postComment(t, `{"author_name":"Ada","author_email":"ada@example.com","content":"Nice post","website":"http://spam.example"}`)
+
+	if accepted.Code != http.StatusAccepted || caught.Code != http.StatusAccepted {
+		t.Fatalf("status %d and %d, want 202", accepted.Code, caught.Code)
+	}
+	if accepted.Body.String() != caught.Body.String() {
+		t.Errorf("bodies differ:\n%s\n%s", accepted.Body, caught.Body)
+	}
+}
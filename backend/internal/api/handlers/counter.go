@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CounterHandler struct {
+	service service.CounterService
+}
+
+func NewCounterHandler(service service.CounterService) *CounterHandler {
+	return &CounterHandler{service: service}
+}
+
+func (h *CounterHandler) RecordArticleView(c *gin.Context) {
+	h.recordView(c, model.CounterTargetArticle)
+}
+
+func (h *CounterHandler) ReactToArticle(c *gin.Context) {
+	h.react(c, model.CounterTargetArticle)
+}
+
+func (h *CounterHandler) RecordProjectView(c *gin.Context) {
+	h.recordView(c, model.CounterTargetProject)
+}
+
+func (h *CounterHandler) ReactToProject(c *gin.Context) {
+	h.react(c, model.CounterTargetProject)
+}
+
+// recordView answers 202 whether or not the view counted; counts are
+// written in batches, so there's nothing new to return yet
+func (h *CounterHandler) recordView(c *gin.Context, target string) {
+	counted, err := h.service.RecordView(c.Request.Context(), target, c.Param("id"), requestVisitor(c))
+	if err != nil {
+		writeCounterError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"counted": counted})
+}
+
+func (h *CounterHandler) react(c *gin.Context, target string) {
+	var req struct {
+		Type string `json:"type" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	counted, err := h.service.React(c.Request.Context(), target, c.Param("id"), req.Type, requestVisitor(c))
+	if err != nil {
+		writeCounterError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"counted": counted})
+}
+
+// requestVisitor identifies who is viewing or reacting. ClientIP only
+// believes X-Forwarded-For from TRUSTED_PROXIES, so a client can't pass for
+// new visitors by rotating it.
+func requestVisitor(c *gin.Context) service.Visitor {
+	return service.Visitor{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+func writeCounterError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+	case errors.Is(err, repository.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, service.ErrInvalidReaction):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
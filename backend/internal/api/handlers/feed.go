@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+	"github.com/portfolio/backend/pkg/feed"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	service service.FeedService
+}
+
+func NewFeedHandler(service service.FeedService) *FeedHandler {
+	return &FeedHandler{service: service}
+}
+
+func (h *FeedHandler) GetRSS(c *gin.Context) {
+	h.serve(c, feed.FormatRSS)
+}
+
+func (h *FeedHandler) GetAtom(c *gin.Context) {
+	h.serve(c, feed.FormatAtom)
+}
+
+func (h *FeedHandler) GetJSONFeed(c *gin.Context) {
+	h.serve(c, feed.FormatJSON)
+}
+
+func (h *FeedHandler) serve(c *gin.Context, format string) {
+	rendered, err := h.service.GetFeed(c.Request.Context(), format, c.Query("tag"), requestLocale(c))
+	if err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("ETag", rendered.ETag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !rendered.LastModified.IsZero() {
+		c.Header("Last-Modified", rendered.LastModified.Format(http.TimeFormat))
+	}
+
+	if notModified(c, rendered.ETag, rendered.LastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, rendered.ContentType, rendered.Body)
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since only
+// when no entity tag was sent (RFC 9110 section 13.2.2)
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		if since, err := http.ParseTime(ims); err == nil {
+			return !lastModified.After(since)
+		}
+	}
+	return false
+}
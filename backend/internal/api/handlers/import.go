@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"github.com/portfolio/backend/internal/service"
+	"github.com/portfolio/backend/pkg/resume"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Largest import accepted; LinkedIn exports without media are well under it
+const maxImportSize = 20 << 20
+
+type ImportHandler struct {
+	service service.ImportService
+}
+
+func NewImportHandler(service service.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// Import accepts a multipart form whose "file" is a JSON Resume document or
+// a LinkedIn data export ZIP. ?dry_run=true returns the diff without
+// writing anything.
+func (h *ImportHandler) Import(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Import file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the \"file\" field"})
+		return
+	}
+	if fileHeader.Size > maxImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Import file is too large"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Import(c.Request.Context(), data, dryRun)
+	if err != nil {
+		if errors.Is(err, resume.ErrUnknownFormat) || errors.Is(err, service.ErrResumeFieldRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+	"github.com/portfolio/backend/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Room for multipart boundaries and the other form fields on top of the file
+const multipartOverhead = 1 << 20
+
+type MediaHandler struct {
+	service service.MediaService
+	maxSize int64
+}
+
+func NewMediaHandler(service service.MediaService, maxSize int64) *MediaHandler {
+	return &MediaHandler{service: service, maxSize: maxSize}
+}
+
+// UploadMedia accepts a multipart form with a "file" field and optional "alt"
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrMediaTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the \"file\" field"})
+		return
+	}
+	if fileHeader.Size > h.maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrMediaTooLarge.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	uploadedBy, err := currentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	media, err := h.service.Upload(c.Request.Context(), file, fileHeader.Filename, c.PostForm("alt"), uploadedBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrMediaTooLarge), errors.Is(err, service.ErrImageTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUnsupportedMediaType):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrEmptyUpload):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+func (h *MediaHandler) GetMediaList(c *gin.Context) {
+	page, limit := pageQuery(c, 20)
+
+	media, total, err := h.service.ListMedia(c.Request.Context(), page, limit, c.Query("type"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+	c.JSON(http.StatusOK, gin.H{
+		"data": media,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+		},
+	})
+}
+
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	media, err := h.service.GetMedia(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, media)
+}
+
+func (h *MediaHandler) UpdateMedia(c *gin.Context) {
+	var req struct {
+		Alt string `json:"alt"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, err := h.service.UpdateMedia(c.Request.Context(), c.Param("id"), req.Alt)
+	if err != nil {
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, media)
+}
+
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	if err := h.service.DeleteMedia(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Media deleted successfully"})
+}
+
+// ReprocessMedia queues an image to have its variants rendered again
+func (h *MediaHandler) ReprocessMedia(c *gin.Context) {
+	media, err := h.service.ReprocessMedia(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrMediaNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		case errors.Is(err, service.ErrUnsupportedMediaType):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only images can be processed"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, media)
+}
+
+// ServeMedia streams an image at the requested width (?w=) and format
+// (?fmt=jpeg|png|webp). The closest rendered variant is served; until the
+// upload has been processed that is the original, cached only briefly.
+func (h *MediaHandler) ServeMedia(c *gin.Context) {
+	width, err := strconv.Atoi(c.DefaultQuery("w", "0"))
+	if err != nil || width < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid width"})
+		return
+	}
+
+	file, err := h.service.OpenMedia(c.Request.Context(), c.Param("id"), width, c.Query("fmt"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidMediaFormat):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrMediaNotFound), errors.Is(err, storage.ErrObjectNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	defer file.Body.Close()
+
+	c.Header("ETag", file.ETag)
+	c.Header("X-Content-Type-Options", "nosniff")
+	if file.Final {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "public, max-age=60")
+	}
+
+	if notModified(c, file.ETag, time.Time{}) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, file.Size, file.ContentType, file.Body, nil)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"github.com/portfolio/backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cursorQuery returns the cursor parameter. Its presence, even empty for the
+// first page, selects cursor pagination over page/limit.
+func cursorQuery(c *gin.Context) (string, bool) {
+	return c.GetQuery("cursor")
+}
+
+// pageQuery reads the page and limit parameters, clamped the way the
+// services clamp them so the reported pagination matches the page served
+func pageQuery(c *gin.Context, defaultLimit int) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = defaultLimit
+	}
+	return page, limit
+}
+
+// includeTotal reports whether a cursor-paginated request asked for the
+// total count, which costs an extra query
+func includeTotal(c *gin.Context) bool {
+	include, _ := strconv.ParseBool(c.Query("include_total"))
+	return include
+}
+
+// setLink sets an RFC 8288 Link header with a link per relation. Targets
+// are the request URL with its query rewritten by the matching function.
+func setLink(c *gin.Context, rels []string, rewrite map[string]func(url.Values)) {
+	links := make([]string, 0, len(rels))
+	for _, rel := range rels {
+		query := c.Request.URL.Query()
+		rewrite[rel](query)
+		target := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=\"%s\"", target.String(), rel))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
+
+// setCursorLinks links the first, previous and next pages of a cursor list
+func setCursorLinks(c *gin.Context, page *model.CursorPage) {
+	withCursor := func(cursor string) func(url.Values) {
+		return func(query url.Values) {
+			query.Del("page")
+			query.Set("cursor", cursor)
+			query.Set("limit", strconv.Itoa(page.Limit))
+		}
+	}
+
+	rels := []string{"first"}
+	rewrite := map[string]func(url.Values){"first": withCursor("")}
+	if page.PrevCursor != "" {
+		rels = append(rels, "prev")
+		rewrite["prev"] = withCursor(page.PrevCursor)
+	}
+	if page.NextCursor != "" {
+		rels = append(rels, "next")
+		rewrite["next"] = withCursor(page.NextCursor)
+	}
+	setLink(c, rels, rewrite)
+}
+
+// setPageLinks links the first, previous, next and last pages of a
+// page/limit list
+func setPageLinks(c *gin.Context, page, limit, totalPages int) {
+	withPage := func(n int) func(url.Values) {
+		return func(query url.Values) {
+			query.Set("page", strconv.Itoa(n))
+			query.Set("limit", strconv.Itoa(limit))
+		}
+	}
+
+	last := totalPages
+	if last < 1 {
+		last = 1
+	}
+	rels := []string{"first"}
+	rewrite := map[string]func(url.Values){"first": withPage(1), "last": withPage(last)}
+	if page > 1 {
+		rels = append(rels, "prev")
+		rewrite["prev"] = withPage(page - 1)
+	}
+	if page < totalPages {
+		rels = append(rels, "next")
+		rewrite["next"] = withPage(page + 1)
+	}
+	setLink(c, append(rels, "last"), rewrite)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubArticles serves total published articles in page/limit mode
+type stubArticles struct {
+	service.ArticleService
+	total int64
+}
+
+func (s *stubArticles) GetArticles(ctx context.Context, page, limit int, tag, locale string) ([]model.Article, int64, error) {
+	return []model.Article{}, s.total, nil
+}
+
+func getArticles(t *testing.T, total int64, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/articles", NewArticleHandler(&stubArticles{total: total}).GetArticles)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles"+query, nil))
+	return w
+}
+
+func TestPageQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		query       string
+		page, limit int
+	}{
+		{"", 1, 20},
+		{"?page=3&limit=50", 3, 50},
+		{"?limit=0", 1, 20},
+		{"?limit=-1", 1, 20},
+		{"?limit=101", 1, 20},
+		{"?limit=100", 1, 100},
+		{"?limit=ten", 1, 20},
+		{"?page=0", 1, 20},
+		{"?page=-2&limit=5", 1, 5},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+		if page, limit := pageQuery(c, 20); page != tt.page || limit != tt.limit {
+			t.Errorf("pageQuery(%q) = %d, %d; want %d, %d", tt.query, page, limit, tt.page, tt.limit)
+		}
+	}
+}
+
+// An out of range limit used to reach the total_pages division unclamped,
+// and limit=0 panicked
+func TestGetArticlesClampsLimit(t *testing.T) {
+	for _, query := range []string{"?limit=0", "?limit=-3", "?limit=1000", "?page=0&limit=0"} {
+		t.Run(query, func(t *testing.T) {
+			w := getArticles(t, 25, query)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", w.Code, w.Body)
+			}
+
+			var body struct {
+				Pagination struct {
+					Page       int `json:"page"`
+					Limit      int `json:"limit"`
+					TotalPages int `json:"total_pages"`
+				} `json:"pagination"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if p := body.Pagination; p.Page != 1 || p.Limit != 10 || p.TotalPages != 3 {
+				t.Errorf("pagination = %+v, want page 1, limit 10, 3 pages", p)
+			}
+			if link := w.Header().Get("Link"); !strings.Contains(link, `limit=10&page=3>; rel="last"`) {
+				t.Errorf("Link = %q", link)
+			}
+		})
+	}
+}
+
+func TestSetPageLinks(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int64
+		query     string
+		wantLinks []string
+	}{
+		{
+			name:      "empty list",
+			total:     0,
+			wantLinks: []string{`</articles?limit=10&page=1>; rel="first"`, `</articles?limit=10&page=1>; rel="last"`},
+		},
+		{
+			name:  "first page",
+			total: 25,
+			wantLinks: []string{
+				`</articles?limit=10&page=1>; rel="first"`,
+				`</articles?limit=10&page=2>; rel="next"`,
+				`</articles?limit=10&page=3>; rel="last"`,
+			},
+		},
+		{
+			name:  "middle page keeps other parameters",
+			total: 25,
+			query: "?page=2&tag=go",
+			wantLinks: []string{
+				`</articles?limit=10&page=1&tag=go>; rel="first"`,
+				`</articles?limit=10&page=1&tag=go>; rel="prev"`,
+				`</articles?limit=10&page=3&tag=go>; rel="next"`,
+				`</articles?limit=10&page=3&tag=go>; rel="last"`,
+			},
+		},
+		{
+			name:  "last page",
+			total: 25,
+			query: "?page=3",
+			wantLinks: []string{
+				`</articles?limit=10&page=1>; rel="first"`,
+				`</articles?limit=10&page=2>; rel="prev"`,
+				`</articles?limit=10&page=3>; rel="last"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getArticles(t, tt.total, tt.query)
+			if want := strings.Join(tt.wantLinks, ", "); w.Header().Get("Link") != want {
+				t.Errorf("Link =\n%s\nwant\n%s", w.Header().Get("Link"), want)
+			}
+		})
+	}
+}
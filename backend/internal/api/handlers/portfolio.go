@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strings"
 	"github.com/portfolio/backend/internal/model"
 	"github.com/portfolio/backend/internal/repository"
 	"github.com/portfolio/backend/internal/service"
@@ -18,13 +19,24 @@ func NewPortfolioHandler(service service.PortfolioService) *PortfolioHandler {
 	return &PortfolioHandler{service: service}
 }
 
+// GetPortfolio returns the portfolio; ?include=experience,skills adds
+// resume sections
 func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
-	portfolio, err := h.service.GetPortfolio(c.Request.Context())
+	var include []string
+	for _, value := range c.QueryArray("include") {
+		include = append(include, strings.Split(value, ",")...)
+	}
+
+	portfolio, err := h.service.GetPortfolio(c.Request.Context(), include, requestLocale(c))
 	if err != nil {
 		if errors.Is(err, repository.ErrPortfolioNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Portfolio not found"})
 			return
 		}
+		if errors.Is(err, service.ErrInvalidInclude) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
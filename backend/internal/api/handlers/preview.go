@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PreviewHandler struct {
+	service service.PreviewService
+}
+
+func NewPreviewHandler(service service.PreviewService) *PreviewHandler {
+	return &PreviewHandler{service: service}
+}
+
+// GetPreview renders a draft for whoever holds the link. Responses must never
+// be cached or indexed.
+func (h *PreviewHandler) GetPreview(c *gin.Context) {
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+	c.Header("Pragma", "no-cache")
+	c.Header("X-Robots-Tag", "noindex, nofollow, noarchive")
+
+	article, token, err := h.service.GetPreview(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPreviewToken), errors.Is(err, repository.ErrArticleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Preview not found"})
+		case errors.Is(err, service.ErrPreviewTokenExpired):
+			c.JSON(http.StatusGone, gin.H{"error": "Preview link has expired or was revoked"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": article,
+		"preview": gin.H{
+			"expires_at": token.ExpiresAt,
+			"robots":     "noindex, nofollow",
+		},
+	})
+}
+
+func (h *PreviewHandler) CreatePreviewToken(c *gin.Context) {
+	var req struct {
+		TTLHours int `json:"ttl_hours"`
+	}
+
+	// The body is optional; an empty one uses the default lifetime
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	createdBy, err := currentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	token, signed, err := h.service.CreatePreviewToken(c.Request.Context(), c.Param("id"), createdBy, time.Duration(req.TTLHours)*time.Hour)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrArticleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		case errors.Is(err, service.ErrInvalidPreviewTTL):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":         token.ID,
+		"article_id": token.ArticleID,
+		"token":      signed,
+		"path":       "/api/v1/preview/" + signed,
+		"expires_at": token.ExpiresAt,
+	})
+}
+
+func (h *PreviewHandler) GetPreviewTokens(c *gin.Context) {
+	tokens, err := h.service.ListPreviewTokens(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tokens})
+}
+
+func (h *PreviewHandler) RevokePreviewToken(c *gin.Context) {
+	if err := h.service.RevokePreviewToken(c.Request.Context(), c.Param("id"), c.Param("tokenId")); err != nil {
+		if errors.Is(err, repository.ErrPreviewTokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Preview token not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Preview token revoked successfully"})
+}
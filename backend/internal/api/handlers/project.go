@@ -6,7 +6,9 @@ import (
 	"github.com/portfolio/backend/internal/model"
 	"github.com/portfolio/backend/internal/repository"
 	"github.com/portfolio/backend/internal/service"
-	"strconv"
+	"strings"
+	"time"
+	"github.com/google/uuid"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,22 +22,48 @@ func NewProjectHandler(service service.ProjectService) *ProjectHandler {
 }
 
 func (h *ProjectHandler) GetProjects(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	
-	var featured *bool
-	if featuredStr := c.Query("featured"); featuredStr != "" {
-		feat := featuredStr == "true"
-		featured = &feat
+	page, limit := pageQuery(c, 10)
+	sort := c.Query("sort")
+
+	filter, ok := projectFilter(c)
+	if !ok {
+		return
+	}
+
+	if cursor, ok := cursorQuery(c); ok {
+		projects, pagination, err := h.service.GetProjectsByCursor(c.Request.Context(), cursor, limit, filter, sort, requestLocale(c), includeTotal(c))
+		if err != nil {
+			writeProjectListError(c, err)
+			return
+		}
+		facets, err := h.service.GetTechnologyFacets(c.Request.Context(), filter)
+		if err != nil {
+			writeProjectListError(c, err)
+			return
+		}
+
+		setCursorLinks(c, pagination)
+		c.JSON(http.StatusOK, gin.H{
+			"data":       projects,
+			"pagination": pagination,
+			"facets":     gin.H{"technologies": facets},
+		})
+		return
 	}
 
-	projects, total, err := h.service.GetProjects(c.Request.Context(), page, limit, featured)
+	projects, total, err := h.service.GetProjects(c.Request.Context(), page, limit, filter, sort, requestLocale(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProjectListError(c, err)
+		return
+	}
+	facets, err := h.service.GetTechnologyFacets(c.Request.Context(), filter)
+	if err != nil {
+		writeProjectListError(c, err)
 		return
 	}
 
 	totalPages := (int(total) + limit - 1) / limit
+	setPageLinks(c, page, limit, totalPages)
 	c.JSON(http.StatusOK, gin.H{
 		"data": projects,
 		"pagination": gin.H{
@@ -44,12 +72,84 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 			"total":      total,
 			"total_pages": totalPages,
 		},
+		"facets": gin.H{"technologies": facets},
 	})
 }
 
+// projectFilter reads the list filters from the query string, writing a 400
+// and reporting false if a date is malformed. Technologies may be given
+// comma-separated, repeated, or both.
+func projectFilter(c *gin.Context) (model.ProjectFilter, bool) {
+	var filter model.ProjectFilter
+
+	if featuredStr := c.Query("featured"); featuredStr != "" {
+		feat := featuredStr == "true"
+		filter.Featured = &feat
+	}
+
+	for _, value := range c.QueryArray("technologies") {
+		for _, technology := range strings.Split(value, ",") {
+			if technology = strings.TrimSpace(technology); technology != "" {
+				filter.Technologies = append(filter.Technologies, technology)
+			}
+		}
+	}
+	switch c.DefaultQuery("match", "any") {
+	case "any":
+	case "all":
+		filter.MatchAll = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "match must be any or all"})
+		return filter, false
+	}
+
+	filter.Search = strings.TrimSpace(c.Query("q"))
+
+	dates := []struct {
+		param string
+		until bool
+		dest  **time.Time
+	}{
+		{"created_from", false, &filter.CreatedFrom},
+		{"created_to", true, &filter.CreatedUntil},
+		{"updated_from", false, &filter.UpdatedFrom},
+		{"updated_to", true, &filter.UpdatedUntil},
+	}
+	for _, d := range dates {
+		v := c.Query(d.param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + d.param + " parameter"})
+			return filter, false
+		}
+		// The to dates are inclusive, so the bound is the next midnight
+		if d.until {
+			t = t.AddDate(0, 0, 1)
+		}
+		*d.dest = &t
+	}
+
+	return filter, true
+}
+
+func writeProjectListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCursor),
+		errors.Is(err, service.ErrInvalidProjectSort),
+		errors.Is(err, service.ErrSortWithCursor),
+		errors.Is(err, service.ErrTooManyTechnologies):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 func (h *ProjectHandler) GetProjectByID(c *gin.Context) {
 	id := c.Param("id")
-	project, err := h.service.GetProjectByID(c.Request.Context(), id)
+	project, err := h.service.GetProjectByID(c.Request.Context(), id, requestLocale(c))
 	if err != nil {
 		if errors.Is(err, repository.ErrProjectNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
@@ -69,6 +169,7 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		LiveURL     string   `json:"live_url"`
 		Technologies []string `json:"technologies"`
 		Featured    bool     `json:"featured"`
+		CoverImageID *uuid.UUID `json:"cover_image_id"`
 	}
 
 	if err := c.ShouldBindJSON(&project); err != nil {
@@ -83,9 +184,14 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		LiveURL:     project.LiveURL,
 		Technologies: model.StringArray(project.Technologies),
 		Featured:    project.Featured,
+		CoverImageID: project.CoverImageID,
 	}
 
 	if err := h.service.CreateProject(c.Request.Context(), projectModel); err != nil {
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown cover image"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -103,6 +209,7 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		LiveURL     string   `json:"live_url"`
 		Technologies []string `json:"technologies"`
 		Featured    bool     `json:"featured"`
+		CoverImageID *uuid.UUID `json:"cover_image_id"`
 	}
 
 	if err := c.ShouldBindJSON(&project); err != nil {
@@ -117,6 +224,7 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		LiveURL:     project.LiveURL,
 		Technologies: model.StringArray(project.Technologies),
 		Featured:    project.Featured,
+		CoverImageID: project.CoverImageID,
 	}
 
 	if err := h.service.UpdateProject(c.Request.Context(), id, projectModel); err != nil {
@@ -124,6 +232,10 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 			return
 		}
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown cover image"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -144,3 +256,97 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
 }
 
+// SetGallery replaces the project's gallery; the order of items is kept
+func (h *ProjectHandler) SetGallery(c *gin.Context) {
+	var req struct {
+		Items []struct {
+			MediaID uuid.UUID `json:"media_id" binding:"required"`
+			Caption string    `json:"caption"`
+		} `json:"items"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]model.ProjectMedia, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = model.ProjectMedia{MediaID: item.MediaID, Caption: item.Caption}
+	}
+
+	project, err := h.service.SetGallery(c.Request.Context(), c.Param("id"), items)
+	if err != nil {
+		if errors.Is(err, repository.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown media"})
+			return
+		}
+		if errors.Is(err, service.ErrDuplicateGalleryMedia) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// idsRequest lists the ids a reorder or link replacement applies, in order;
+// an empty list clears them
+type idsRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required"`
+}
+
+func (h *ProjectHandler) ReorderProjects(c *gin.Context) {
+	var req idsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ReorderProjects(c.Request.Context(), req.IDs); err != nil {
+		if errors.Is(err, repository.ErrProjectNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown project"})
+			return
+		}
+		if errors.Is(err, service.ErrDuplicateID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Projects reordered successfully"})
+}
+
+// LinkArticles replaces the articles related to the project
+func (h *ProjectHandler) LinkArticles(c *gin.Context) {
+	var req idsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.service.LinkArticles(c.Request.Context(), c.Param("id"), req.IDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		case errors.Is(err, repository.ErrArticleNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown article"})
+		case errors.Is(err, service.ErrDuplicateID):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
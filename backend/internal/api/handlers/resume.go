@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResumeSectionHandler serves the admin endpoints of one resume section
+type ResumeSectionHandler[T any] struct {
+	service service.ResumeSectionService[T]
+}
+
+func NewResumeSectionHandler[T any](service service.ResumeSectionService[T]) *ResumeSectionHandler[T] {
+	return &ResumeSectionHandler[T]{service: service}
+}
+
+func (h *ResumeSectionHandler[T]) List(c *gin.Context) {
+	entries, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": entries})
+}
+
+func (h *ResumeSectionHandler[T]) Create(c *gin.Context) {
+	var entry T
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Create(c.Request.Context(), &entry); err != nil {
+		writeResumeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+func (h *ResumeSectionHandler[T]) Update(c *gin.Context) {
+	var entry T
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.Update(c.Request.Context(), c.Param("id"), &entry)
+	if err != nil {
+		writeResumeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *ResumeSectionHandler[T]) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		writeResumeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Entry deleted successfully"})
+}
+
+func (h *ResumeSectionHandler[T]) Reorder(c *gin.Context) {
+	var req idsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Reorder(c.Request.Context(), req.IDs); err != nil {
+		if errors.Is(err, repository.ErrResumeEntryNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown entry"})
+			return
+		}
+		writeResumeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Entries reordered successfully"})
+}
+
+func writeResumeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrResumeEntryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entry not found"})
+	case errors.Is(err, service.ErrResumeFieldRequired),
+		errors.Is(err, service.ErrInvalidDateRange),
+		errors.Is(err, service.ErrInvalidSkillLevel),
+		errors.Is(err, service.ErrDuplicateID):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResumeExportHandler struct {
+	service service.ResumeExportService
+}
+
+func NewResumeExportHandler(service service.ResumeExportService) *ResumeExportHandler {
+	return &ResumeExportHandler{service: service}
+}
+
+// GetJSONResume serves the resume in the JSON Resume schema
+func (h *ResumeExportHandler) GetJSONResume(c *gin.Context) {
+	rendered, err := h.service.GetJSONResume(c.Request.Context())
+	h.serve(c, rendered, err, "")
+}
+
+// GetPDF serves the resume as PDF; ?theme= picks the layout
+func (h *ResumeExportHandler) GetPDF(c *gin.Context) {
+	rendered, err := h.service.GetPDF(c.Request.Context(), c.Query("theme"))
+	h.serve(c, rendered, err, "resume.pdf")
+}
+
+func (h *ResumeExportHandler) serve(c *gin.Context, rendered *service.RenderedResume, err error, filename string) {
+	if err != nil {
+		if errors.Is(err, repository.ErrPortfolioNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Portfolio not found"})
+			return
+		}
+		if errors.Is(err, service.ErrUnknownTheme) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error() + "; available: " + strings.Join(h.service.Themes(), ", ")})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("ETag", rendered.ETag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !rendered.LastModified.IsZero() {
+		c.Header("Last-Modified", rendered.LastModified.Format(http.TimeFormat))
+	}
+	if filename != "" {
+		c.Header("Content-Disposition", `inline; filename="`+filename+`"`)
+	}
+
+	if notModified(c, rendered.ETag, rendered.LastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, rendered.ContentType, rendered.Body)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	service service.SearchService
+}
+
+func NewSearchHandler(service service.SearchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+func (h *SearchHandler) Search(c *gin.Context) {
+	page, limit := pageQuery(c, 10)
+
+	query := repository.SearchQuery{
+		Query:      c.Query("q"),
+		Technology: c.Query("technology"),
+		Page:       page,
+		Limit:      limit,
+	}
+	if types := c.Query("type"); types != "" {
+		query.Types = strings.Split(types, ",")
+	}
+
+	result, err := h.service.Search(c.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, service.ErrEmptySearchQuery) || errors.Is(err, service.ErrInvalidSearchType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	totalPages := (int(result.Total) + limit - 1) / limit
+	c.JSON(http.StatusOK, gin.H{
+		"data":   result.Hits,
+		"facets": result.Facets,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       result.Total,
+			"total_pages": totalPages,
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const xmlContentType = "application/xml; charset=utf-8"
+
+type SitemapHandler struct {
+	service service.SitemapService
+}
+
+func NewSitemapHandler(service service.SitemapService) *SitemapHandler {
+	return &SitemapHandler{service: service}
+}
+
+func (h *SitemapHandler) GetSitemap(c *gin.Context) {
+	body, err := h.service.GetSitemap(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, xmlContentType, body)
+}
+
+// GetSitemapPage serves /sitemaps/<n>.xml, the files listed in the index
+func (h *SitemapHandler) GetSitemapPage(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("file"), ".xml"))
+	if err != nil || !strings.HasSuffix(c.Param("file"), ".xml") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+
+	body, err := h.service.GetSitemapPage(c.Request.Context(), page)
+	if err != nil {
+		if errors.Is(err, service.ErrSitemapPageNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, xmlContentType, body)
+}
+
+func (h *SitemapHandler) GetRobots(c *gin.Context) {
+	robots, err := h.service.GetRobots(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.String(http.StatusOK, robots)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	service service.TagService
+}
+
+func NewTagHandler(service service.TagService) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+func (h *TagHandler) GetTags(c *gin.Context) {
+	tags, err := h.service.GetTags(c.Request.Context(), c.Query("kind"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+func (h *TagHandler) GetTagBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	tag, err := h.service.GetTagBySlug(c.Request.Context(), slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var tag struct {
+		Name        string `json:"name" binding:"required"`
+		Slug        string `json:"slug"`
+		Kind        string `json:"kind"`
+		Description string `json:"description"`
+	}
+
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tagModel := &model.Tag{
+		Name:        tag.Name,
+		Slug:        tag.Slug,
+		Kind:        tag.Kind,
+		Description: tag.Description,
+	}
+
+	if err := h.service.CreateTag(c.Request.Context(), tagModel); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tagModel)
+}
+
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id := c.Param("id")
+
+	var tag struct {
+		Name        string `json:"name" binding:"required"`
+		Slug        string `json:"slug"`
+		Kind        string `json:"kind"`
+		Description string `json:"description"`
+	}
+
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tagModel := &model.Tag{
+		Name:        tag.Name,
+		Slug:        tag.Slug,
+		Kind:        tag.Kind,
+		Description: tag.Description,
+	}
+
+	if err := h.service.UpdateTag(c.Request.Context(), id, tagModel); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tagModel)
+}
+
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.DeleteTag(c.Request.Context(), id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+func (h *TagHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+	case errors.Is(err, service.ErrTagSlugExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidTagKind), errors.Is(err, service.ErrInvalidTagSlug):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TranslationHandler serves the admin endpoints for translating articles,
+// projects and the portfolio. The locale is the last path segment.
+type TranslationHandler struct {
+	service service.TranslationService
+}
+
+func NewTranslationHandler(service service.TranslationService) *TranslationHandler {
+	return &TranslationHandler{service: service}
+}
+
+// requestLocale returns the locale negotiated by middleware.Locale, or ""
+// (the default locale) when it didn't run
+func requestLocale(c *gin.Context) string {
+	return c.GetString("locale")
+}
+
+func (h *TranslationHandler) ListArticleTranslations(c *gin.Context) {
+	translations, err := h.service.ListArticleTranslations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": translations})
+}
+
+func (h *TranslationHandler) SaveArticleTranslation(c *gin.Context) {
+	var translation model.ArticleTranslation
+	if err := c.ShouldBindJSON(&translation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SaveArticleTranslation(c.Request.Context(), c.Param("id"), c.Param("locale"), &translation); err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *TranslationHandler) DeleteArticleTranslation(c *gin.Context) {
+	if err := h.service.DeleteArticleTranslation(c.Request.Context(), c.Param("id"), c.Param("locale")); err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted successfully"})
+}
+
+func (h *TranslationHandler) ListProjectTranslations(c *gin.Context) {
+	translations, err := h.service.ListProjectTranslations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": translations})
+}
+
+func (h *TranslationHandler) SaveProjectTranslation(c *gin.Context) {
+	var translation model.ProjectTranslation
+	if err := c.ShouldBindJSON(&translation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SaveProjectTranslation(c.Request.Context(), c.Param("id"), c.Param("locale"), &translation); err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *TranslationHandler) DeleteProjectTranslation(c *gin.Context) {
+	if err := h.service.DeleteProjectTranslation(c.Request.Context(), c.Param("id"), c.Param("locale")); err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted successfully"})
+}
+
+func (h *TranslationHandler) ListPortfolioTranslations(c *gin.Context) {
+	translations, err := h.service.ListPortfolioTranslations(c.Request.Context())
+	if err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": translations})
+}
+
+func (h *TranslationHandler) SavePortfolioTranslation(c *gin.Context) {
+	var translation model.PortfolioTranslation
+	if err := c.ShouldBindJSON(&translation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SavePortfolioTranslation(c.Request.Context(), c.Param("locale"), &translation); err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *TranslationHandler) DeletePortfolioTranslation(c *gin.Context) {
+	if err := h.service.DeletePortfolioTranslation(c.Request.Context(), c.Param("locale")); err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted successfully"})
+}
+
+func writeTranslationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+	case errors.Is(err, repository.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	case errors.Is(err, repository.ErrPortfolioNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Portfolio not found"})
+	case errors.Is(err, repository.ErrTranslationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+	case errors.Is(err, service.ErrTranslationSlugExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnsupportedLocale),
+		errors.Is(err, service.ErrDefaultLocale),
+		errors.Is(err, service.ErrTranslationIncomplete):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -37,9 +37,10 @@ func Auth(authServiceURL string) gin.HandlerFunc {
 		}
 
 		var verifyResp struct {
-			Valid   bool   `json:"valid"`
-			UserID  string `json:"user_id"`
-			Role    string `json:"role"`
+			Valid   bool     `json:"valid"`
+			UserID  string   `json:"user_id"`
+			Role    string   `json:"role"`
+			Tenants []string `json:"tenants"` // Slugs of the tenants the user owns
 		}
 		json.NewDecoder(resp.Body).Decode(&verifyResp)
 
@@ -51,6 +52,7 @@ func Auth(authServiceURL string) gin.HandlerFunc {
 
 		c.Set("user_id", verifyResp.UserID)
 		c.Set("role", verifyResp.Role)
+		c.Set("tenants", verifyResp.Tenants)
 		c.Set("token", token)
 
 		c.Next()
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/portfolio/backend/pkg/locale"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale negotiates the locale to serve the request in: ?lang= when it names
+// a supported locale, then the Accept-Language header, then the default. The
+// choice is stored under the "locale" key.
+func Locale(defaultLocale string, supported []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		negotiated := locale.Match(c.Query("lang"), supported)
+		if negotiated == "" {
+			negotiated = locale.Match(c.GetHeader("Accept-Language"), supported)
+		}
+		if negotiated == "" {
+			negotiated = defaultLocale
+		}
+
+		c.Set("locale", negotiated)
+		c.Writer.Header().Set("Content-Language", negotiated)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+	"github.com/portfolio/backend/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+type pathTenantKey struct{}
+
+// TenantPath serves a request for prefix/<slug>/rest as a request for /rest
+// by the tenant with that slug, so every route is reachable under the
+// prefix. It wraps the router, which only sees the stripped path.
+func TenantPath(prefix string, next http.Handler) http.Handler {
+	if prefix == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, prefix+"/")
+		slug, path, _ := strings.Cut(rest, "/")
+		if !ok || slug == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r = r.Clone(context.WithValue(r.Context(), pathTenantKey{}, slug))
+		r.URL.Path = "/" + path
+		r.URL.RawPath = ""
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Tenant scopes the request to the tenant it is for: the one named by its
+// path prefix, else the one claiming its Host, else the fallback tenant.
+// Requests for no tenant get a 404. The slug is stored under the "tenant" key.
+func Tenant(tenants service.TenantService, fallback string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		t, err := resolveTenant(ctx, tenants, c.Request.Host, fallback)
+		if err != nil {
+			if errors.Is(err, repository.ErrTenantNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Tenant not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.NewContext(ctx, t.ID))
+		c.Set("tenant", t.Slug)
+
+		c.Next()
+	}
+}
+
+func resolveTenant(ctx context.Context, tenants service.TenantService, host, fallback string) (*model.Tenant, error) {
+	if slug, ok := ctx.Value(pathTenantKey{}).(string); ok {
+		return tenants.GetBySlug(ctx, slug)
+	}
+
+	t, err := tenants.GetByHost(ctx, host)
+	if errors.Is(err, repository.ErrTenantNotFound) && fallback != "" {
+		return tenants.GetBySlug(ctx, fallback)
+	}
+	return t, err
+}
+
+// TenantOwner lets through only users who own the request's tenant, as told
+// by auth-service. It runs after Auth and Tenant.
+func TenantOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug := c.GetString("tenant")
+		for _, owned := range c.GetStringSlice("tenants") {
+			if owned == slug {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not an owner of this tenant"})
+		c.Abort()
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+	"github.com/portfolio/backend/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubTenants knows tenants by slug and serves each on <slug>.example.com
+type stubTenants struct {
+	service.TenantService
+	tenants map[string]*model.Tenant
+}
+
+func newStubTenants(slugs ...string) *stubTenants {
+	s := &stubTenants{tenants: map[string]*model.Tenant{}}
+	for _, slug := range slugs {
+		s.tenants[slug] = &model.Tenant{ID: uuid.New(), Slug: slug}
+	}
+	return s
+}
+
+func (s *stubTenants) GetBySlug(ctx context.Context, slug string) (*model.Tenant, error) {
+	if t, ok := s.tenants[slug]; ok {
+		return t, nil
+	}
+	return nil, repository.ErrTenantNotFound
+}
+
+func (s *stubTenants) GetByHost(ctx context.Context, host string) (*model.Tenant, error) {
+	for slug, t := range s.tenants {
+		if host == slug+".example.com" {
+			return t, nil
+		}
+	}
+	return nil, repository.ErrTenantNotFound
+}
+
+// tenantRouter answers /articles with the slug and tenant ID the request
+// was scoped to
+func tenantRouter(tenants service.TenantService, fallback string) http.Handler {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Tenant(tenants, fallback))
+	router.GET("/articles", func(c *gin.Context) {
+		id, _ := tenant.FromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"tenant": c.GetString("tenant"), "id": id})
+	})
+	return TenantPath("/t", router)
+}
+
+func TestTenantResolution(t *testing.T) {
+	tenants := newStubTenants("acme", "globex", "default")
+
+	tests := []struct {
+		name   string
+		host   string
+		path   string
+		status int
+		slug   string
+	}{
+		{"path prefix", "api.example.org", "/t/acme/articles", http.StatusOK, "acme"},
+		{"path prefix wins over host", "globex.example.com", "/t/acme/articles", http.StatusOK, "acme"},
+		{"host", "globex.example.com", "/articles", http.StatusOK, "globex"},
+		{"fallback", "api.example.org", "/articles", http.StatusOK, "default"},
+		{"unknown slug does not fall back", "globex.example.com", "/t/initech/articles", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Host = tt.host
+			tenantRouter(tenants, "default").ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.slug == "" {
+				return
+			}
+			want := `{"id":"` + tenants.tenants[tt.slug].ID.String() + `","tenant":"` + tt.slug + `"}`
+			if w.Body.String() != want {
+				t.Errorf("body = %s, want %s", w.Body, want)
+			}
+		})
+	}
+}
+
+func TestTenantWithoutFallback(t *testing.T) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	req.Host = "api.example.org"
+	tenantRouter(newStubTenants("acme"), "").ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestTenantOwner(t *testing.T) {
+	tenants := newStubTenants("acme", "globex")
+
+	tests := []struct {
+		name   string
+		owned  []string
+		status int
+	}{
+		{"owner", []string{"globex", "acme"}, http.StatusOK},
+		{"owner of another tenant", []string{"globex"}, http.StatusForbidden},
+		{"owner of none", nil, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			// Stands in for Auth, which stores the slugs the user owns
+			router.Use(func(c *gin.Context) {
+				if tt.owned != nil {
+					c.Set("tenants", tt.owned)
+				}
+			})
+			router.Use(Tenant(tenants, ""), TenantOwner())
+			router.DELETE("/articles", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/t/acme/articles", nil)
+			req.Host = "globex.example.com"
+			TenantPath("/t", router).ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
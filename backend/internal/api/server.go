@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 	"github.com/portfolio/backend/internal/api/handlers"
 	"github.com/portfolio/backend/internal/api/middleware"
 	"github.com/portfolio/backend/internal/config"
+	"github.com/portfolio/backend/internal/github"
 	"github.com/portfolio/backend/internal/model"
 	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/scheduler"
 	"github.com/portfolio/backend/internal/service"
+	"github.com/portfolio/backend/internal/storage"
+	"github.com/portfolio/backend/internal/worker"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -18,6 +23,7 @@ import (
 	"gorm.io/gorm"
 	"github.com/portfolio/backend/internal/cache"
 	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/markdown"
 )
 
 type Server struct {
@@ -26,6 +32,15 @@ type Server struct {
 	router   *gin.Engine
 	db       *gorm.DB
 	httpServer *http.Server
+	publisher  *scheduler.ArticlePublisher
+	processor  *worker.MediaProcessor
+	flusher    *worker.CounterFlusher
+	analytics  *worker.AnalyticsConsumer
+	relay      *worker.OutboxRelay
+	github     *worker.GithubSyncer
+	producer   *kafka.Producer
+	cancel     context.CancelFunc
+	workers    sync.WaitGroup
 }
 
 func NewServer(cfg *config.Config, zapLogger *zap.Logger) *Server {
@@ -39,6 +54,9 @@ func NewServer(cfg *config.Config, zapLogger *zap.Logger) *Server {
 	if err != nil {
 		zapLogger.Fatal("Failed to connect to database", zap.Error(err))
 	}
+	if err := repository.RegisterTenantScope(db); err != nil {
+		zapLogger.Fatal("Failed to register tenant scope", zap.Error(err))
+	}
 
 	// Auto migrate models
 	if err := autoMigrate(db, zapLogger); err != nil {
@@ -54,71 +72,316 @@ func NewServer(cfg *config.Config, zapLogger *zap.Logger) *Server {
 
 	// Initialize Kafka producer
 	kafkaProducer := kafka.NewProducer(cfg.Kafka.Brokers)
-	defer kafkaProducer.Close()
 
 	// Initialize repositories
+	tenantRepo := repository.NewTenantRepository(db)
 	articleRepo := repository.NewArticleRepository(db)
 	projectRepo := repository.NewProjectRepository(db)
 	portfolioRepo := repository.NewPortfolioRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	revisionRepo := repository.NewArticleRevisionRepository(db)
+	sitemapRepo := repository.NewSitemapRepository(db)
+	mediaRepo := repository.NewMediaRepository(db)
+	previewTokenRepo := repository.NewPreviewTokenRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	counterRepo := repository.NewCounterRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	resumeRepos := service.ResumeRepositories{
+		Experience:     repository.NewExperienceRepository(db),
+		Education:      repository.NewEducationRepository(db),
+		Skills:         repository.NewSkillRepository(db),
+		Certifications: repository.NewCertificationRepository(db),
+		Awards:         repository.NewAwardRepository(db),
+	}
+	translationRepos := service.TranslationRepositories{
+		Articles:  repository.NewArticleTranslationRepository(db),
+		Projects:  repository.NewProjectTranslationRepository(db),
+		Portfolio: repository.NewPortfolioTranslationRepository(db),
+	}
+
+	// Initialize media storage
+	mediaStorage, err := newMediaStorage(cfg)
+	if err != nil {
+		zapLogger.Fatal("Failed to initialize media storage", zap.Error(err))
+	}
 
 	// Initialize services (with Kafka and Redis)
-	articleService := service.NewArticleService(articleRepo, kafkaProducer, redisCache)
-	projectService := service.NewProjectService(projectRepo, kafkaProducer, redisCache)
-	portfolioService := service.NewPortfolioService(portfolioRepo)
+	renderer := markdown.NewRenderer(cfg.Markdown.HighlightStyle)
+	tenantService := service.NewTenantService(tenantRepo, redisCache, cfg.Site.URL, cfg.Site.APIURL, cfg.Tenants.PathPrefix, cfg.Tenants.Fallback)
+	articleService := service.NewArticleService(articleRepo, tagRepo, revisionRepo, mediaRepo, translationRepos.Articles, renderer, redisCache, cfg.Locales.Default)
+	projectService := service.NewProjectService(projectRepo, mediaRepo, translationRepos.Projects, redisCache, cfg.Locales.Default)
+	portfolioService := service.NewPortfolioService(portfolioRepo, resumeRepos, translationRepos.Portfolio, redisCache, cfg.Locales.Default)
+	experienceService := service.NewExperienceService(resumeRepos.Experience, redisCache)
+	educationService := service.NewEducationService(resumeRepos.Education, redisCache)
+	skillService := service.NewSkillService(resumeRepos.Skills, redisCache)
+	certificationService := service.NewCertificationService(resumeRepos.Certifications, redisCache)
+	awardService := service.NewAwardService(resumeRepos.Awards, redisCache)
+	searchService := service.NewSearchService(searchRepo)
+	tagService := service.NewTagService(tagRepo, redisCache)
+	previewService := service.NewPreviewService(previewTokenRepo, articleRepo, cfg.Preview.Secret, cfg.Preview.DefaultTTL, cfg.Preview.MaxTTL)
+	sitemapService := service.NewSitemapService(sitemapRepo, portfolioRepo, redisCache, tenantService, cfg.Locales.Default)
+	mediaService := service.NewMediaService(mediaRepo, mediaStorage, redisCache, cfg.Media.MaxUploadSize, cfg.Media.VariantWidths)
+	commentService := service.NewCommentService(commentRepo, articleRepo, redisCache, cfg.Comments.IPSalt, cfg.Comments.RateLimit, cfg.Comments.RateWindow)
+	counterService := service.NewCounterService(counterRepo, redisCache, cfg.Counters.VisitorSalt, cfg.Counters.ViewWindow, cfg.Counters.ReactionWindow, cfg.Counters.MaxClaps)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, kafkaProducer, redisCache, tenantService)
+	feedService := service.NewFeedService(articleRepo, tagRepo, portfolioRepo, translationRepos.Articles, redisCache, tenantService, cfg.Locales.Default, cfg.Locales.Supported)
+	importService := service.NewImportService(portfolioRepo, resumeRepos, projectRepo, redisCache)
+	resumeExportService := service.NewResumeExportService(portfolioService, projectRepo, redisCache, tenantService, cfg.Resume.DefaultTheme)
+	translationService := service.NewTranslationService(translationRepos, articleRepo, projectRepo, portfolioRepo, renderer, redisCache, cfg.Locales.Default, cfg.Locales.Supported)
 
 	// Initialize handlers
 	articleHandler := handlers.NewArticleHandler(articleService)
 	projectHandler := handlers.NewProjectHandler(projectService)
 	portfolioHandler := handlers.NewPortfolioHandler(portfolioService)
+	experienceHandler := handlers.NewResumeSectionHandler(experienceService)
+	educationHandler := handlers.NewResumeSectionHandler(educationService)
+	skillHandler := handlers.NewResumeSectionHandler(skillService)
+	certificationHandler := handlers.NewResumeSectionHandler(certificationService)
+	awardHandler := handlers.NewResumeSectionHandler(awardService)
+	resumeExportHandler := handlers.NewResumeExportHandler(resumeExportService)
+	importHandler := handlers.NewImportHandler(importService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	previewHandler := handlers.NewPreviewHandler(previewService)
+	feedHandler := handlers.NewFeedHandler(feedService)
+	sitemapHandler := handlers.NewSitemapHandler(sitemapService)
+	mediaHandler := handlers.NewMediaHandler(mediaService, cfg.Media.MaxUploadSize)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	counterHandler := handlers.NewCounterHandler(counterService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	translationHandler := handlers.NewTranslationHandler(translationService)
 
 	// Setup router
-	router := gin.Default()
+	router, err := newRouter(cfg.Server.TrustedProxies)
+	if err != nil {
+		zapLogger.Fatal("Invalid TRUSTED_PROXIES", zap.Error(err))
+	}
 	router.Use(middleware.CORS())
 	router.Use(middleware.Logger(zapLogger))
 	router.Use(middleware.Recovery(zapLogger))
+	router.Use(middleware.Locale(cfg.Locales.Default, cfg.Locales.Supported))
 
-	// Health check
+	// Health check, registered ahead of the tenant middleware so probes
+	// answer on any host
 	router.GET("/healthz", func(c *gin.Context) {
 		c.String(200, "ok")
 	})
 
+	// Everything else is served for one tenant, picked by /t/<slug> or Host
+	router.Use(middleware.Tenant(tenantService, cfg.Tenants.Fallback))
+
+	// Syndication feeds (optional ?tag=<slug>, ?lang=<locale>)
+	router.GET("/feed.xml", feedHandler.GetRSS)
+	router.GET("/atom.xml", feedHandler.GetAtom)
+	router.GET("/feed.json", feedHandler.GetJSONFeed)
+
+	// Uploaded media, when stored on local disk
+	if local, ok := mediaStorage.(*storage.LocalStorage); ok {
+		mediaFiles := router.Group("/uploads")
+		mediaFiles.Use(func(c *gin.Context) {
+			// Keys are unique per upload, so files never change
+			c.Header("Cache-Control", "public, max-age=31536000, immutable")
+			c.Header("X-Content-Type-Options", "nosniff")
+		})
+		mediaFiles.Static("/", local.Root())
+	}
+
+	// Responsive images: /media/:id?w=640&fmt=webp
+	router.GET("/media/:id", mediaHandler.ServeMedia)
+
+	// Crawler discovery
+	router.GET("/robots.txt", sitemapHandler.GetRobots)
+	router.GET("/sitemap.xml", sitemapHandler.GetSitemap)
+	router.GET("/sitemaps/:file", sitemapHandler.GetSitemapPage)
+
 	// Public API routes
 	v1 := router.Group("/api/v1")
 	{
 		// Articles
 		v1.GET("/articles", articleHandler.GetArticles)
+		v1.GET("/articles/pinned", articleHandler.GetPinnedArticles)
 		v1.GET("/articles/:id", articleHandler.GetArticleByID)
 		v1.GET("/articles/slug/:slug", articleHandler.GetArticleBySlug)
 
+		// Comments (visitors post without an account; held for moderation)
+		v1.GET("/articles/:id/comments", commentHandler.GetArticleComments)
+		v1.POST("/articles/:id/comments", commentHandler.CreateComment)
+
+		// Views and reactions (deduplicated per visitor, buffered in Redis)
+		v1.POST("/articles/:id/view", counterHandler.RecordArticleView)
+		v1.POST("/articles/:id/reactions", counterHandler.ReactToArticle)
+
 		// Projects
 		v1.GET("/projects", projectHandler.GetProjects)
 		v1.GET("/projects/:id", projectHandler.GetProjectByID)
+		v1.POST("/projects/:id/view", counterHandler.RecordProjectView)
+		v1.POST("/projects/:id/reactions", counterHandler.ReactToProject)
 
-		// Portfolio
+		// Portfolio (?include= adds resume sections)
 		v1.GET("/portfolio", portfolioHandler.GetPortfolio)
+
+		// Resume exports (?theme= picks the PDF layout)
+		v1.GET("/portfolio/resume.json", resumeExportHandler.GetJSONResume)
+		v1.GET("/portfolio/resume.pdf", resumeExportHandler.GetPDF)
+
+		// Search
+		v1.GET("/search", searchHandler.Search)
+
+		// Tags
+		v1.GET("/tags", tagHandler.GetTags)
+		v1.GET("/tags/:slug", tagHandler.GetTagBySlug)
+
+		// Page view analytics (no cookies; visitors are daily salted hashes)
+		v1.POST("/analytics/pageview", analyticsHandler.RecordPageView)
+
+		// Draft previews (signed, expiring links)
+		v1.GET("/preview/:token", previewHandler.GetPreview)
 	}
 
 	// Admin API routes (require authentication)
 	admin := v1.Group("/admin")
-	admin.Use(middleware.Auth(cfg.Auth.ServiceURL))
+	admin.Use(middleware.Auth(cfg.Auth.ServiceURL), middleware.TenantOwner())
 	{
 		// Articles
 		admin.POST("/articles", articleHandler.CreateArticle)
 		admin.PUT("/articles/:id", articleHandler.UpdateArticle)
 		admin.DELETE("/articles/:id", articleHandler.DeleteArticle)
+		admin.GET("/articles/calendar", articleHandler.GetCalendar)
+		admin.PUT("/articles/pinned", articleHandler.PinArticles)
+		admin.PUT("/articles/:id/projects", articleHandler.LinkProjects)
+		admin.POST("/articles/:id/autosave", articleHandler.AutosaveArticle)
+
+		// Article revisions
+		admin.GET("/articles/:id/revisions", articleHandler.GetRevisions)
+		admin.GET("/articles/:id/revisions/:revisionId", articleHandler.GetRevision)
+		admin.GET("/articles/:id/revisions/:revisionId/diff", articleHandler.DiffRevision)
+		admin.POST("/articles/:id/revisions/:revisionId/restore", articleHandler.RestoreRevision)
+
+		// Article translations (one per supported locale besides the default)
+		admin.GET("/articles/:id/translations", translationHandler.ListArticleTranslations)
+		admin.PUT("/articles/:id/translations/:locale", translationHandler.SaveArticleTranslation)
+		admin.DELETE("/articles/:id/translations/:locale", translationHandler.DeleteArticleTranslation)
+
+		// Article preview links
+		admin.GET("/articles/:id/preview-tokens", previewHandler.GetPreviewTokens)
+		admin.POST("/articles/:id/preview-tokens", previewHandler.CreatePreviewToken)
+		admin.DELETE("/articles/:id/preview-tokens/:tokenId", previewHandler.RevokePreviewToken)
+
+		// Comment moderation
+		admin.GET("/comments", commentHandler.GetComments)
+		admin.PUT("/comments/:id/status", commentHandler.UpdateCommentStatus)
+		admin.DELETE("/comments/:id", commentHandler.DeleteComment)
 
 		// Projects
 		admin.POST("/projects", projectHandler.CreateProject)
 		admin.PUT("/projects/:id", projectHandler.UpdateProject)
 		admin.DELETE("/projects/:id", projectHandler.DeleteProject)
+		admin.PUT("/projects/:id/gallery", projectHandler.SetGallery)
+		admin.PUT("/projects/order", projectHandler.ReorderProjects)
+		admin.PUT("/projects/:id/articles", projectHandler.LinkArticles)
+		admin.GET("/projects/:id/translations", translationHandler.ListProjectTranslations)
+		admin.PUT("/projects/:id/translations/:locale", translationHandler.SaveProjectTranslation)
+		admin.DELETE("/projects/:id/translations/:locale", translationHandler.DeleteProjectTranslation)
+
+		// Media library
+		admin.POST("/media", mediaHandler.UploadMedia)
+		admin.GET("/media", mediaHandler.GetMediaList)
+		admin.GET("/media/:id", mediaHandler.GetMedia)
+		admin.PUT("/media/:id", mediaHandler.UpdateMedia)
+		admin.DELETE("/media/:id", mediaHandler.DeleteMedia)
+		admin.POST("/media/:id/reprocess", mediaHandler.ReprocessMedia)
+
+		// Analytics reports
+		admin.GET("/analytics/pages", analyticsHandler.GetTopPages)
+		admin.GET("/analytics/referrers", analyticsHandler.GetTopReferrers)
+		admin.GET("/analytics/traffic", analyticsHandler.GetTraffic)
+		admin.GET("/analytics/export.csv", analyticsHandler.ExportCSV)
 
 		// Portfolio
 		admin.PUT("/portfolio", portfolioHandler.UpdatePortfolio)
+		admin.POST("/portfolio/import", importHandler.Import) // ?dry_run=true to preview
+		admin.GET("/portfolio/translations", translationHandler.ListPortfolioTranslations)
+		admin.PUT("/portfolio/translations/:locale", translationHandler.SavePortfolioTranslation)
+		admin.DELETE("/portfolio/translations/:locale", translationHandler.DeletePortfolioTranslation)
+
+		// Resume sections
+		admin.GET("/resume/experience", experienceHandler.List)
+		admin.POST("/resume/experience", experienceHandler.Create)
+		admin.PUT("/resume/experience/order", experienceHandler.Reorder)
+		admin.PUT("/resume/experience/:id", experienceHandler.Update)
+		admin.DELETE("/resume/experience/:id", experienceHandler.Delete)
+		admin.GET("/resume/education", educationHandler.List)
+		admin.POST("/resume/education", educationHandler.Create)
+		admin.PUT("/resume/education/order", educationHandler.Reorder)
+		admin.PUT("/resume/education/:id", educationHandler.Update)
+		admin.DELETE("/resume/education/:id", educationHandler.Delete)
+		admin.GET("/resume/skills", skillHandler.List)
+		admin.POST("/resume/skills", skillHandler.Create)
+		admin.PUT("/resume/skills/order", skillHandler.Reorder)
+		admin.PUT("/resume/skills/:id", skillHandler.Update)
+		admin.DELETE("/resume/skills/:id", skillHandler.Delete)
+		admin.GET("/resume/certifications", certificationHandler.List)
+		admin.POST("/resume/certifications", certificationHandler.Create)
+		admin.PUT("/resume/certifications/order", certificationHandler.Reorder)
+		admin.PUT("/resume/certifications/:id", certificationHandler.Update)
+		admin.DELETE("/resume/certifications/:id", certificationHandler.Delete)
+		admin.GET("/resume/awards", awardHandler.List)
+		admin.POST("/resume/awards", awardHandler.Create)
+		admin.PUT("/resume/awards/order", awardHandler.Reorder)
+		admin.PUT("/resume/awards/:id", awardHandler.Update)
+		admin.DELETE("/resume/awards/:id", awardHandler.Delete)
+
+		// Tags
+		admin.POST("/tags", tagHandler.CreateTag)
+		admin.PUT("/tags/:id", tagHandler.UpdateTag)
+		admin.DELETE("/tags/:id", tagHandler.DeleteTag)
 	}
 
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
-		Handler: router,
+		Handler: middleware.TenantPath(cfg.Tenants.PathPrefix, router),
+	}
+
+	// Scheduled publishing runs on every replica, coordinated by a Redis lock
+	var publisher *scheduler.ArticlePublisher
+	if cfg.Scheduler.Enabled {
+		publisher = scheduler.NewArticlePublisher(articleService, redisCache, zapLogger, cfg.Scheduler.Interval)
+	}
+
+	// Image derivatives are rendered off the request path by queue workers
+	var processor *worker.MediaProcessor
+	if cfg.Media.ProcessorEnabled {
+		processor = worker.NewMediaProcessor(mediaService, redisCache, zapLogger, cfg.Media.ProcessorConcurrency)
+	}
+
+	// View and reaction counts reach Postgres in batches
+	var flusher *worker.CounterFlusher
+	if cfg.Counters.FlushEnabled {
+		flusher = worker.NewCounterFlusher(counterService, zapLogger, cfg.Counters.FlushInterval)
+	}
+
+	// Page view events are rolled up into daily stats
+	var analyticsConsumer *worker.AnalyticsConsumer
+	if cfg.Analytics.ConsumerEnabled {
+		analyticsConsumer = worker.NewAnalyticsConsumer(analyticsService, cfg.Kafka.Brokers, zapLogger)
+	}
+
+	// Domain events committed to the outbox are published by a single
+	// replica at a time, coordinated by a Redis lock
+	var relay *worker.OutboxRelay
+	if cfg.Outbox.RelayEnabled {
+		outboxService := service.NewOutboxService(repository.NewOutboxRepository(db), kafkaProducer, cfg.Outbox.MaxAttempts)
+		relay = worker.NewOutboxRelay(outboxService, redisCache, zapLogger, cfg.Outbox.RelayInterval, cfg.Outbox.MaxBackoff, cfg.Outbox.Retention)
+	}
+
+	// Project repository metadata is pulled from GitHub by one replica at a
+	// time, coordinated by a Redis lock
+	var githubSyncer *worker.GithubSyncer
+	if cfg.Github.SyncEnabled {
+		githubClient := github.NewClient(cfg.Github.APIURL, cfg.Github.Token)
+		githubService := service.NewGithubSyncService(projectRepo, githubClient, redisCache, cfg.Github.SyncTechnologies)
+		githubSyncer = worker.NewGithubSyncer(githubService, redisCache, zapLogger, cfg.Github.SyncInterval)
 	}
 
 	return &Server{
@@ -127,10 +390,38 @@ func NewServer(cfg *config.Config, zapLogger *zap.Logger) *Server {
 		router:     router,
 		db:         db,
 		httpServer: httpServer,
+		publisher:  publisher,
+		processor:  processor,
+		flusher:    flusher,
+		analytics:  analyticsConsumer,
+		relay:      relay,
+		github:     githubSyncer,
+		producer:   kafkaProducer,
 	}
 }
 
 func (s *Server) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	if s.publisher != nil {
+		s.runWorker(func() { s.publisher.Run(ctx) })
+	}
+	if s.processor != nil {
+		s.runWorker(func() { s.processor.Run(ctx) })
+	}
+	if s.flusher != nil {
+		s.runWorker(func() { s.flusher.Run(ctx) })
+	}
+	if s.analytics != nil {
+		s.runWorker(func() { s.analytics.Run(ctx) })
+	}
+	if s.relay != nil {
+		s.runWorker(func() { s.relay.Run(ctx) })
+	}
+	if s.github != nil {
+		s.runWorker(func() { s.github.Run(ctx) })
+	}
+
 	s.logger.Info("Starting server", 
 		zap.String("host", s.config.Server.Host),
 		zap.String("port", s.config.Server.Port),
@@ -138,7 +429,36 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
+// runWorker starts a background worker that Shutdown waits for
+func (s *Server) runWorker(run func()) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		run()
+	}()
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
+	// Stop background workers, letting them finish in-flight work (such as
+	// a last counter flush) before the database goes away
+	if s.cancel != nil {
+		s.cancel()
+	}
+	stopped := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.logger.Warn("Background workers did not stop before the shutdown deadline")
+	}
+
+	if err := s.producer.Close(); err != nil {
+		s.logger.Error("Failed to close Kafka producer", zap.Error(err))
+	}
+
 	// Close database connection
 	sqlDB, err := s.db.DB()
 	if err == nil {
@@ -147,12 +467,66 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
 
+// newRouter returns the engine, believing X-Forwarded-For only from the
+// trusted proxies. Believing it from anyone would let clients dodge per-IP
+// limits and visitor dedup by sending a new address each time.
+func newRouter(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
+// newMediaStorage picks the storage backend configured by MEDIA_STORAGE
+func newMediaStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.Media.Storage {
+	case "local":
+		publicURL := cfg.Media.PublicURL
+		if publicURL == "" {
+			publicURL = cfg.Site.APIURL + "/uploads"
+		}
+		return storage.NewLocalStorage(cfg.Media.LocalPath, publicURL)
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.Media.S3Endpoint,
+			Region:    cfg.Media.S3Region,
+			Bucket:    cfg.Media.S3Bucket,
+			AccessKey: cfg.Media.S3AccessKey,
+			SecretKey: cfg.Media.S3SecretKey,
+			UseSSL:    cfg.Media.S3UseSSL,
+			PublicURL: cfg.Media.PublicURL,
+		})
+	}
+	return nil, fmt.Errorf("unknown media storage %q", cfg.Media.Storage)
+}
+
 // autoMigrate runs GORM AutoMigrate for all models
 func autoMigrate(db *gorm.DB, logger *zap.Logger) error {
 	models := []interface{}{
+		&model.Tenant{},
 		&model.Article{},
 		&model.Project{},
 		&model.Portfolio{},
+		&model.Tag{},
+		&model.ArticleRevision{},
+		&model.PreviewToken{},
+		&model.Media{},
+		&model.MediaVariant{},
+		&model.ProjectMedia{},
+		&model.ProjectGithub{},
+		&model.Comment{},
+		&model.AnalyticsDailyPage{},
+		&model.AnalyticsDailyVisitors{},
+		&model.OutboxEvent{},
+		&model.Experience{},
+		&model.Education{},
+		&model.Skill{},
+		&model.Certification{},
+		&model.Award{},
+		&model.ArticleTranslation{},
+		&model.ProjectTranslation{},
+		&model.PortfolioTranslation{},
 	}
 
 	for _, m := range models {
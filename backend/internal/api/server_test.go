@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRouterClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "no proxies trusted",
+			remoteAddr: "198.51.100.7:5000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.1", "X-Real-IP": "203.0.113.2"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "trusted proxy",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.1"},
+			want:       "203.0.113.1",
+		},
+		{
+			name:       "trusted proxy appending to a client's header",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"X-Forwarded-For": "192.0.2.99, 203.0.113.1"},
+			want:       "203.0.113.1",
+		},
+		{
+			name:       "client outside the trusted range",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "198.51.100.7:5000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.1"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "single trusted address",
+			trusted:    []string{"192.0.2.10"},
+			remoteAddr: "192.0.2.10:5000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.1"},
+			want:       "203.0.113.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, err := newRouter(tt.trusted)
+			if err != nil {
+				t.Fatalf("newRouter: %v", err)
+			}
+			router.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterRejectsInvalidProxy(t *testing.T) {
+	if _, err := newRouter([]string{"not-an-ip"}); err == nil {
+		t.Error("newRouter accepted an invalid proxy")
+	}
+}
@@ -4,10 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
+	"github.com/portfolio/backend/internal/tenant"
 	"github.com/redis/go-redis/v9"
 )
 
+// RedisCache stores cached content under keys private to the tenant the
+// context is scoped to: Get, Set, GetOrSet, Delete and DeletePattern, and the
+// article helpers built on them, prefix keys with tenant:<id>:. Locks, queues,
+// counters and dedup keys are shared by the whole deployment.
 type RedisCache struct {
 	client *redis.Client
 }
@@ -22,8 +28,16 @@ func NewRedisCache(addr, password string, db int) *RedisCache {
 	}
 }
 
+// tenantKey prefixes key with the tenant ctx is scoped to, if any
+func tenantKey(ctx context.Context, key string) string {
+	if id, ok := tenant.FromContext(ctx); ok {
+		return fmt.Sprintf("tenant:%s:%s", id, key)
+	}
+	return key
+}
+
 func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
-	data, err := c.client.Get(ctx, key).Bytes()
+	data, err := c.client.Get(ctx, tenantKey(ctx, key)).Bytes()
 	if err == redis.Nil {
 		return nil, ErrCacheMiss
 	}
@@ -31,15 +45,15 @@ func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
 }
 
 func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
-	return c.client.Set(ctx, key, value, ttl).Err()
+	return c.client.Set(ctx, tenantKey(ctx, key), value, ttl).Err()
 }
 
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
-	return c.client.Del(ctx, key).Err()
+	return c.client.Del(ctx, tenantKey(ctx, key)).Err()
 }
 
 func (c *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
-	keys, err := c.client.Keys(ctx, pattern).Result()
+	keys, err := c.client.Keys(ctx, tenantKey(ctx, pattern)).Result()
 	if err != nil {
 		return err
 	}
@@ -67,5 +81,115 @@ func (c *RedisCache) InvalidateArticles(ctx context.Context) error {
 	return c.DeletePattern(ctx, "articles:*")
 }
 
+// AcquireLock takes a distributed lock held until ttl expires or ReleaseLock
+// is called with the same token. It reports false if another holder has it.
+func (c *RedisCache) AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, fmt.Sprintf("lock:%s", key), token, ttl).Result()
+}
+
+// releaseLockScript deletes the lock only if it still belongs to the caller
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (c *RedisCache) ReleaseLock(ctx context.Context, key, token string) error {
+	return releaseLockScript.Run(ctx, c.client, []string{fmt.Sprintf("lock:%s", key)}, token).Err()
+}
+
+// Increment bumps a counter that expires ttl after its first increment, for
+// fixed-window rate limits
+func (c *RedisCache) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	count, err := c.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if err := c.client.Expire(ctx, key, ttl).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// Enqueue pushes a job payload onto a Redis list used as a FIFO queue
+func (c *RedisCache) Enqueue(ctx context.Context, queue string, payload []byte) error {
+	return c.client.LPush(ctx, fmt.Sprintf("queue:%s", queue), payload).Err()
+}
+
+// Dequeue blocks for up to timeout waiting for the oldest job on queue. It
+// returns ErrCacheMiss if the queue stayed empty.
+func (c *RedisCache) Dequeue(ctx context.Context, queue string, timeout time.Duration) ([]byte, error) {
+	result, err := c.client.BRPop(ctx, timeout, fmt.Sprintf("queue:%s", queue)).Result()
+	if err == redis.Nil {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(result[1]), nil
+}
+
+// IncrementField adds by to a field of a Redis hash, for buffering counters
+// that are written to the database later
+func (c *RedisCache) IncrementField(ctx context.Context, key, field string, by int64) error {
+	return c.client.HIncrBy(ctx, key, field, by).Err()
+}
+
+// drainHashScript reads and deletes a hash in one step, so increments made
+// while a drain runs are never lost
+var drainHashScript = redis.NewScript(`
+local fields = redis.call("HGETALL", KEYS[1])
+redis.call("DEL", KEYS[1])
+return fields
+`)
+
+// DrainHash returns the integer fields of a hash and deletes it atomically
+func (c *RedisCache) DrainHash(ctx context.Context, key string) (map[string]int64, error) {
+	fields, err := drainHashScript.Run(ctx, c.client, []string{key}).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]int64, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		n, err := strconv.ParseInt(fields[i+1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[fields[i]] = n
+	}
+	return values, nil
+}
+
+// Exists reports whether key is set
+func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.client.Exists(ctx, key).Result()
+	return n > 0, err
+}
+
+// MarkSeen records key for ttl and reports whether it was new, for
+// deduplicating events
+func (c *RedisCache) MarkSeen(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, 1, ttl).Result()
+}
+
+// GetOrSet stores value under key unless the key already exists, and returns
+// whichever value is stored. Concurrent callers all get the same value. Like
+// Get and Set, the key is private to the tenant.
+func (c *RedisCache) GetOrSet(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, error) {
+	key = tenantKey(ctx, key)
+	if err := c.client.SetNX(ctx, key, value, ttl).Err(); err != nil {
+		return nil, err
+	}
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, ErrCacheMiss
+	}
+	return data, err
+}
+
 var ErrCacheMiss = fmt.Errorf("cache miss")
 
@@ -3,21 +3,41 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 	"github.com/joho/godotenv"
+	"github.com/portfolio/backend/pkg/locale"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Kafka    KafkaConfig
-	Auth     AuthConfig
-	LogLevel string
-	Seeder   SeederConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Kafka     KafkaConfig
+	Auth      AuthConfig
+	Scheduler SchedulerConfig
+	Preview   PreviewConfig
+	Markdown  MarkdownConfig
+	Site      SiteConfig
+	Media     MediaConfig
+	Comments  CommentsConfig
+	Counters  CountersConfig
+	Analytics AnalyticsConfig
+	Outbox    OutboxConfig
+	Github    GithubConfig
+	Resume    ResumeConfig
+	Locales   LocalesConfig
+	Tenants   TenantsConfig
+	Worker    WorkerConfig
+	LogLevel  string
+	Seeder    SeederConfig
 }
 
 type SeederConfig struct {
+	Tenant             string // Slug of the tenant seeded and imported into
 	PortfolioName      string
 	PortfolioTitle     string
 	PortfolioEmail     string
@@ -32,6 +52,9 @@ type SeederConfig struct {
 type ServerConfig struct {
 	Port string
 	Host string
+	// Proxies, as IPs or CIDRs, whose X-Forwarded-For names the client.
+	// Empty trusts none, so the client is the connection's peer.
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -59,6 +82,117 @@ type AuthConfig struct {
 	JWTSecret  string
 }
 
+type SchedulerConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+type PreviewConfig struct {
+	Secret     string
+	DefaultTTL time.Duration
+	MaxTTL     time.Duration
+}
+
+type MarkdownConfig struct {
+	// Chroma style used for inline code highlighting
+	HighlightStyle string
+}
+
+// SiteConfig holds the public URLs used when building absolute links
+type SiteConfig struct {
+	URL    string // Public website, where articles are read
+	APIURL string // This API as reachable from outside
+}
+
+type MediaConfig struct {
+	Storage       string // "local" or "s3"
+	LocalPath     string
+	PublicURL     string // Base URL uploads are served from; empty uses the backend default
+	MaxUploadSize int64
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3UseSSL      bool
+
+	// Derivative pipeline
+	VariantWidths        []int // Widths images are resized to, ascending
+	ProcessorEnabled     bool
+	ProcessorConcurrency int
+}
+
+type CommentsConfig struct {
+	IPSalt     string // Mixed into hashed visitor IPs
+	RateLimit  int    // Comments allowed per IP per window; 0 disables the limit
+	RateWindow time.Duration
+}
+
+type CountersConfig struct {
+	VisitorSalt    string        // Mixed into hashed visitor ids used for dedup
+	ViewWindow     time.Duration // A visitor's repeat views within this count once
+	ReactionWindow time.Duration
+	MaxClaps       int // Claps per visitor per reaction window
+	FlushEnabled   bool
+	FlushInterval  time.Duration
+}
+
+type AnalyticsConfig struct {
+	// Run the consumer that rolls page view events up into daily stats
+	ConsumerEnabled bool
+}
+
+type OutboxConfig struct {
+	// Run the relay that publishes committed domain events to Kafka
+	RelayEnabled  bool
+	RelayInterval time.Duration
+	MaxBackoff    time.Duration // Longest wait between retries while Kafka is failing
+	MaxAttempts   int           // Failed publishes before an event is parked
+	Retention     time.Duration // Sent events are kept this long; 0 keeps them forever
+}
+
+type GithubConfig struct {
+	// Run the syncer that pulls repository metadata for projects
+	SyncEnabled  bool
+	SyncInterval time.Duration
+	APIURL       string // Pointed at a stub server in tests
+	Token        string // Optional; anonymous requests get 60 an hour
+	// Add each repository's primary languages to the project's technologies
+	SyncTechnologies bool
+}
+
+type ResumeConfig struct {
+	DefaultTheme string // PDF theme used when ?theme= is absent
+}
+
+type LocalesConfig struct {
+	// Locale of the articles, projects and portfolio themselves; every
+	// other locale is a translation of them
+	Default   string
+	Supported []string // Locales served, the default first
+}
+
+// TenantsConfig controls how a request is matched to the tenant it is for
+type TenantsConfig struct {
+	// Requests under PathPrefix/<slug> are for that tenant, whatever the host
+	PathPrefix string
+	// Slug of the tenant serving hosts no tenant claims; empty answers 404
+	Fallback string
+}
+
+// WorkerConfig configures cmd/worker, which consumes domain events
+type WorkerConfig struct {
+	MetricsPort string // Serves /metrics and /healthz
+	// Handler attempts per event before it goes to the dead-letter topic
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// How long a handled event ID is remembered for deduplication
+	DedupTTL time.Duration
+	// Chat webhook told about comments awaiting moderation; empty only logs
+	NotifyWebhookURL string
+}
+
 func Load() (*Config, error) {
 	// Determine environment
 	env := getEnv("ENV", "development")
@@ -82,13 +216,42 @@ func Load() (*Config, error) {
 	viper.SetDefault("LOG_LEVEL", "info")
 	viper.SetDefault("DB_SSLMODE", "disable")
 	viper.SetDefault("REDIS_DB", 0)
+	viper.SetDefault("SCHEDULER_ENABLED", true)
+	viper.SetDefault("SCHEDULER_INTERVAL", "30s")
+	viper.SetDefault("PREVIEW_TOKEN_TTL", "72h")
+	viper.SetDefault("PREVIEW_TOKEN_MAX_TTL", "720h")
+	viper.SetDefault("MEDIA_MAX_UPLOAD_SIZE", 10<<20)
+	viper.SetDefault("S3_USE_SSL", true)
+	viper.SetDefault("MEDIA_PROCESSOR_ENABLED", true)
+	viper.SetDefault("MEDIA_PROCESSOR_CONCURRENCY", 2)
+	viper.SetDefault("COMMENTS_RATE_LIMIT", 5)
+	viper.SetDefault("COMMENTS_RATE_WINDOW", "10m")
+	viper.SetDefault("COUNTERS_VIEW_WINDOW", "30m")
+	viper.SetDefault("COUNTERS_REACTION_WINDOW", "24h")
+	viper.SetDefault("COUNTERS_MAX_CLAPS", 10)
+	viper.SetDefault("COUNTERS_FLUSH_ENABLED", true)
+	viper.SetDefault("COUNTERS_FLUSH_INTERVAL", "30s")
+	viper.SetDefault("ANALYTICS_CONSUMER_ENABLED", true)
+	viper.SetDefault("CONSUMER_MAX_ATTEMPTS", 5)
+	viper.SetDefault("CONSUMER_MIN_BACKOFF", "1s")
+	viper.SetDefault("CONSUMER_MAX_BACKOFF", "1m")
+	viper.SetDefault("CONSUMER_DEDUP_TTL", "168h")
+	viper.SetDefault("OUTBOX_RELAY_ENABLED", true)
+	viper.SetDefault("OUTBOX_RELAY_INTERVAL", "1s")
+	viper.SetDefault("OUTBOX_MAX_BACKOFF", "1m")
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	viper.SetDefault("OUTBOX_RETENTION", "168h")
+	viper.SetDefault("GITHUB_SYNC_ENABLED", true)
+	viper.SetDefault("GITHUB_SYNC_INTERVAL", "1h")
+	viper.SetDefault("GITHUB_SYNC_TECHNOLOGIES", false)
 
 	viper.AutomaticEnv()
 
 	cfg := &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			Host:           getEnv("SERVER_HOST", "0.0.0.0"),
+			TrustedProxies: getList("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -111,8 +274,88 @@ func Load() (*Config, error) {
 			ServiceURL: getEnv("AUTH_SERVICE_URL", "http://localhost:8081"),
 			JWTSecret:  getEnv("JWT_SECRET", "your-secret-key"),
 		},
+		Scheduler: SchedulerConfig{
+			Enabled:  viper.GetBool("SCHEDULER_ENABLED"),
+			Interval: viper.GetDuration("SCHEDULER_INTERVAL"),
+		},
+		Preview: PreviewConfig{
+			// Falls back to the JWT secret so previews work without extra setup
+			Secret:     getEnv("PREVIEW_SECRET", getEnv("JWT_SECRET", "your-secret-key")),
+			DefaultTTL: viper.GetDuration("PREVIEW_TOKEN_TTL"),
+			MaxTTL:     viper.GetDuration("PREVIEW_TOKEN_MAX_TTL"),
+		},
+		Markdown: MarkdownConfig{
+			HighlightStyle: getEnv("MARKDOWN_HIGHLIGHT_STYLE", "github"),
+		},
+		Site: SiteConfig{
+			URL:    strings.TrimSuffix(getEnv("SITE_URL", "http://localhost:5173"), "/"),
+			APIURL: strings.TrimSuffix(getEnv("API_URL", "http://localhost:8080"), "/"),
+		},
+		Media: MediaConfig{
+			Storage:       getEnv("MEDIA_STORAGE", "local"),
+			LocalPath:     getEnv("MEDIA_LOCAL_PATH", "./uploads"),
+			PublicURL:     getEnv("MEDIA_PUBLIC_URL", ""),
+			MaxUploadSize: viper.GetInt64("MEDIA_MAX_UPLOAD_SIZE"),
+			S3Endpoint:    getEnv("S3_ENDPOINT", ""),
+			S3Region:      getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:      getEnv("S3_BUCKET", ""),
+			S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+			S3UseSSL:      viper.GetBool("S3_USE_SSL"),
+
+			VariantWidths:        getIntList("MEDIA_VARIANT_WIDTHS", "160,320,640,960,1280,1920"),
+			ProcessorEnabled:     viper.GetBool("MEDIA_PROCESSOR_ENABLED"),
+			ProcessorConcurrency: viper.GetInt("MEDIA_PROCESSOR_CONCURRENCY"),
+		},
+		Comments: CommentsConfig{
+			IPSalt:     getEnv("COMMENTS_IP_SALT", getEnv("JWT_SECRET", "your-secret-key")),
+			RateLimit:  viper.GetInt("COMMENTS_RATE_LIMIT"),
+			RateWindow: viper.GetDuration("COMMENTS_RATE_WINDOW"),
+		},
+		Counters: CountersConfig{
+			VisitorSalt:    getEnv("COUNTERS_VISITOR_SALT", getEnv("JWT_SECRET", "your-secret-key")),
+			ViewWindow:     viper.GetDuration("COUNTERS_VIEW_WINDOW"),
+			ReactionWindow: viper.GetDuration("COUNTERS_REACTION_WINDOW"),
+			MaxClaps:       viper.GetInt("COUNTERS_MAX_CLAPS"),
+			FlushEnabled:   viper.GetBool("COUNTERS_FLUSH_ENABLED"),
+			FlushInterval:  viper.GetDuration("COUNTERS_FLUSH_INTERVAL"),
+		},
+		Analytics: AnalyticsConfig{
+			ConsumerEnabled: viper.GetBool("ANALYTICS_CONSUMER_ENABLED"),
+		},
+		Outbox: OutboxConfig{
+			RelayEnabled:  viper.GetBool("OUTBOX_RELAY_ENABLED"),
+			RelayInterval: viper.GetDuration("OUTBOX_RELAY_INTERVAL"),
+			MaxBackoff:    viper.GetDuration("OUTBOX_MAX_BACKOFF"),
+			MaxAttempts:   viper.GetInt("OUTBOX_MAX_ATTEMPTS"),
+			Retention:     viper.GetDuration("OUTBOX_RETENTION"),
+		},
+		Github: GithubConfig{
+			SyncEnabled:      viper.GetBool("GITHUB_SYNC_ENABLED"),
+			SyncInterval:     viper.GetDuration("GITHUB_SYNC_INTERVAL"),
+			APIURL:           getEnv("GITHUB_API_URL", "https://api.github.com"),
+			Token:            getEnv("GITHUB_TOKEN", ""),
+			SyncTechnologies: viper.GetBool("GITHUB_SYNC_TECHNOLOGIES"),
+		},
+		Resume: ResumeConfig{
+			DefaultTheme: getEnv("RESUME_DEFAULT_THEME", "classic"),
+		},
+		Locales: getLocales(),
+		Tenants: TenantsConfig{
+			PathPrefix: strings.TrimSuffix(getEnv("TENANT_PATH_PREFIX", "/t"), "/"),
+			Fallback:   getTenantFallback(),
+		},
+		Worker: WorkerConfig{
+			MetricsPort:      getEnv("WORKER_METRICS_PORT", "9091"),
+			MaxAttempts:      viper.GetInt("CONSUMER_MAX_ATTEMPTS"),
+			MinBackoff:       viper.GetDuration("CONSUMER_MIN_BACKOFF"),
+			MaxBackoff:       viper.GetDuration("CONSUMER_MAX_BACKOFF"),
+			DedupTTL:         viper.GetDuration("CONSUMER_DEDUP_TTL"),
+			NotifyWebhookURL: getEnv("NOTIFY_WEBHOOK_URL", ""),
+		},
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 		Seeder: SeederConfig{
+			Tenant:            getEnv("SEED_TENANT", "default"),
 			PortfolioName:     getEnv("PORTFOLIO_NAME", "Muhsin Kılıç"),
 			PortfolioTitle:    getEnv("PORTFOLIO_TITLE", "Fullstack Developer"),
 			PortfolioEmail:    getEnv("PORTFOLIO_EMAIL", "kmuhsinn@gmail.com"),
@@ -135,6 +378,64 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getTenantFallback reads TENANT_FALLBACK, which unlike other settings may
+// be set empty: that turns the fallback off
+func getTenantFallback() string {
+	if value, ok := os.LookupEnv("TENANT_FALLBACK"); ok {
+		return strings.TrimSpace(value)
+	}
+	return "default"
+}
+
+// getList parses a comma-separated list, skipping empty entries
+func getList(key string) []string {
+	var values []string
+	for _, field := range strings.Split(os.Getenv(key), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			values = append(values, field)
+		}
+	}
+	return values
+}
+
+// getIntList parses a comma-separated list of positive integers, sorted
+// ascending. Entries that don't parse are skipped.
+func getIntList(key, defaultValue string) []int {
+	var values []int
+	for _, field := range strings.Split(getEnv(key, defaultValue), ",") {
+		if v, err := strconv.Atoi(strings.TrimSpace(field)); err == nil && v > 0 {
+			values = append(values, v)
+		}
+	}
+	sort.Ints(values)
+	return values
+}
+
+// getLocales reads DEFAULT_LOCALE, which falls back to the seeded portfolio
+// language, and the SUPPORTED_LOCALES it is added to. Tags are canonical.
+func getLocales() LocalesConfig {
+	defaultLocale := locale.Canonical(getEnv("DEFAULT_LOCALE", getEnv("PORTFOLIO_LANGUAGE", "en")))
+	if defaultLocale == "" {
+		defaultLocale = "en"
+	}
+
+	cfg := LocalesConfig{Default: defaultLocale, Supported: []string{defaultLocale}}
+	for _, field := range strings.Split(getEnv("SUPPORTED_LOCALES", ""), ",") {
+		tag := locale.Canonical(field)
+		if tag == "" {
+			continue
+		}
+		seen := false
+		for _, s := range cfg.Supported {
+			seen = seen || s == tag
+		}
+		if !seen {
+			cfg.Supported = append(cfg.Supported, tag)
+		}
+	}
+	return cfg
+}
+
 func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode)
@@ -0,0 +1,115 @@
+// Package events holds the handlers cmd/worker runs for domain events.
+package events
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/service"
+	"github.com/portfolio/backend/pkg/feed"
+	"go.uber.org/zap"
+)
+
+// CacheWarmer rebuilds the caches a content change invalidates, so the
+// first visitor after an edit doesn't pay for rendering them
+type CacheWarmer struct {
+	articles service.ArticleService
+	feeds    service.FeedService
+	sitemap  service.SitemapService
+	resume   service.ResumeExportService
+	logger   *zap.Logger
+}
+
+func NewCacheWarmer(articles service.ArticleService, feeds service.FeedService, sitemap service.SitemapService, resume service.ResumeExportService, logger *zap.Logger) *CacheWarmer {
+	return &CacheWarmer{
+		articles: articles,
+		feeds:    feeds,
+		sitemap:  sitemap,
+		resume:   resume,
+		logger:   logger,
+	}
+}
+
+// articleEvent is the part of an article event payload the warmer reads
+type articleEvent struct {
+	ID        uuid.UUID `json:"id"`
+	Published bool      `json:"published"`
+}
+
+func (w *CacheWarmer) Register(c *kafka.Consumer) {
+	kafka.Handle(c, kafka.EventArticleCreated, w.warmArticle)
+	kafka.Handle(c, kafka.EventArticleUpdated, w.warmArticle)
+	kafka.Handle(c, kafka.EventArticleDeleted, func(ctx context.Context, _ *kafka.ReceivedEvent, _ map[string]string) error {
+		return w.warmListings(ctx)
+	})
+	for _, eventType := range []string{kafka.EventProjectCreated, kafka.EventProjectUpdated, kafka.EventProjectDeleted} {
+		c.HandleFunc(eventType, func(ctx context.Context, _ *kafka.ReceivedEvent) error {
+			if _, err := w.sitemap.GetSitemap(ctx); err != nil {
+				return err
+			}
+			return w.warmResume(ctx)
+		})
+	}
+	// Featured projects are listed in the resume in their curated order
+	c.HandleFunc(kafka.EventProjectsReordered, func(ctx context.Context, _ *kafka.ReceivedEvent) error {
+		return w.warmResume(ctx)
+	})
+	// Translations add hreflang alternates to the feeds and the sitemap
+	for _, eventType := range []string{kafka.EventTranslationUpdated, kafka.EventTranslationDeleted} {
+		c.HandleFunc(eventType, func(ctx context.Context, _ *kafka.ReceivedEvent) error {
+			return w.warmListings(ctx)
+		})
+	}
+	for _, eventType := range []string{kafka.EventPortfolioUpdated, kafka.EventResumeUpdated} {
+		c.HandleFunc(eventType, func(ctx context.Context, _ *kafka.ReceivedEvent) error {
+			return w.warmResume(ctx)
+		})
+	}
+}
+
+func (w *CacheWarmer) warmArticle(ctx context.Context, _ *kafka.ReceivedEvent, article articleEvent) error {
+	if !article.Published {
+		// Drafts aren't in any public listing, but unpublishing one takes
+		// it out of them
+		return w.warmListings(ctx)
+	}
+
+	if _, err := w.articles.GetArticleByID(ctx, article.ID.String(), ""); err != nil {
+		// Deleted since the event was published; its own event follows
+		if !errors.Is(err, repository.ErrArticleNotFound) {
+			return err
+		}
+	}
+	return w.warmListings(ctx)
+}
+
+// warmListings renders the untagged feeds in the default locale and the
+// sitemap
+func (w *CacheWarmer) warmListings(ctx context.Context) error {
+	for _, format := range []string{feed.FormatRSS, feed.FormatAtom, feed.FormatJSON} {
+		if _, err := w.feeds.GetFeed(ctx, format, "", ""); err != nil {
+			return err
+		}
+	}
+	_, err := w.sitemap.GetSitemap(ctx)
+	return err
+}
+
+// warmResume renders the JSON resume and the PDF in every theme
+func (w *CacheWarmer) warmResume(ctx context.Context) error {
+	if _, err := w.resume.GetJSONResume(ctx); err != nil {
+		// Nothing to render until a portfolio is saved
+		if errors.Is(err, repository.ErrPortfolioNotFound) {
+			return nil
+		}
+		return err
+	}
+	for _, theme := range w.resume.Themes() {
+		if _, err := w.resume.GetPDF(ctx, theme); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"go.uber.org/zap"
+)
+
+// CommentNotifier tells the site owner about comments waiting for
+// moderation. With a webhook URL it posts {"text": "..."}, which Slack,
+// Mattermost and most chat webhooks accept; without one it only logs.
+type CommentNotifier struct {
+	webhookURL string
+	client     *http.Client
+	logger     *zap.Logger
+}
+
+func NewCommentNotifier(webhookURL string, logger *zap.Logger) *CommentNotifier {
+	return &CommentNotifier{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		logger:     logger,
+	}
+}
+
+func (n *CommentNotifier) Register(c *kafka.Consumer) {
+	kafka.Handle(c, kafka.EventCommentCreated, n.notify)
+}
+
+func (n *CommentNotifier) notify(ctx context.Context, event *kafka.ReceivedEvent, comment model.Comment) error {
+	// Comments filed as spam on arrival don't need anyone's attention
+	if comment.Status != model.CommentStatusPending {
+		return nil
+	}
+
+	if n.webhookURL == "" {
+		n.logger.Info("Comment awaiting moderation",
+			zap.String("comment_id", comment.ID.String()),
+			zap.String("article_id", comment.ArticleID.String()),
+		)
+		return nil
+	}
+
+	text := fmt.Sprintf("New comment from %s awaiting moderation: %q", comment.AuthorName, excerpt(comment.Content, 200))
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return kafka.Permanent(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return kafka.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", event.EventID)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// excerpt cuts s to at most n runes
+func excerpt(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
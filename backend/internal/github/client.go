@@ -0,0 +1,206 @@
+// Package github reads repository metadata from the GitHub REST API.
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://api.github.com"
+
+var ErrNotFound = errors.New("github repository not found")
+
+// RateLimitError is returned while GitHub refuses requests; they may be
+// retried from Reset on
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("github rate limit exceeded until %s", e.Reset.Format(time.RFC3339))
+}
+
+// RateLimit is the request budget GitHub reported with a response
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Response describes a conditional request. When NotModified is set the
+// resource still matches the ETag that was sent and no result is returned.
+type Response struct {
+	ETag        string
+	NotModified bool
+	Rate        RateLimit
+}
+
+type Repository struct {
+	FullName string     `json:"full_name"`
+	HTMLURL  string     `json:"html_url"`
+	Stars    int        `json:"stargazers_count"`
+	Forks    int        `json:"forks_count"`
+	Language string     `json:"language"`
+	License  *License   `json:"license"`
+	Topics   []string   `json:"topics"`
+	Archived bool       `json:"archived"`
+	PushedAt *time.Time `json:"pushed_at"`
+}
+
+type License struct {
+	Key    string `json:"key"`
+	SPDXID string `json:"spdx_id"`
+	Name   string `json:"name"`
+}
+
+type Release struct {
+	TagName     string     `json:"tag_name"`
+	Name        string     `json:"name"`
+	HTMLURL     string     `json:"html_url"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+// Client fetches repository metadata. Every call takes the ETag of the
+// last response for the same resource, or "" for an unconditional request.
+type Client interface {
+	GetRepository(ctx context.Context, owner, name, etag string) (*Repository, *Response, error)
+	// GetLanguages returns bytes of code per language
+	GetLanguages(ctx context.Context, owner, name, etag string) (map[string]int64, *Response, error)
+	// GetLatestRelease returns a nil release when the repository has none
+	GetLatestRelease(ctx context.Context, owner, name, etag string) (*Release, *Response, error)
+}
+
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient returns a client for the API at baseURL, which defaults to
+// api.github.com. Without a token requests are anonymous and get a much
+// smaller rate limit.
+func NewClient(baseURL, token string) Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (c *client) GetRepository(ctx context.Context, owner, name, etag string) (*Repository, *Response, error) {
+	var repo Repository
+	resp, found, err := c.get(ctx, fmt.Sprintf("/repos/%s/%s", owner, name), etag, &repo)
+	if err != nil || resp.NotModified {
+		return nil, resp, err
+	}
+	if !found {
+		return nil, resp, ErrNotFound
+	}
+	return &repo, resp, nil
+}
+
+func (c *client) GetLanguages(ctx context.Context, owner, name, etag string) (map[string]int64, *Response, error) {
+	var languages map[string]int64
+	resp, found, err := c.get(ctx, fmt.Sprintf("/repos/%s/%s/languages", owner, name), etag, &languages)
+	if err != nil || resp.NotModified {
+		return nil, resp, err
+	}
+	if !found {
+		return nil, resp, ErrNotFound
+	}
+	return languages, resp, nil
+}
+
+func (c *client) GetLatestRelease(ctx context.Context, owner, name, etag string) (*Release, *Response, error) {
+	var release Release
+	resp, found, err := c.get(ctx, fmt.Sprintf("/repos/%s/%s/releases/latest", owner, name), etag, &release)
+	if err != nil || resp.NotModified || !found {
+		return nil, resp, err
+	}
+	return &release, resp, nil
+}
+
+// get makes a conditional GET and decodes a 200 body into out. It reports
+// found=false for a 404.
+func (c *client) get(ctx context.Context, path, etag string, out interface{}) (*Response, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", "portfolio-backend")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer res.Body.Close()
+
+	resp := &Response{
+		ETag: res.Header.Get("ETag"),
+		Rate: parseRateLimit(res.Header),
+	}
+	switch {
+	case res.StatusCode == http.StatusNotModified:
+		resp.ETag = etag
+		resp.NotModified = true
+		return resp, true, nil
+	case res.StatusCode == http.StatusNotFound:
+		return resp, false, nil
+	case res.StatusCode == http.StatusTooManyRequests,
+		res.StatusCode == http.StatusForbidden && (resp.Rate.Remaining == 0 || res.Header.Get("Retry-After") != ""):
+		return resp, false, &RateLimitError{Reset: retryAt(res.Header, resp.Rate)}
+	case res.StatusCode != http.StatusOK:
+		return resp, false, fmt.Errorf("github %s returned %s", path, res.Status)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return resp, false, fmt.Errorf("decode github %s: %w", path, err)
+	}
+	return resp, true, nil
+}
+
+// parseRateLimit reads the X-RateLimit headers. Responses without them
+// (such as from a stub server) report an unlimited budget.
+func parseRateLimit(h http.Header) RateLimit {
+	rate := RateLimit{Limit: -1, Remaining: -1}
+	if v, err := strconv.Atoi(h.Get("X-RateLimit-Limit")); err == nil {
+		rate.Limit = v
+	}
+	if v, err := strconv.Atoi(h.Get("X-RateLimit-Remaining")); err == nil {
+		rate.Remaining = v
+	}
+	if v, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rate.Reset = time.Unix(v, 0)
+	}
+	return rate
+}
+
+// retryAt is when a rate limited request may be retried: Retry-After for
+// secondary limits, the reset of the primary limit otherwise, or a minute
+// from now when GitHub gave neither
+func retryAt(h http.Header, rate RateLimit) time.Time {
+	if seconds, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if !rate.Reset.IsZero() {
+		return rate.Reset
+	}
+	return time.Now().Add(time.Minute)
+}
@@ -0,0 +1,238 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// stubServer serves a fake GitHub API for octo/widget, answering 304 to
+// requests carrying the current ETag, plus failing repositories under octo
+func stubServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/repos/octo/widget", func(w http.ResponseWriter, r *http.Request) {
+		if want := "Bearer " + token; token != "" && r.Header.Get("Authorization") != want {
+			t.Errorf("Authorization = %q, want %q", r.Header.Get("Authorization"), want)
+		}
+		if r.Header.Get("Accept") != "application/vnd.github+json" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		if r.Header.Get("If-None-Match") == `"repo-v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"repo-v1"`)
+		w.Write([]byte(`{
+			"full_name": "octo/widget",
+			"html_url": "https://github.com/octo/widget",
+			"stargazers_count": 42,
+			"forks_count": 7,
+			"language": "Go",
+			"license": {"key": "mit", "spdx_id": "MIT", "name": "MIT License"},
+			"topics": ["cli", "tools"],
+			"archived": true,
+			"pushed_at": "2024-05-01T12:00:00Z"
+		}`))
+	})
+
+	mux.HandleFunc("/repos/octo/widget/languages", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"langs-v1"`)
+		w.Write([]byte(`{"Go": 9000, "Shell": 100}`))
+	})
+
+	mux.HandleFunc("/repos/octo/widget/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"release-v1"`)
+		w.Write([]byte(`{"tag_name": "v1.2.0", "name": "1.2", "html_url": "https://github.com/octo/widget/releases/v1.2.0", "published_at": "2024-04-01T00:00:00Z"}`))
+	})
+
+	mux.HandleFunc("/repos/octo/limited", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	mux.HandleFunc("/repos/octo/throttled", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	mux.HandleFunc("/repos/octo/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetRepository(t *testing.T) {
+	server := stubServer(t, "secret")
+	c := NewClient(server.URL+"/", "secret")
+
+	repo, resp, err := c.GetRepository(context.Background(), "octo", "widget", "")
+	if err != nil {
+		t.Fatalf("GetRepository: %v", err)
+	}
+	if resp.NotModified {
+		t.Error("NotModified on an unconditional request")
+	}
+	if resp.ETag != `"repo-v1"` {
+		t.Errorf("ETag = %q", resp.ETag)
+	}
+	if resp.Rate.Limit != 5000 || resp.Rate.Remaining != 4999 || !resp.Rate.Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Rate = %+v", resp.Rate)
+	}
+	if repo.Stars != 42 || repo.Forks != 7 || !repo.Archived {
+		t.Errorf("repo = %+v", repo)
+	}
+	if repo.License == nil || repo.License.SPDXID != "MIT" {
+		t.Errorf("License = %+v", repo.License)
+	}
+	if len(repo.Topics) != 2 || repo.Topics[0] != "cli" {
+		t.Errorf("Topics = %v", repo.Topics)
+	}
+	if repo.PushedAt == nil || !repo.PushedAt.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("PushedAt = %v", repo.PushedAt)
+	}
+}
+
+func TestGetRepositoryNotModified(t *testing.T) {
+	server := stubServer(t, "")
+	c := NewClient(server.URL, "")
+
+	repo, resp, err := c.GetRepository(context.Background(), "octo", "widget", `"repo-v1"`)
+	if err != nil {
+		t.Fatalf("GetRepository: %v", err)
+	}
+	if !resp.NotModified || repo != nil {
+		t.Errorf("got repo %+v, NotModified %v; want nil and true", repo, resp.NotModified)
+	}
+	// A 304 carries no ETag of its own; the one sent still applies
+	if resp.ETag != `"repo-v1"` {
+		t.Errorf("ETag = %q", resp.ETag)
+	}
+}
+
+func TestGetRepositoryNotFound(t *testing.T) {
+	server := stubServer(t, "")
+	c := NewClient(server.URL, "")
+
+	if _, _, err := c.GetRepository(context.Background(), "octo", "missing", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+	if _, _, err := c.GetLanguages(context.Background(), "octo", "missing", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetLanguages err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetLanguages(t *testing.T) {
+	server := stubServer(t, "")
+	c := NewClient(server.URL, "")
+
+	languages, resp, err := c.GetLanguages(context.Background(), "octo", "widget", "")
+	if err != nil {
+		t.Fatalf("GetLanguages: %v", err)
+	}
+	if languages["Go"] != 9000 || languages["Shell"] != 100 || len(languages) != 2 {
+		t.Errorf("languages = %v", languages)
+	}
+	if resp.ETag != `"langs-v1"` {
+		t.Errorf("ETag = %q", resp.ETag)
+	}
+	// The stub sends no rate limit headers
+	if resp.Rate.Remaining != -1 {
+		t.Errorf("Remaining = %d, want -1", resp.Rate.Remaining)
+	}
+}
+
+func TestGetLatestRelease(t *testing.T) {
+	server := stubServer(t, "")
+	c := NewClient(server.URL, "")
+
+	release, _, err := c.GetLatestRelease(context.Background(), "octo", "widget", "")
+	if err != nil {
+		t.Fatalf("GetLatestRelease: %v", err)
+	}
+	if release.TagName != "v1.2.0" || release.HTMLURL != "https://github.com/octo/widget/releases/v1.2.0" {
+		t.Errorf("release = %+v", release)
+	}
+
+	// A repository without releases is not an error
+	release, _, err = c.GetLatestRelease(context.Background(), "octo", "bare", "")
+	if err != nil || release != nil {
+		t.Errorf("got %+v, %v; want nil, nil", release, err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := stubServer(t, "")
+	c := NewClient(server.URL, "")
+
+	var limited *RateLimitError
+	_, _, err := c.GetRepository(context.Background(), "octo", "limited", "")
+	if !errors.As(err, &limited) {
+		t.Fatalf("err = %v, want *RateLimitError", err)
+	}
+	if !limited.Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Reset = %v", limited.Reset)
+	}
+
+	before := time.Now()
+	_, _, err = c.GetRepository(context.Background(), "octo", "throttled", "")
+	if !errors.As(err, &limited) {
+		t.Fatalf("err = %v, want *RateLimitError", err)
+	}
+	if wait := limited.Reset.Sub(before); wait < 59*time.Second || wait > 61*time.Second {
+		t.Errorf("Retry-After 60 gave a wait of %v", wait)
+	}
+}
+
+func TestUnexpectedStatus(t *testing.T) {
+	server := stubServer(t, "")
+	c := NewClient(server.URL, "")
+
+	_, _, err := c.GetRepository(context.Background(), "octo", "broken", "")
+	var limited *RateLimitError
+	if err == nil || errors.Is(err, ErrNotFound) || errors.As(err, &limited) {
+		t.Errorf("err = %v, want a plain error", err)
+	}
+}
+
+func TestParseRepoURL(t *testing.T) {
+	tests := []struct {
+		raw         string
+		owner, name string
+		wantErr     bool
+	}{
+		{raw: "https://github.com/octo/widget", owner: "octo", name: "widget"},
+		{raw: "github.com/octo/widget.git", owner: "octo", name: "widget"},
+		{raw: "https://www.github.com/octo/widget/tree/main/cmd", owner: "octo", name: "widget"},
+		{raw: "git@github.com:octo/widget.git", owner: "octo", name: "widget"},
+		{raw: "  https://GitHub.com/Octo/Widget/  ", owner: "Octo", name: "Widget"},
+		{raw: "https://gitlab.com/octo/widget", wantErr: true},
+		{raw: "https://github.com/octo", wantErr: true},
+		{raw: "https://github.com/-octo/widget", wantErr: true},
+		{raw: "https://github.com/octo/..", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		owner, name, err := ParseRepoURL(tt.raw)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidURL) {
+				t.Errorf("ParseRepoURL(%q) err = %v, want ErrInvalidURL", tt.raw, err)
+			}
+			continue
+		}
+		if err != nil || owner != tt.owner || name != tt.name {
+			t.Errorf("ParseRepoURL(%q) = %q, %q, %v; want %q, %q", tt.raw, owner, name, err, tt.owner, tt.name)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package github
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidURL = errors.New("not a GitHub repository URL")
+
+// Owner and repository names GitHub allows
+var (
+	ownerPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,38})$`)
+	namePattern  = regexp.MustCompile(`^[A-Za-z0-9._-]{1,100}$`)
+)
+
+// ParseRepoURL extracts the owner and repository name from a GitHub URL.
+// It accepts web URLs with or without a scheme, including links deeper
+// into the repository such as /tree/main, and SSH clone URLs.
+func ParseRepoURL(raw string) (owner, name string, err error) {
+	raw = strings.TrimSpace(raw)
+	if rest, ok := strings.CutPrefix(raw, "git@github.com:"); ok {
+		raw = "https://github.com/" + rest
+	} else if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", ErrInvalidURL
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host != "github.com" {
+		return "", "", ErrInvalidURL
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", "", ErrInvalidURL
+	}
+	owner, name = parts[0], strings.TrimSuffix(parts[1], ".git")
+	if !ownerPattern.MatchString(owner) || !namePattern.MatchString(name) || name == "." || name == ".." {
+		return "", "", ErrInvalidURL
+	}
+	return owner, name, nil
+}
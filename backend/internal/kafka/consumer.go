@@ -0,0 +1,407 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/tenant"
+	"go.uber.org/zap"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Dead-lettered messages go to the source topic with this suffix
+const DeadLetterSuffix = ".dlq"
+
+// ReceivedEvent is an Event read back from Kafka, its payload still encoded
+type ReceivedEvent struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Source    string          `json:"source"`
+	Version   string          `json:"version"`
+	TenantID  string          `json:"tenant_id,omitempty"`
+	Data      json.RawMessage `json:"data"`
+
+	Topic     string `json:"-"`
+	Partition int    `json:"-"`
+	Offset    int64  `json:"-"`
+}
+
+// HandlerFunc processes one event. Returning an error retries it with
+// backoff; wrap the error with Permanent to dead-letter it straight away.
+type HandlerFunc func(ctx context.Context, event *ReceivedEvent) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying won't fix, such as a payload that
+// doesn't decode
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// ConsumerConfig configures one consumer group
+type ConsumerConfig struct {
+	Brokers []string
+	GroupID string
+	Topics  []string
+	// Attempts per event before it is dead-lettered, including the first
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// Upper bound on one handler call
+	HandlerTimeout time.Duration
+	// Dedup, when set, remembers handled event IDs for DedupTTL so an event
+	// delivered twice, by a redelivery or a republish from the outbox,
+	// takes effect once
+	Dedup    DedupStore
+	DedupTTL time.Duration
+}
+
+// DedupStore records handled event IDs; cache.RedisCache implements it
+type DedupStore interface {
+	Exists(ctx context.Context, key string) (bool, error)
+	MarkSeen(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// Consumer reads events as part of a consumer group and dispatches them to
+// handlers registered per event type. Events within a partition are handled
+// one at a time, in order; an offset is committed only once its event has
+// been handled or dead-lettered.
+type Consumer struct {
+	config     ConsumerConfig
+	reader     *kafka.Reader
+	deadLetter *kafka.Writer
+	logger     *zap.Logger
+
+	mu       sync.RWMutex
+	handlers map[string][]HandlerFunc
+
+	processed    atomic.Int64
+	failed       atomic.Int64
+	retried      atomic.Int64
+	deadLettered atomic.Int64
+	skipped      atomic.Int64
+	duplicates   atomic.Int64
+
+	lagMu sync.Mutex
+	lag   map[string]int64 // Per "topic/partition"
+}
+
+func NewConsumer(config ConsumerConfig, logger *zap.Logger) *Consumer {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 5
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = time.Second
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = time.Minute
+	}
+	if config.HandlerTimeout <= 0 {
+		config.HandlerTimeout = 30 * time.Second
+	}
+	if config.DedupTTL <= 0 {
+		config.DedupTTL = 7 * 24 * time.Hour
+	}
+
+	return &Consumer{
+		config: config,
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     config.Brokers,
+			GroupID:     config.GroupID,
+			GroupTopics: config.Topics,
+			MaxBytes:    10 << 20,
+		}),
+		deadLetter: &kafka.Writer{
+			Addr:     kafka.TCP(config.Brokers...),
+			Balancer: &kafka.Hash{},
+		},
+		logger:   logger.With(zap.String("group", config.GroupID)),
+		handlers: make(map[string][]HandlerFunc),
+		lag:      make(map[string]int64),
+	}
+}
+
+// HandleFunc registers a handler for an event type. Several handlers may
+// share a type; they run in registration order and an error from any of
+// them retries the event for all.
+func (c *Consumer) HandleFunc(eventType string, handler HandlerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[eventType] = append(c.handlers[eventType], handler)
+}
+
+// Handle registers a handler that receives the event payload decoded as T.
+// Payloads that don't decode are dead-lettered without retrying.
+func Handle[T any](c *Consumer, eventType string, handler func(ctx context.Context, event *ReceivedEvent, data T) error) {
+	c.HandleFunc(eventType, func(ctx context.Context, event *ReceivedEvent) error {
+		var data T
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return Permanent(fmt.Errorf("decode %s payload: %w", event.EventType, err))
+		}
+		return handler(ctx, event, data)
+	})
+}
+
+// Run consumes until ctx is cancelled. The event being handled when that
+// happens is finished first; one still waiting to retry is left uncommitted
+// for the group to redeliver.
+func (c *Consumer) Run(ctx context.Context) {
+	c.logger.Info("Kafka consumer started", zap.Strings("topics", c.config.Topics))
+	defer func() {
+		if err := c.reader.Close(); err != nil {
+			c.logger.Error("Failed to close Kafka reader", zap.Error(err))
+		}
+		if err := c.deadLetter.Close(); err != nil {
+			c.logger.Error("Failed to close dead-letter writer", zap.Error(err))
+		}
+		c.logger.Info("Kafka consumer stopped")
+	}()
+
+	for ctx.Err() == nil {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Error("Failed to fetch message", zap.Error(err))
+				sleep(ctx, c.config.MinBackoff)
+			}
+			continue
+		}
+		c.recordLag(msg)
+
+		if !c.dispatch(ctx, msg) {
+			return
+		}
+		if err := c.reader.CommitMessages(context.Background(), msg); err != nil {
+			c.logger.Error("Failed to commit offset", zap.String("topic", msg.Topic), zap.Int64("offset", msg.Offset), zap.Error(err))
+		}
+	}
+}
+
+// dispatch handles msg, retrying and dead-lettering as needed. It reports
+// false if shutdown interrupted it before the message was settled.
+func (c *Consumer) dispatch(ctx context.Context, msg kafka.Message) bool {
+	var event ReceivedEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil || event.EventType == "" {
+		if err == nil {
+			err = errors.New("missing event_type")
+		}
+		return c.sendToDeadLetter(ctx, msg, Permanent(fmt.Errorf("decode envelope: %w", err)), 0)
+	}
+	event.Topic = msg.Topic
+	event.Partition = msg.Partition
+	event.Offset = msg.Offset
+
+	c.mu.RLock()
+	handlers := c.handlers[event.EventType]
+	c.mu.RUnlock()
+	if len(handlers) == 0 {
+		c.skipped.Add(1)
+		return true
+	}
+	if c.isDuplicate(&event) {
+		c.duplicates.Add(1)
+		return true
+	}
+
+	backoff := c.config.MinBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.handle(handlers, &event)
+		if err == nil {
+			c.processed.Add(1)
+			c.markHandled(&event)
+			return true
+		}
+		c.failed.Add(1)
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= c.config.MaxAttempts {
+			return c.sendToDeadLetter(ctx, msg, err, attempt)
+		}
+
+		c.logger.Warn("Event handler failed, retrying",
+			zap.String("event_type", event.EventType),
+			zap.String("event_id", event.EventID),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		c.retried.Add(1)
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		if backoff *= 2; backoff > c.config.MaxBackoff {
+			backoff = c.config.MaxBackoff
+		}
+	}
+}
+
+func (c *Consumer) dedupKey(event *ReceivedEvent) string {
+	return fmt.Sprintf("events:processed:%s:%s", c.config.GroupID, event.EventID)
+}
+
+// isDuplicate reports whether the group has already handled the event. If
+// the store can't be reached the event is handled again rather than lost.
+func (c *Consumer) isDuplicate(event *ReceivedEvent) bool {
+	if c.config.Dedup == nil || event.EventID == "" {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.HandlerTimeout)
+	defer cancel()
+	seen, err := c.config.Dedup.Exists(ctx, c.dedupKey(event))
+	if err != nil {
+		c.logger.Warn("Failed to check event dedup store", zap.String("event_id", event.EventID), zap.Error(err))
+		return false
+	}
+	return seen
+}
+
+func (c *Consumer) markHandled(event *ReceivedEvent) {
+	if c.config.Dedup == nil || event.EventID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.HandlerTimeout)
+	defer cancel()
+	if _, err := c.config.Dedup.MarkSeen(ctx, c.dedupKey(event), c.config.DedupTTL); err != nil {
+		c.logger.Warn("Failed to record handled event", zap.String("event_id", event.EventID), zap.Error(err))
+	}
+}
+
+// handle runs every handler for the event on a context that survives
+// shutdown, so a handler is never cut off halfway. The context is scoped to
+// the event's tenant, if it has one.
+func (c *Consumer) handle(handlers []HandlerFunc, event *ReceivedEvent) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.HandlerTimeout)
+	defer cancel()
+	if id, parseErr := uuid.Parse(event.TenantID); parseErr == nil {
+		ctx = tenant.NewContext(ctx, id)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendToDeadLetter copies msg to the dead-letter topic with headers saying
+// where it came from and why it failed. Publishing is retried until it
+// succeeds or shutdown begins.
+func (c *Consumer) sendToDeadLetter(ctx context.Context, msg kafka.Message, cause error, attempts int) bool {
+	headers := append([]kafka.Header{}, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: "dlq-original-topic", Value: []byte(msg.Topic)},
+		kafka.Header{Key: "dlq-original-partition", Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: "dlq-original-offset", Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kafka.Header{Key: "dlq-consumer-group", Value: []byte(c.config.GroupID)},
+		kafka.Header{Key: "dlq-error", Value: []byte(cause.Error())},
+		kafka.Header{Key: "dlq-attempts", Value: []byte(strconv.Itoa(attempts))},
+	)
+	dead := kafka.Message{
+		Topic:   msg.Topic + DeadLetterSuffix,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}
+
+	backoff := c.config.MinBackoff
+	for {
+		err := c.deadLetter.WriteMessages(context.Background(), dead)
+		if err == nil {
+			break
+		}
+		c.logger.Error("Failed to publish to dead-letter topic", zap.String("topic", dead.Topic), zap.Error(err))
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		if backoff *= 2; backoff > c.config.MaxBackoff {
+			backoff = c.config.MaxBackoff
+		}
+	}
+
+	c.deadLettered.Add(1)
+	c.logger.Error("Event dead-lettered",
+		zap.String("topic", msg.Topic),
+		zap.Int("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.Int("attempts", attempts),
+		zap.Error(cause),
+	)
+	return true
+}
+
+// recordLag notes how far msg's partition is behind its newest offset
+func (c *Consumer) recordLag(msg kafka.Message) {
+	lag := msg.HighWaterMark - msg.Offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+	c.lagMu.Lock()
+	c.lag[fmt.Sprintf("%s/%d", msg.Topic, msg.Partition)] = lag
+	c.lagMu.Unlock()
+}
+
+// ConsumerStats are a consumer's counters since it started, plus its
+// current lag
+type ConsumerStats struct {
+	GroupID      string
+	Topics       []string
+	Lag          int64 // Messages behind the newest offsets, summed over partitions as of their last fetch
+	Processed    int64
+	Failed       int64 // Failed handler attempts, including retried ones
+	Retried      int64
+	DeadLettered int64
+	Skipped      int64 // Events with no registered handler
+	Duplicates   int64 // Events dropped because the group had already handled them
+}
+
+func (c *Consumer) Stats() ConsumerStats {
+	var lag int64
+	c.lagMu.Lock()
+	for _, l := range c.lag {
+		lag += l
+	}
+	c.lagMu.Unlock()
+
+	return ConsumerStats{
+		GroupID:      c.config.GroupID,
+		Topics:       c.config.Topics,
+		Lag:          lag,
+		Processed:    c.processed.Load(),
+		Failed:       c.failed.Load(),
+		Retried:      c.retried.Load(),
+		DeadLettered: c.deadLettered.Load(),
+		Skipped:      c.skipped.Load(),
+		Duplicates:   c.duplicates.Load(),
+	}
+}
+
+// sleep waits for d, returning false early if ctx is cancelled
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,41 @@
+package kafka
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMetrics writes consumer stats in the Prometheus text format
+func WriteMetrics(w io.Writer, consumers ...*Consumer) error {
+	metrics := []struct {
+		name, help, kind string
+		value            func(ConsumerStats) int64
+	}{
+		{"kafka_consumer_lag", "Messages behind the newest offset, summed over assigned partitions.", "gauge", func(s ConsumerStats) int64 { return s.Lag }},
+		{"kafka_consumer_events_processed_total", "Events handled successfully.", "counter", func(s ConsumerStats) int64 { return s.Processed }},
+		{"kafka_consumer_handler_failures_total", "Failed handler attempts, including those retried.", "counter", func(s ConsumerStats) int64 { return s.Failed }},
+		{"kafka_consumer_retries_total", "Handler attempts retried after a failure.", "counter", func(s ConsumerStats) int64 { return s.Retried }},
+		{"kafka_consumer_dead_lettered_total", "Events sent to a dead-letter topic.", "counter", func(s ConsumerStats) int64 { return s.DeadLettered }},
+		{"kafka_consumer_events_skipped_total", "Events with no registered handler.", "counter", func(s ConsumerStats) int64 { return s.Skipped }},
+		{"kafka_consumer_events_duplicate_total", "Events dropped because the group had already handled their event ID.", "counter", func(s ConsumerStats) int64 { return s.Duplicates }},
+	}
+
+	stats := make([]ConsumerStats, len(consumers))
+	for i, c := range consumers {
+		stats[i] = c.Stats()
+	}
+
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+		for _, s := range stats {
+			_, err := fmt.Fprintf(w, "%s{group=%q,topics=%q} %d\n", m.name, s.GroupID, strings.Join(s.Topics, ","), m.value(s))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
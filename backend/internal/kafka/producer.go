@@ -3,8 +3,12 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"net"
 	"time"
 	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/tenant"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -14,74 +18,174 @@ type Event struct {
 	Timestamp time.Time   `json:"timestamp"`
 	Source    string      `json:"source"`
 	Version   string      `json:"version"`
+	TenantID  string      `json:"tenant_id,omitempty"` // The tenant the event happened in
 	Data      interface{} `json:"data"`
 }
 
+// Topics events are published to
+const (
+	TopicArticles  = "portfolio.articles"
+	TopicProjects  = "portfolio.projects"
+	TopicTags      = "portfolio.tags"
+	TopicComments  = "portfolio.comments"
+	TopicAnalytics = "portfolio.analytics"
+	TopicResume    = "portfolio.resume"
+)
+
+// Event types, carried in Event.EventType and the event-type header
+const (
+	EventArticleCreated    = "article.created"
+	EventArticleUpdated    = "article.updated"
+	EventArticleDeleted    = "article.deleted"
+	EventArticlesReordered = "article.reordered"
+	EventProjectCreated    = "project.created"
+	EventProjectUpdated    = "project.updated"
+	EventProjectDeleted    = "project.deleted"
+	EventProjectsReordered = "project.reordered"
+	EventTagCreated        = "tag.created"
+	EventTagUpdated        = "tag.updated"
+	EventTagDeleted        = "tag.deleted"
+	EventCommentCreated    = "comment.created"
+	EventPageView          = "analytics.pageview"
+	EventPortfolioUpdated  = "portfolio.updated"
+	EventResumeUpdated     = "resume.updated"
+	// Published on the topic of the translated article, project or portfolio
+	EventTranslationUpdated = "translation.updated"
+	EventTranslationDeleted = "translation.deleted"
+)
+
+// NewEvent wraps data in the standard envelope under a fresh event ID,
+// stamped with the tenant ctx is scoped to. Consumers deduplicate on the ID,
+// so a redelivered event takes effect once.
+func NewEvent(ctx context.Context, eventType string, data interface{}) Event {
+	event := Event{
+		EventID:   uuid.New().String(),
+		EventType: eventType,
+		Timestamp: time.Now(),
+		Source:    "backend",
+		Version:   "1.0",
+		Data:      data,
+	}
+	if id, ok := tenant.FromContext(ctx); ok {
+		event.TenantID = id.String()
+	}
+	return event
+}
+
+// Message is an event that has already been encoded, as the outbox stores it
+type Message struct {
+	Topic     string
+	EventType string
+	Key       string
+	TenantID  string // Repeated in the tenant-id header
+	Value     []byte
+}
+
 type Producer struct {
 	writer *kafka.Writer
+	// async batches high-volume events in the background so publishing them
+	// never holds up a request; delivery failures are dropped
+	async *kafka.Writer
 }
 
 func NewProducer(brokers []string) *Producer {
 	return &Producer{
+		// Keyed messages hash to a fixed partition so each aggregate's
+		// events stay in order. Outbox rows are only marked sent once every
+		// replica has the message.
 		writer: &kafka.Writer{
-			Addr:     kafka.TCP(brokers...),
-			Balancer: &kafka.LeastBytes{},
+			Addr:         kafka.TCP(brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+		},
+		async: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Balancer:     &kafka.LeastBytes{},
+			Async:        true,
+			BatchTimeout: 100 * time.Millisecond,
 		},
 	}
 }
 
-func (p *Producer) PublishArticleCreated(ctx context.Context, article interface{}) error {
-	return p.publishEvent(ctx, "portfolio.articles", "article.created", article)
+func (p *Producer) PublishPageView(ctx context.Context, view interface{}) error {
+	return p.write(ctx, p.async, TopicAnalytics, EventPageView, view)
 }
 
-func (p *Producer) PublishArticleUpdated(ctx context.Context, article interface{}) error {
-	return p.publishEvent(ctx, "portfolio.articles", "article.updated", article)
-}
-
-func (p *Producer) PublishArticleDeleted(ctx context.Context, articleID string) error {
-	return p.publishEvent(ctx, "portfolio.articles", "article.deleted", map[string]string{"id": articleID})
-}
-
-func (p *Producer) PublishProjectCreated(ctx context.Context, project interface{}) error {
-	return p.publishEvent(ctx, "portfolio.projects", "project.created", project)
-}
+func (p *Producer) write(ctx context.Context, writer *kafka.Writer, topic, eventType string, data interface{}) error {
+	event := NewEvent(ctx, eventType, data)
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
 
-func (p *Producer) PublishProjectUpdated(ctx context.Context, project interface{}) error {
-	return p.publishEvent(ctx, "portfolio.projects", "project.updated", project)
+	return writer.WriteMessages(ctx, newMessage(Message{Topic: topic, EventType: eventType, TenantID: event.TenantID, Value: eventData}))
 }
 
-func (p *Producer) PublishProjectDeleted(ctx context.Context, projectID string) error {
-	return p.publishEvent(ctx, "portfolio.projects", "project.deleted", map[string]string{"id": projectID})
+// PublishMessages writes pre-encoded events in order and waits until Kafka
+// has acknowledged all of them
+func (p *Producer) PublishMessages(ctx context.Context, messages []Message) error {
+	msgs := make([]kafka.Message, len(messages))
+	for i, m := range messages {
+		msgs[i] = newMessage(m)
+	}
+	return p.writer.WriteMessages(ctx, msgs...)
 }
 
-func (p *Producer) publishEvent(ctx context.Context, topic, eventType string, data interface{}) error {
-	event := Event{
-		EventID:   uuid.New().String(),
-		EventType: eventType,
-		Timestamp: time.Now(),
-		Source:    "backend",
-		Version:   "1.0",
-		Data:      data,
+// Unavailable reports whether a failed publish looks like Kafka being down or
+// overloaded, rather than something wrong with the messages, which no number
+// of retries would get through
+func Unavailable(err error) bool {
+	var tooLarge kafka.MessageTooLargeError
+	if errors.As(err, &tooLarge) {
+		return false
 	}
-
-	eventData, err := json.Marshal(event)
-	if err != nil {
-		return err
+	// A write reports an error per message
+	var writeErrs kafka.WriteErrors
+	if errors.As(err, &writeErrs) {
+		for _, e := range writeErrs {
+			if e != nil && Unavailable(e) {
+				return true
+			}
+		}
+		return false
+	}
+	var kafkaErr kafka.Error
+	if errors.As(err, &kafkaErr) {
+		return kafkaErr.Temporary()
 	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.ErrClosedPipe)
+}
 
+func newMessage(m Message) kafka.Message {
 	msg := kafka.Message{
-		Topic: topic,
-		Value: eventData,
+		Topic: m.Topic,
+		Value: m.Value,
 		Headers: []kafka.Header{
-			{Key: "event-type", Value: []byte(eventType)},
+			{Key: "event-type", Value: []byte(m.EventType)},
 			{Key: "content-type", Value: []byte("application/json")},
 		},
 	}
-
-	return p.writer.WriteMessages(ctx, msg)
+	if m.Key != "" {
+		msg.Key = []byte(m.Key)
+	}
+	if m.TenantID != "" {
+		msg.Headers = append(msg.Headers, kafka.Header{Key: "tenant-id", Value: []byte(m.TenantID)})
+	}
+	return msg
 }
 
 func (p *Producer) Close() error {
-	return p.writer.Close()
+	asyncErr := p.async.Close()
+	if err := p.writer.Close(); err != nil {
+		return err
+	}
+	return asyncErr
 }
 
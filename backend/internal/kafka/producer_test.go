@@ -0,0 +1,36 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"dial", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"deadline", fmt.Errorf("write: %w", context.DeadlineExceeded), true},
+		{"connection dropped", io.ErrUnexpectedEOF, true},
+		{"writer closed", io.ErrClosedPipe, true},
+		{"no leader", kafka.LeaderNotAvailable, true},
+		{"some partitions without a leader", kafka.WriteErrors{nil, kafka.NotLeaderForPartition}, true},
+		{"too large for the writer", kafka.MessageTooLargeError{}, false},
+		{"too large for the broker", kafka.WriteErrors{kafka.MessageSizeTooLarge}, false},
+		{"not authorized", kafka.TopicAuthorizationFailed, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		if got := Unavailable(tt.err); got != tt.want {
+			t.Errorf("%s: Unavailable(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
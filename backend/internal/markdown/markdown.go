@@ -0,0 +1,163 @@
+// Package markdown renders article Markdown to sanitized HTML and extracts
+// the metadata shown alongside it (table of contents, word count, excerpt).
+package markdown
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"unicode/utf8"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	// Average adult silent reading speed
+	wordsPerMinute = 200
+
+	// Headings deeper than this are left out of the table of contents
+	maxTOCLevel = 4
+
+	// Generated excerpts are cut at a word boundary below this many runes
+	excerptLength = 200
+)
+
+// TOCEntry is one heading in the table of contents
+type TOCEntry struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// Result holds the rendered HTML and the metadata extracted while parsing
+type Result struct {
+	HTML               string
+	TOC                []TOCEntry
+	WordCount          int
+	ReadingTimeMinutes int
+	Excerpt            string
+}
+
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+// NewRenderer builds a GFM renderer whose code blocks are highlighted with
+// the given chroma style using inline styles, so clients need no stylesheet
+func NewRenderer(style string) *Renderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			highlighting.NewHighlighting(
+				highlighting.WithStyle(style),
+				highlighting.WithFormatOptions(html.WithClasses(false)),
+			),
+		),
+		// Raw HTML is passed through here and cleaned by the sanitizer below
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("tabindex").OnElements("pre")
+	policy.AllowStyles("color", "background-color", "font-weight", "font-style", "text-decoration", "display", "white-space").
+		OnElements("pre", "code", "span")
+	policy.AllowAttrs("type").Matching(bluemonday.SpaceSeparatedTokens).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+
+	return &Renderer{md: md, policy: policy}
+}
+
+func (r *Renderer) Render(source string) (*Result, error) {
+	src := []byte(source)
+	doc := r.md.Parser().Parse(text.NewReader(src))
+	// Heading IDs come from the heading's text rather than its source line,
+	// which would carry any inline HTML into the anchor
+	ids := parser.NewContext().IDs()
+
+	result := &Result{TOC: []TOCEntry{}}
+	var words int
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			heading := plainText(node, src)
+			id := ids.Generate([]byte(heading), ast.KindHeading)
+			node.SetAttributeString("id", id)
+			if node.Level <= maxTOCLevel {
+				result.TOC = append(result.TOC, TOCEntry{Level: node.Level, Text: heading, ID: string(id)})
+			}
+		case *ast.Paragraph:
+			if result.Excerpt == "" {
+				result.Excerpt = truncate(plainText(node, src), excerptLength)
+			}
+		case *ast.Text:
+			words += len(strings.Fields(string(node.Segment.Value(src))))
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+
+	result.HTML = r.policy.Sanitize(buf.String())
+	result.WordCount = words
+	result.ReadingTimeMinutes = ReadingTime(words)
+	return result, nil
+}
+
+// ReadingTime rounds up to whole minutes; any content takes at least one
+func ReadingTime(words int) int {
+	if words == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(words) / wordsPerMinute))
+}
+
+// plainText concatenates the text under n, dropping inline markup
+func plainText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(src))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(b.String())
+}
+
+// truncate cuts s to at most limit runes, backing off to the last space
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	cut := string([]rune(s)[:limit])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
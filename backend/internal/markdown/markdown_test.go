@@ -0,0 +1,211 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "script block",
+			source:  "<script>alert(1)</script>\n\nhi",
+			want:    []string{"<p>hi</p>"},
+			notWant: []string{"<script", "alert(1)"},
+		},
+		{
+			name:    "inline script",
+			source:  "hi <script>alert(1)</script> there",
+			want:    []string{"hi", "there"},
+			notWant: []string{"<script"},
+		},
+		{
+			name:    "style and iframe",
+			source:  "<style>body{display:none}</style>\n\n<iframe src=\"https://evil.example\"></iframe>",
+			notWant: []string{"<style", "display:none", "<iframe", "evil.example"},
+		},
+		{
+			name:    "javascript link",
+			source:  "[x](javascript:alert(1))",
+			want:    []string{"<p>x</p>"},
+			notWant: []string{"javascript:", "href"},
+		},
+		{
+			name:    "mixed case javascript link",
+			source:  "[x](JaVaScRiPt:alert(1))",
+			notWant: []string{"javascript:", "JaVaScRiPt:", "href"},
+		},
+		{
+			name:    "javascript reference link",
+			source:  "[x][1]\n\n[1]: javascript:alert(1)",
+			notWant: []string{"javascript:", "href"},
+		},
+		{
+			name:    "javascript link in raw html",
+			source:  `<a href="javascript:alert(1)">x</a>`,
+			notWant: []string{"javascript:", "href"},
+		},
+		{
+			name:    "javascript image",
+			source:  "![x](javascript:alert(1))",
+			notWant: []string{"javascript:"},
+		},
+		{
+			name:    "safe link is kept",
+			source:  "[x](https://example.com)",
+			want:    []string{`<a href="https://example.com" rel="nofollow">x</a>`},
+		},
+		{
+			name:    "onerror",
+			source:  `<img src="x" onerror="alert(1)">`,
+			want:    []string{`<img src="x">`},
+			notWant: []string{"onerror", "alert(1)"},
+		},
+		{
+			name:    "onclick",
+			source:  `<a href="/x" onclick="alert(1)">x</a>`,
+			want:    []string{`<a href="/x" rel="nofollow">x</a>`},
+			notWant: []string{"onclick", "alert(1)"},
+		},
+		{
+			name:    "onmouseover in a heading",
+			source:  `## <b onmouseover="alert(1)">Setup</b>`,
+			want:    []string{"<b>Setup</b>"},
+			notWant: []string{"onmouseover", "alert(1)"},
+		},
+		{
+			name:    "style with a url",
+			source:  `<div style="background:url(javascript:alert(1))">x</div>`,
+			notWant: []string{"style=", "javascript:"},
+		},
+	}
+
+	r := NewRenderer("github")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := r.Render(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(res.HTML, s) {
+					t.Errorf("HTML %q lacks %q", res.HTML, s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(res.HTML, s) {
+					t.Errorf("HTML %q contains %q", res.HTML, s)
+				}
+			}
+		})
+	}
+}
+
+var headingID = regexp.MustCompile(`<h[1-6] id="([^"]*)"`)
+
+// Inline HTML in headings reaches neither the TOC nor the anchors it links to
+func TestRenderHeadingHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		wantText string
+	}{
+		{"bold", "## Install <b>fast</b>", "Install fast"},
+		{"event handler", `## <span onclick="alert(1)">Setup</span>`, "Setup"},
+		{"image", `## <img src="x" onerror="alert(1)">Usage`, "Usage"},
+		{"script", "## Hello <script>x</script>", "Hello x"},
+	}
+
+	r := NewRenderer("github")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := r.Render(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.TOC) != 1 {
+				t.Fatalf("TOC = %+v, want one entry", res.TOC)
+			}
+			entry := res.TOC[0]
+			if entry.Text != tt.wantText {
+				t.Errorf("TOC text = %q, want %q", entry.Text, tt.wantText)
+			}
+			if strings.ContainsAny(entry.Text+entry.ID, `<>"`) {
+				t.Errorf("TOC entry %+v carries markup", entry)
+			}
+
+			m := headingID.FindStringSubmatch(res.HTML)
+			if m == nil {
+				t.Fatalf("no heading id in %q", res.HTML)
+			}
+			if m[1] != entry.ID {
+				t.Errorf("heading id %q, TOC links to %q", m[1], entry.ID)
+			}
+			for _, s := range []string{"onclick", "onerror", "<script"} {
+				if strings.Contains(res.HTML, s) {
+					t.Errorf("HTML %q contains %q", res.HTML, s)
+				}
+			}
+		})
+	}
+}
+
+// The sanitizer keeps the inline styles chroma highlights code with
+func TestRenderKeepsHighlighting(t *testing.T) {
+	r := NewRenderer("github")
+	res, err := r.Render("```go\nfunc main() {}\n```")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`<pre style="background-color: #fff">`,
+		`<span style="color: #000; font-weight: bold">func</span>`,
+		`<span style="color: #900; font-weight: bold">main</span>`,
+	} {
+		if !strings.Contains(res.HTML, s) {
+			t.Errorf("HTML %q lacks %q", res.HTML, s)
+		}
+	}
+
+	// Styles outside the allowed properties are still dropped on spans
+	res, err = r.Render(`<span style="position: fixed; color: red">x</span>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(res.HTML, "position") {
+		t.Errorf("HTML %q keeps position", res.HTML)
+	}
+}
+
+func TestRenderHeadingIDs(t *testing.T) {
+	r := NewRenderer("github")
+	res, err := r.Render("# Getting Started\n\n## Install `go`\n\n## See [the docs](https://example.com)\n\n## Getting started\n\n##### Deep")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []TOCEntry{
+		{Level: 1, Text: "Getting Started", ID: "getting-started"},
+		{Level: 2, Text: "Install go", ID: "install-go"},
+		{Level: 2, Text: "See the docs", ID: "see-the-docs"},
+		{Level: 2, Text: "Getting started", ID: "getting-started-1"},
+	}
+	if len(res.TOC) != len(want) {
+		t.Fatalf("TOC = %+v, want %+v", res.TOC, want)
+	}
+	for i := range want {
+		if res.TOC[i] != want[i] {
+			t.Errorf("TOC[%d] = %+v, want %+v", i, res.TOC[i], want[i])
+		}
+	}
+	// Headings left out of the TOC are still anchored
+	if !strings.Contains(res.HTML, `<h5 id="deep">`) {
+		t.Errorf("HTML %q lacks the h5 anchor", res.HTML)
+	}
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+)
+
+// Coarse device classes of a page view; bots are dropped before counting
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+)
+
+// PageView is the analytics event published for each counted page view. It
+// holds no IP, user agent or cookie: the visitor is a hash salted with a
+// random value that is discarded after the day ends.
+type PageView struct {
+	ID             string    `json:"id"`
+	TenantID       uuid.UUID `json:"-"` // Travels in the event envelope
+	Path           string    `json:"path"`
+	ReferrerDomain string    `json:"referrer_domain,omitempty"`
+	Device         string    `json:"device"`
+	VisitorHash    string    `json:"visitor_hash"`
+	ViewedAt       time.Time `json:"viewed_at"`
+}
+
+// AnalyticsDailyPage is the daily rollup of views of one path from one
+// referrer on one device class. Visitors counts each visitor's first view of
+// the path that day.
+type AnalyticsDailyPage struct {
+	TenantID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Day            time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Path           string    `gorm:"type:varchar(500);primaryKey;index:idx_analytics_daily_pages_path" json:"path"`
+	ReferrerDomain string    `gorm:"type:varchar(255);primaryKey" json:"referrer_domain"`
+	Device         string    `gorm:"type:varchar(20);primaryKey" json:"device"`
+	Views          int64     `gorm:"not null;default:0" json:"views"`
+	Visitors       int64     `gorm:"not null;default:0" json:"visitors"`
+}
+
+func (p *AnalyticsDailyPage) TableName() string {
+	return "analytics_daily_pages"
+}
+
+// AnalyticsDailyVisitors counts unique visitors to a tenant's whole site per day,
+// which can't be summed from the per-path rollups
+type AnalyticsDailyVisitors struct {
+	TenantID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Day      time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Visitors int64     `gorm:"not null;default:0" json:"visitors"`
+}
+
+func (v *AnalyticsDailyVisitors) TableName() string {
+	return "analytics_daily_visitors"
+}
+
+// AnalyticsRollup is a batch of page views aggregated for writing
+type AnalyticsRollup struct {
+	Pages    []AnalyticsDailyPage
+	Visitors []AnalyticsDailyVisitors
+}
+
+// AnalyticsPageStat is a path's traffic over a date range
+type AnalyticsPageStat struct {
+	Path     string `json:"path"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// AnalyticsReferrerStat is the traffic sent by a referring domain; an empty
+// domain is direct traffic
+type AnalyticsReferrerStat struct {
+	Domain   string `json:"domain"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// AnalyticsDayStat is one day of a traffic time series
+type AnalyticsDayStat struct {
+	Day      string `json:"day"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
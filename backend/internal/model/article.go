@@ -1,23 +1,74 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"time"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// TOCEntry is a heading of the rendered article, linked by its anchor id
+type TOCEntry struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+type TableOfContents []TOCEntry
+
+func (t *TableOfContents) Scan(value interface{}) error {
+	if value == nil {
+		*t = []TOCEntry{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+func (t TableOfContents) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(t)
+}
+
 type Article struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Title       string         `gorm:"type:varchar(255);not null;index" json:"title"`
-	Slug        string         `gorm:"type:varchar(255);uniqueIndex:idx_articles_slug;not null" json:"slug"`
-	Excerpt     string         `gorm:"type:text" json:"excerpt"`
-	Content     string         `gorm:"type:text;not null" json:"content"`
-	AuthorID    uuid.UUID      `gorm:"type:uuid;not null;index:idx_articles_author_id" json:"author_id"`
-	Published   bool           `gorm:"default:false;index:idx_articles_published" json:"published"`
-	PublishedAt *time.Time     `gorm:"index:idx_articles_published_at" json:"published_at,omitempty"`
-	CreatedAt   time.Time      `gorm:"index:idx_articles_created_at" json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index:idx_articles_deleted_at" json:"-"`
+	ID                 uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID           uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_articles_tenant_slug" json:"-"`
+	Title              string          `gorm:"type:varchar(255);not null;index" json:"title"`
+	Slug               string          `gorm:"type:varchar(255);uniqueIndex:idx_articles_tenant_slug;not null" json:"slug"`
+	Excerpt            string          `gorm:"type:text" json:"excerpt"`
+	Content            string          `gorm:"type:text;not null" json:"content"`
+	ContentHTML        string          `gorm:"type:text" json:"content_html"`
+	TOC                TableOfContents `gorm:"type:jsonb" json:"toc"`
+	WordCount          int             `gorm:"default:0" json:"word_count"`
+	ReadingTimeMinutes int             `gorm:"default:0" json:"reading_time_minutes"`
+	CommentCount       int             `gorm:"->;-:migration" json:"comment_count"` // Approved comments, computed on read
+	ViewCount          int64           `gorm:"not null;default:0" json:"view_count"`
+	LikeCount          int64           `gorm:"not null;default:0" json:"like_count"`
+	ClapCount          int64           `gorm:"not null;default:0" json:"clap_count"`
+	AuthorID           uuid.UUID       `gorm:"type:uuid;not null;index:idx_articles_author_id" json:"author_id"`
+	Published          bool            `gorm:"default:false;index:idx_articles_published" json:"published"`
+	NoIndex            bool            `gorm:"column:noindex;default:false" json:"noindex"`
+	Position           *int            `gorm:"index:idx_articles_position" json:"position"` // Place among pinned articles, set only by a reorder; nil when not pinned
+	CoverImageID       *uuid.UUID      `gorm:"type:uuid" json:"cover_image_id,omitempty"`
+	CoverImage         *Media          `gorm:"foreignKey:CoverImageID;constraint:OnDelete:SET NULL" json:"cover_image,omitempty"`
+	PublishedAt        *time.Time      `gorm:"index:idx_articles_published_at" json:"published_at,omitempty"`
+	PublishAt          *time.Time      `gorm:"index:idx_articles_publish_at" json:"publish_at,omitempty"`
+	UnpublishAt        *time.Time      `gorm:"index:idx_articles_unpublish_at" json:"unpublish_at,omitempty"`
+	CreatedAt          time.Time       `gorm:"index:idx_articles_created_at" json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt  `gorm:"index:idx_articles_deleted_at" json:"-"`
+	Tags               []Tag           `gorm:"many2many:article_tags;constraint:OnDelete:CASCADE" json:"tags"`
+	Projects           []Project       `gorm:"many2many:article_projects;constraint:OnDelete:CASCADE" json:"projects,omitempty"` // Projects the article describes
+	Related            []Article       `gorm:"-" json:"related,omitempty"`                                                        // Suggested further reading, filled on detail reads
+	Translations       []ArticleTranslation `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+	Locale             string          `gorm:"-" json:"locale,omitempty"`     // Locale of the text, set on public reads
+	Alternates         []Alternate     `gorm:"-" json:"alternates,omitempty"` // Every locale the article is in, filled on detail reads
 }
 
 func (a *Article) BeforeCreate(tx *gorm.DB) error {
@@ -32,6 +83,21 @@ func (a *Article) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
 
+// Scheduled transitions reported in the content calendar
+const (
+	ScheduleActionPublish   = "publish"
+	ScheduleActionUnpublish = "unpublish"
+)
+
+// CalendarEntry is an upcoming scheduled publish or unpublish of an article
+type CalendarEntry struct {
+	ArticleID uuid.UUID `json:"article_id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Action    string    `json:"action"`
+	At        time.Time `json:"at"`
+}
+
 func (a *Article) TableName() string {
 	return "articles"
 }
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/pkg/textdiff"
+	"gorm.io/gorm"
+)
+
+// ArticleRevision is an immutable snapshot of an article taken on every save.
+// Autosaves are kept as revisions too but hidden from the default history.
+type ArticleRevision struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArticleID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_article_revisions_number,priority:1" json:"article_id"`
+	Number         int        `gorm:"not null;uniqueIndex:idx_article_revisions_number,priority:2" json:"number"`
+	AuthorID       uuid.UUID  `gorm:"type:uuid;not null" json:"author_id"`
+	Title          string     `gorm:"type:varchar(255);not null" json:"title"`
+	Slug           string     `gorm:"type:varchar(255);not null" json:"slug"`
+	Excerpt        string     `gorm:"type:text" json:"excerpt"`
+	Content        string     `gorm:"type:text;not null" json:"content,omitempty"`
+	Published      bool       `gorm:"default:false" json:"published"`
+	Autosave       bool       `gorm:"default:false;index:idx_article_revisions_autosave" json:"autosave"`
+	RestoredFromID *uuid.UUID `gorm:"type:uuid" json:"restored_from_id,omitempty"`
+	CreatedAt      time.Time  `gorm:"index:idx_article_revisions_created_at" json:"created_at"`
+}
+
+func (r *ArticleRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *ArticleRevision) TableName() string {
+	return "article_revisions"
+}
+
+// NewArticleRevision snapshots the article's current content
+func NewArticleRevision(article *Article, authorID uuid.UUID) *ArticleRevision {
+	return &ArticleRevision{
+		ArticleID: article.ID,
+		AuthorID:  authorID,
+		Title:     article.Title,
+		Slug:      article.Slug,
+		Excerpt:   article.Excerpt,
+		Content:   article.Content,
+		Published: article.Published,
+	}
+}
+
+// ArticleRevisionDiff is a line diff of the content of two revisions
+type ArticleRevisionDiff struct {
+	From          *ArticleRevision `json:"from"`
+	To            *ArticleRevision `json:"to"`
+	ChangedFields []string         `json:"changed_fields"`
+	Lines         []textdiff.Line  `json:"lines"`
+	Stats         textdiff.Stats   `json:"stats"`
+	Unified       string           `json:"unified"`
+}
+
+// DiffArticleRevisions compares the content of two revisions line by line
+// and reports which other fields differ
+func DiffArticleRevisions(from, to *ArticleRevision) *ArticleRevisionDiff {
+	changed := []string{}
+	if from.Title != to.Title {
+		changed = append(changed, "title")
+	}
+	if from.Slug != to.Slug {
+		changed = append(changed, "slug")
+	}
+	if from.Excerpt != to.Excerpt {
+		changed = append(changed, "excerpt")
+	}
+	if from.Content != to.Content {
+		changed = append(changed, "content")
+	}
+
+	lines := textdiff.Lines(from.Content, to.Content)
+
+	// Content is carried by the diff itself
+	fromMeta, toMeta := *from, *to
+	fromMeta.Content, toMeta.Content = "", ""
+
+	diff := &ArticleRevisionDiff{
+		From:          &fromMeta,
+		To:            &toMeta,
+		ChangedFields: changed,
+		Lines:         lines,
+		Stats:         textdiff.Summarize(lines),
+		Unified:       textdiff.Unified(lines, 3),
+	}
+	if from.ID == uuid.Nil {
+		// Diffed against an empty document
+		diff.From = nil
+	}
+	return diff
+}
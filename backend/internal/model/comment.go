@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Moderation states of a comment; only approved comments are public
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusSpam     = "spam"
+)
+
+// Comment is a visitor's comment on an article. Visitors have no account, so
+// the author is whatever name and email they typed in.
+type Comment struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_comments_tenant_id" json:"-"`
+	ArticleID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_comments_article_status" json:"article_id"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index:idx_comments_parent_id" json:"parent_id,omitempty"`
+	AuthorName  string     `gorm:"type:varchar(100);not null" json:"author_name"`
+	AuthorEmail string     `gorm:"type:varchar(255);not null" json:"author_email,omitempty"`
+	Content     string     `gorm:"type:text;not null" json:"content"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_comments_article_status;index:idx_comments_status" json:"status"`
+	IPHash      string     `gorm:"type:varchar(64);index:idx_comments_ip_hash" json:"-"`
+	UserAgent   string     `gorm:"type:varchar(500)" json:"-"`
+	ModeratedAt *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"index:idx_comments_created_at" json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Parent      *Comment   `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE" json:"-"`
+	Article     *Article   `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE" json:"-"`
+
+	// Replies is filled in when comments are returned as a thread
+	Replies []Comment `gorm:"-" json:"replies,omitempty"`
+}
+
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+func (c *Comment) TableName() string {
+	return "comments"
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// Content that visitors can view and react to
+const (
+	CounterTargetArticle = "article"
+	CounterTargetProject = "project"
+)
+
+// Counted interactions; likes and claps are the reactions
+const (
+	CounterView  = "view"
+	ReactionLike = "like"
+	ReactionClap = "clap"
+)
+
+// CounterDelta is what one flush adds to the counters of an article or project
+type CounterDelta struct {
+	Target string
+	ID     uuid.UUID
+	Views  int64
+	Likes  int64
+	Claps  int64
+}
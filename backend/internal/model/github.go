@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+)
+
+// ProjectGithub is the GitHub metadata last synced for a project's
+// repository. The ETags make the next sync a conditional request.
+type ProjectGithub struct {
+	ProjectID        uuid.UUID   `gorm:"type:uuid;primaryKey" json:"-"`
+	Owner            string      `gorm:"type:varchar(100);not null" json:"owner"`
+	Name             string      `gorm:"type:varchar(100);not null" json:"name"`
+	Stars            int         `gorm:"not null;default:0" json:"stars"`
+	Forks            int         `gorm:"not null;default:0" json:"forks"`
+	Languages        StringArray `gorm:"type:jsonb" json:"languages"` // Primary languages, largest first
+	License          string      `gorm:"type:varchar(100)" json:"license,omitempty"` // SPDX identifier
+	Topics           StringArray `gorm:"type:jsonb" json:"topics"`
+	Archived         bool        `gorm:"not null;default:false" json:"archived"`
+	PushedAt         *time.Time  `json:"pushed_at,omitempty"`
+	LatestRelease    string      `gorm:"type:varchar(255)" json:"latest_release,omitempty"` // Tag name
+	LatestReleaseURL string      `gorm:"type:varchar(500)" json:"latest_release_url,omitempty"`
+	LatestReleaseAt  *time.Time  `json:"latest_release_at,omitempty"`
+	RepoETag         string      `gorm:"column:repo_etag;type:varchar(255)" json:"-"`
+	LanguagesETag    string      `gorm:"column:languages_etag;type:varchar(255)" json:"-"`
+	ReleaseETag      string      `gorm:"column:release_etag;type:varchar(255)" json:"-"`
+	SyncedAt         time.Time   `gorm:"index:idx_project_github_synced_at" json:"synced_at"`
+	// SyncError is why the last sync failed for good, such as a URL that
+	// names no repository; empty after a successful sync
+	SyncError        string      `gorm:"type:varchar(255)" json:"sync_error,omitempty"`
+}
+
+func (g *ProjectGithub) TableName() string {
+	return "project_github"
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Processing states of an uploaded file
+const (
+	MediaStatusPending = "pending"
+	MediaStatusReady   = "ready"
+	MediaStatusFailed  = "failed"
+)
+
+// Media is an uploaded file. The bytes live in the configured storage
+// backend under Key; URL is where clients fetch it from.
+type Media struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID    uuid.UUID `gorm:"type:uuid;not null;index:idx_media_tenant_id" json:"-"`
+	Key         string    `gorm:"type:varchar(500);uniqueIndex:idx_media_key;not null" json:"key"`
+	URL         string    `gorm:"type:varchar(1000);not null" json:"url"`
+	Filename    string    `gorm:"type:varchar(255)" json:"filename"`
+	ContentType string    `gorm:"type:varchar(100);not null;index:idx_media_content_type" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Width       int       `json:"width,omitempty"`
+	Height      int       `json:"height,omitempty"`
+	Checksum    string    `gorm:"type:varchar(64);index:idx_media_checksum" json:"checksum"`
+	Alt         string    `gorm:"type:varchar(500)" json:"alt"`
+	UploadedBy  uuid.UUID `gorm:"type:uuid" json:"uploaded_by"`
+	CreatedAt   time.Time `gorm:"index:idx_media_created_at" json:"created_at"`
+
+	// Set by the derivative pipeline once the upload has been processed
+	Status      string         `gorm:"type:varchar(20);not null;default:'pending';index:idx_media_status" json:"status"`
+	BlurHash    string         `gorm:"type:varchar(100)" json:"blurhash,omitempty"`
+	ProcessedAt *time.Time     `json:"processed_at,omitempty"`
+	Variants    []MediaVariant `gorm:"foreignKey:MediaID;constraint:OnDelete:CASCADE" json:"variants,omitempty"`
+}
+
+func (m *Media) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+func (m *Media) TableName() string {
+	return "media"
+}
+
+// MediaVariant is a resized or re-encoded copy of an image
+type MediaVariant struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MediaID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_media_variants_media_width_format" json:"-"`
+	Width     int       `gorm:"not null;uniqueIndex:idx_media_variants_media_width_format" json:"width"`
+	Height    int       `gorm:"not null" json:"height"`
+	Format    string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_media_variants_media_width_format" json:"format"`
+	Key       string    `gorm:"type:varchar(500);not null" json:"key"`
+	URL       string    `gorm:"type:varchar(1000);not null" json:"url"`
+	Size      int64     `gorm:"not null" json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (v *MediaVariant) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+func (v *MediaVariant) TableName() string {
+	return "media_variants"
+}
+
+// ProjectMedia places a media item in a project's gallery
+type ProjectMedia struct {
+	ProjectID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	MediaID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"media_id"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	Caption   string    `gorm:"type:varchar(500)" json:"caption"`
+	Media     *Media    `gorm:"foreignKey:MediaID;constraint:OnDelete:CASCADE" json:"media,omitempty"`
+}
+
+func (pm *ProjectMedia) TableName() string {
+	return "project_media"
+}
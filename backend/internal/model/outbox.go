@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a domain event recorded in the same transaction as the
+// change it describes. The relay publishes pending rows in ID order and
+// stamps SentAt once Kafka has acknowledged them; one that keeps failing is
+// parked with FailedAt so the events behind it still go out.
+type OutboxEvent struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventID   uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"event_id"`
+	TenantID  *uuid.UUID `gorm:"type:uuid" json:"tenant_id,omitempty"` // Nil for events not raised on behalf of a tenant
+	Topic     string     `gorm:"type:varchar(255);not null" json:"topic"`
+	EventType string     `gorm:"type:varchar(100);not null" json:"event_type"`
+	Key       string     `gorm:"type:varchar(255);not null" json:"key"` // Aggregate ID; keeps its events on one partition, in order
+	Payload   string     `gorm:"type:jsonb;not null" json:"payload"`    // The encoded event envelope
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+	LastError string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	SentAt    *time.Time `gorm:"index" json:"sent_at,omitempty"`
+	FailedAt  *time.Time `json:"failed_at,omitempty"` // Set when the relay gave up on the event; cleared to replay it
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
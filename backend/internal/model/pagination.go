@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+)
+
+// Cursor is a position in a list ordered newest first by (created_at, id).
+// A page starts just after it, or ends just before it when Before is set.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+	Before    bool
+}
+
+// CursorPage describes one page of a cursor-paginated list. Cursors are
+// opaque to clients and empty when there is no page in that direction.
+type CursorPage struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
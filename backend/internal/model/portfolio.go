@@ -9,6 +9,7 @@ import (
 
 type Portfolio struct {
 	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID    uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_portfolio_tenant_id" json:"-"` // One portfolio per tenant
 	Name        string         `gorm:"type:varchar(255);not null" json:"name"`
 	Title       string         `gorm:"type:varchar(255)" json:"title"`
 	Bio         string         `gorm:"type:text" json:"bio"`
@@ -16,6 +17,17 @@ type Portfolio struct {
 	SocialLinks datatypes.JSON `gorm:"type:jsonb" json:"social_links"`
 	Settings    datatypes.JSON `gorm:"type:jsonb" json:"settings"`
 	UpdatedAt   time.Time      `json:"updated_at"`
+	Translations []PortfolioTranslation `gorm:"foreignKey:PortfolioID;constraint:OnDelete:CASCADE" json:"-"`
+	Locale      string         `gorm:"-" json:"locale,omitempty"`     // Locale of the title and bio, set on public reads
+	Alternates  []Alternate    `gorm:"-" json:"alternates,omitempty"` // Every locale the portfolio is in
+
+	// Resume sections, filled only when asked for; an included section
+	// with no entries is an empty list
+	Experience     *[]Experience    `gorm:"-" json:"experience,omitempty"`
+	Education      *[]Education     `gorm:"-" json:"education,omitempty"`
+	Skills         *[]Skill         `gorm:"-" json:"skills,omitempty"`
+	Certifications *[]Certification `gorm:"-" json:"certifications,omitempty"`
+	Awards         *[]Award         `gorm:"-" json:"awards,omitempty"`
 }
 
 func (p *Portfolio) BeforeCreate(tx *gorm.DB) error {
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PreviewToken grants read access to one (possibly unpublished) article.
+// Only the token ID is stored; the token itself is signed and handed out once.
+type PreviewToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArticleID uuid.UUID  `gorm:"type:uuid;not null;index:idx_preview_tokens_article_id" json:"article_id"`
+	CreatedBy uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	ExpiresAt time.Time  `gorm:"not null;index:idx_preview_tokens_expires_at" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (t *PreviewToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *PreviewToken) TableName() string {
+	return "preview_tokens"
+}
+
+// Active reports whether the token is neither revoked nor expired at now
+func (t *PreviewToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
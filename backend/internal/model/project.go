@@ -31,15 +31,54 @@ func (a StringArray) Value() (driver.Value, error) {
 
 type Project struct {
 	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID    uuid.UUID      `gorm:"type:uuid;not null;index:idx_projects_tenant_id" json:"-"`
 	Name        string         `gorm:"type:varchar(255);not null;index" json:"name"`
 	Description string         `gorm:"type:text" json:"description"`
 	GithubURL   string         `gorm:"type:varchar(500)" json:"github_url"`
 	LiveURL     string         `gorm:"type:varchar(500)" json:"live_url"`
-	Technologies StringArray   `gorm:"type:jsonb" json:"technologies"`
+	Technologies StringArray   `gorm:"type:jsonb;index:idx_projects_technologies,type:gin" json:"technologies"`
 	Featured    bool           `gorm:"default:false;index:idx_projects_featured" json:"featured"`
+	Position    *int           `gorm:"index:idx_projects_position" json:"position"` // Curated order, set only by a reorder; nil sorts last
+	CoverImageID *uuid.UUID    `gorm:"type:uuid" json:"cover_image_id,omitempty"`
+	CoverImage  *Media         `gorm:"foreignKey:CoverImageID;constraint:OnDelete:SET NULL" json:"cover_image,omitempty"`
+	Gallery     []ProjectMedia `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"gallery,omitempty"`
+	Articles    []Article      `gorm:"many2many:article_projects;constraint:OnDelete:CASCADE" json:"articles,omitempty"` // Published articles about the project, filled on detail reads
+	Github      *ProjectGithub `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"github,omitempty"` // Synced from GithubURL in the background
+	ViewCount   int64          `gorm:"not null;default:0" json:"view_count"`
+	LikeCount   int64          `gorm:"not null;default:0" json:"like_count"`
+	ClapCount   int64          `gorm:"not null;default:0" json:"clap_count"`
 	CreatedAt   time.Time      `gorm:"index:idx_projects_created_at" json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index:idx_projects_deleted_at" json:"-"`
+	Translations []ProjectTranslation `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	Locale      string         `gorm:"-" json:"locale,omitempty"`     // Locale of the text, set on public reads
+	Alternates  []Alternate    `gorm:"-" json:"alternates,omitempty"` // Every locale the project is in, filled on detail reads
+}
+
+// ProjectFilter narrows a project list. Date bounds are inclusive of From
+// and exclusive of Until.
+type ProjectFilter struct {
+	Featured     *bool
+	Technologies []string
+	MatchAll     bool   // Require every technology rather than any of them
+	Search       string // Matched against name and description
+	CreatedFrom  *time.Time
+	CreatedUntil *time.Time
+	UpdatedFrom  *time.Time
+	UpdatedUntil *time.Time
+	Sort         []SortField // Empty sorts newest first
+}
+
+// SortField is one key of a multi-field sort; Column comes from a whitelist
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// TechnologyFacet counts the projects in a filtered list using a technology
+type TechnologyFacet struct {
+	Technology string `json:"technology"`
+	Count      int64  `json:"count"`
 }
 
 func (p *Project) BeforeCreate(tx *gorm.DB) error {
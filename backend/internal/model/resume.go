@@ -0,0 +1,147 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Resume sections, as named by ?include= on the portfolio
+const (
+	ResumeExperience     = "experience"
+	ResumeEducation      = "education"
+	ResumeSkills         = "skills"
+	ResumeCertifications = "certifications"
+	ResumeAwards         = "awards"
+)
+
+// Skill proficiency levels, lowest first
+const (
+	SkillLevelBeginner     = "beginner"
+	SkillLevelIntermediate = "intermediate"
+	SkillLevelAdvanced     = "advanced"
+	SkillLevelExpert       = "expert"
+)
+
+// ResumeItem holds the columns every resume entry has
+type ResumeItem struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID  uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	Position  *int      `gorm:"index" json:"position"` // Curated order, set only by a reorder; nil sorts last
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (i *ResumeItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// Item gives code shared by all sections access to the common columns
+func (i *ResumeItem) Item() *ResumeItem {
+	return i
+}
+
+// ResumeEntry is implemented by the entries of every resume section
+type ResumeEntry interface {
+	Item() *ResumeItem
+	// Period is when the entry applies; a nil end means it still does
+	Period() (start, end *time.Time)
+}
+
+type Experience struct {
+	ResumeItem
+	Company      string      `gorm:"type:varchar(255);not null" json:"company"`
+	Role         string      `gorm:"type:varchar(255);not null" json:"role"`
+	Location     string      `gorm:"type:varchar(255)" json:"location"`
+	URL          string      `gorm:"type:varchar(500)" json:"url"`
+	Description  string      `gorm:"type:text" json:"description"`
+	Highlights   StringArray `gorm:"type:jsonb" json:"highlights"`
+	Technologies StringArray `gorm:"type:jsonb" json:"technologies"`
+	StartDate    time.Time   `gorm:"type:date;not null" json:"start_date"`
+	EndDate      *time.Time  `gorm:"type:date" json:"end_date"` // Nil while current
+}
+
+func (e *Experience) Period() (*time.Time, *time.Time) {
+	return &e.StartDate, e.EndDate
+}
+
+func (e *Experience) TableName() string {
+	return "resume_experience"
+}
+
+type Education struct {
+	ResumeItem
+	Institution string     `gorm:"type:varchar(255);not null" json:"institution"`
+	Degree      string     `gorm:"type:varchar(255)" json:"degree"`
+	Field       string     `gorm:"type:varchar(255)" json:"field"`
+	Location    string     `gorm:"type:varchar(255)" json:"location"`
+	Grade       string     `gorm:"type:varchar(100)" json:"grade"`
+	URL         string     `gorm:"type:varchar(500)" json:"url"`
+	Description string     `gorm:"type:text" json:"description"`
+	StartDate   time.Time  `gorm:"type:date;not null" json:"start_date"`
+	EndDate     *time.Time `gorm:"type:date" json:"end_date"` // Nil while studying
+}
+
+func (e *Education) Period() (*time.Time, *time.Time) {
+	return &e.StartDate, e.EndDate
+}
+
+func (e *Education) TableName() string {
+	return "resume_education"
+}
+
+type Skill struct {
+	ResumeItem
+	Name     string     `gorm:"type:varchar(100);not null" json:"name"`
+	Category string     `gorm:"type:varchar(100);index" json:"category"` // e.g. "Languages", "Cloud"
+	Level    string     `gorm:"type:varchar(20);not null" json:"level"`
+	Since    *time.Time `gorm:"type:date" json:"since"`     // First used
+	LastUsed *time.Time `gorm:"type:date" json:"last_used"` // Nil while in use
+}
+
+func (s *Skill) Period() (*time.Time, *time.Time) {
+	return s.Since, s.LastUsed
+}
+
+func (s *Skill) TableName() string {
+	return "resume_skills"
+}
+
+type Certification struct {
+	ResumeItem
+	Name         string     `gorm:"type:varchar(255);not null" json:"name"`
+	Issuer       string     `gorm:"type:varchar(255)" json:"issuer"`
+	CredentialID string     `gorm:"type:varchar(255)" json:"credential_id"`
+	URL          string     `gorm:"type:varchar(500)" json:"url"` // Where the credential can be verified
+	IssuedOn     time.Time  `gorm:"type:date;not null" json:"issued_on"`
+	ExpiresOn    *time.Time `gorm:"type:date" json:"expires_on"` // Nil if it doesn't expire
+}
+
+func (c *Certification) Period() (*time.Time, *time.Time) {
+	return &c.IssuedOn, c.ExpiresOn
+}
+
+func (c *Certification) TableName() string {
+	return "resume_certifications"
+}
+
+type Award struct {
+	ResumeItem
+	Title       string    `gorm:"type:varchar(255);not null" json:"title"`
+	Issuer      string    `gorm:"type:varchar(255)" json:"issuer"`
+	URL         string    `gorm:"type:varchar(500)" json:"url"`
+	Description string    `gorm:"type:text" json:"description"`
+	AwardedOn   time.Time `gorm:"type:date;not null" json:"awarded_on"`
+}
+
+// Period of an award is the day it was given
+func (a *Award) Period() (*time.Time, *time.Time) {
+	return &a.AwardedOn, &a.AwardedOn
+}
+
+func (a *Award) TableName() string {
+	return "resume_awards"
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+)
+
+// Search result types
+const (
+	SearchTypeArticle = "article"
+	SearchTypeProject = "project"
+)
+
+type SearchHit struct {
+	Type         string      `json:"type"`
+	ID           uuid.UUID   `json:"id"`
+	Title        string      `json:"title"`
+	Slug         string      `json:"slug,omitempty"`
+	Highlight    string      `json:"highlight"`
+	Snippet      string      `json:"snippet"`
+	Technologies StringArray `json:"technologies,omitempty"`
+	Score        float64     `json:"score"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type SearchFacets struct {
+	Types        []FacetCount `json:"types"`
+	Technologies []FacetCount `json:"technologies"`
+}
+
+type SearchResult struct {
+	Hits   []SearchHit  `json:"hits"`
+	Total  int64        `json:"total"`
+	Facets SearchFacets `json:"facets"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Kinds of pages listed in the sitemap
+const (
+	SitemapTypeArticle = "article"
+	SitemapTypeProject = "project"
+)
+
+// SitemapEntry is an indexable page: an article slug or a project id, in
+// the default locale or in one it is translated into
+type SitemapEntry struct {
+	Type       string    `json:"type"`
+	Locale     string    `json:"locale"` // Empty for the default locale
+	Key        string    `json:"key"`
+	DefaultKey string    `json:"default_key"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// Every translation of the page, for hreflang alternates
+	Translations SitemapTranslations `json:"translations"`
+}
+
+// SitemapTranslation is the key of a page in a locale
+type SitemapTranslation struct {
+	Locale string `json:"locale"`
+	Key    string `json:"key"`
+}
+
+type SitemapTranslations []SitemapTranslation
+
+func (t *SitemapTranslations) Scan(value interface{}) error {
+	if value == nil {
+		*t = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, t)
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag kinds
+const (
+	TagKindTag      = "tag"
+	TagKindCategory = "category"
+)
+
+type Tag struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_tags_tenant_slug" json:"-"`
+	Name         string    `gorm:"type:varchar(100);not null" json:"name"`
+	Slug         string    `gorm:"type:varchar(100);uniqueIndex:idx_tags_tenant_slug;not null" json:"slug"`
+	Kind         string    `gorm:"type:varchar(20);not null;default:tag;index:idx_tags_kind" json:"kind"`
+	Description  string    `gorm:"type:text" json:"description"`
+	ArticleCount int64     `gorm:"->;-:migration" json:"article_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *Tag) BeforeUpdate(tx *gorm.DB) error {
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+func (t *Tag) TableName() string {
+	return "tags"
+}
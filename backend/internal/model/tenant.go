@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultTenant is the slug of the tenant that content predating
+// multi-tenancy was moved to
+const DefaultTenant = "default"
+
+// Tenant is one portfolio hosted by the deployment, with its own articles,
+// projects and everything hanging off them. Requests reach it through one of
+// its hosts or the /t/<slug> path prefix. The slug names it in auth-service,
+// which records the users owning it, so it never changes.
+type Tenant struct {
+	ID        uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Slug      string      `gorm:"type:varchar(63);uniqueIndex:idx_tenants_slug;not null" json:"slug"`
+	Name      string      `gorm:"type:varchar(255);not null" json:"name"`
+	Hosts     StringArray `gorm:"type:jsonb;index:idx_tenants_hosts,type:gin" json:"hosts"`  // Lower-case host names, without port
+	SiteURL   string      `gorm:"type:varchar(500)" json:"site_url,omitempty"`               // Public website; empty uses SITE_URL
+	APIURL    string      `gorm:"column:api_url;type:varchar(500)" json:"api_url,omitempty"` // This API as the tenant reaches it; empty uses API_URL
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func (t *Tenant) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *Tenant) TableName() string {
+	return "tenants"
+}
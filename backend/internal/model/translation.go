@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ArticleTranslation is an article in another locale, with its own slug.
+// The rendered fields are filled on save like the article's own.
+type ArticleTranslation struct {
+	ID                 uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID           uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_article_translations_tenant_locale_slug" json:"-"` // Scopes slug lookups
+	ArticleID          uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_article_translations_article_locale" json:"article_id"`
+	Locale             string          `gorm:"type:varchar(35);not null;uniqueIndex:idx_article_translations_article_locale;uniqueIndex:idx_article_translations_tenant_locale_slug" json:"locale"`
+	Title              string          `gorm:"type:varchar(255);not null" json:"title"`
+	Slug               string          `gorm:"type:varchar(255);not null;uniqueIndex:idx_article_translations_tenant_locale_slug" json:"slug"`
+	Excerpt            string          `gorm:"type:text" json:"excerpt"`
+	Content            string          `gorm:"type:text;not null" json:"content"`
+	ContentHTML        string          `gorm:"type:text" json:"content_html"`
+	TOC                TableOfContents `gorm:"type:jsonb" json:"toc"`
+	WordCount          int             `gorm:"default:0" json:"word_count"`
+	ReadingTimeMinutes int             `gorm:"default:0" json:"reading_time_minutes"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// ProjectTranslation is a project's name and description in another locale
+type ProjectTranslation struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_project_translations_project_locale" json:"project_id"`
+	Locale      string    `gorm:"type:varchar(35);not null;uniqueIndex:idx_project_translations_project_locale" json:"locale"`
+	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PortfolioTranslation is the portfolio's title and bio in another locale
+type PortfolioTranslation struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PortfolioID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_portfolio_translations_portfolio_locale" json:"portfolio_id"`
+	Locale      string    `gorm:"type:varchar(35);not null;uniqueIndex:idx_portfolio_translations_portfolio_locale" json:"locale"`
+	Title       string    `gorm:"type:varchar(255)" json:"title"`
+	Bio         string    `gorm:"type:text" json:"bio"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Alternate is the same page in another locale, for hreflang links. Slug is
+// set for articles, whose slugs differ per locale.
+type Alternate struct {
+	Locale string `json:"locale"`
+	Slug   string `json:"slug,omitempty"`
+}
+
+func (t *ArticleTranslation) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *ArticleTranslation) TableName() string {
+	return "article_translations"
+}
+
+func (t *ProjectTranslation) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *ProjectTranslation) TableName() string {
+	return "project_translations"
+}
+
+func (t *PortfolioTranslation) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *PortfolioTranslation) TableName() string {
+	return "portfolio_translations"
+}
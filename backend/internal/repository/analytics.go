@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AnalyticsRepository interface {
+	// SaveRollup adds a batch of aggregated counts onto the stored rollups
+	SaveRollup(ctx context.Context, rollup *model.AnalyticsRollup) error
+	TopPages(ctx context.Context, from, to time.Time, limit int) ([]model.AnalyticsPageStat, error)
+	TopReferrers(ctx context.Context, from, to time.Time, limit int) ([]model.AnalyticsReferrerStat, error)
+	// Traffic returns per-day totals for the site, or for one path if path
+	// isn't empty. Days without traffic are left out.
+	Traffic(ctx context.Context, from, to time.Time, path string) ([]model.AnalyticsDayStat, error)
+	ListDailyPages(ctx context.Context, from, to time.Time) ([]model.AnalyticsDailyPage, error)
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+func (r *analyticsRepository) SaveRollup(ctx context.Context, rollup *model.AnalyticsRollup) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(rollup.Pages) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "tenant_id"}, {Name: "day"}, {Name: "path"}, {Name: "referrer_domain"}, {Name: "device"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"views":    gorm.Expr("analytics_daily_pages.views + excluded.views"),
+					"visitors": gorm.Expr("analytics_daily_pages.visitors + excluded.visitors"),
+				}),
+			}).Create(&rollup.Pages).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(rollup.Visitors) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "tenant_id"}, {Name: "day"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"visitors": gorm.Expr("analytics_daily_visitors.visitors + excluded.visitors"),
+				}),
+			}).Create(&rollup.Visitors).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *analyticsRepository) TopPages(ctx context.Context, from, to time.Time, limit int) ([]model.AnalyticsPageStat, error) {
+	stats := []model.AnalyticsPageStat{}
+	err := r.db.WithContext(ctx).
+		Model(&model.AnalyticsDailyPage{}).
+		Select("path, SUM(views) AS views, SUM(visitors) AS visitors").
+		Where("day BETWEEN ? AND ?", from, to).
+		Group("path").
+		Order("views DESC, path").
+		Limit(limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *analyticsRepository) TopReferrers(ctx context.Context, from, to time.Time, limit int) ([]model.AnalyticsReferrerStat, error) {
+	stats := []model.AnalyticsReferrerStat{}
+	err := r.db.WithContext(ctx).
+		Model(&model.AnalyticsDailyPage{}).
+		Select("referrer_domain AS domain, SUM(views) AS views, SUM(visitors) AS visitors").
+		Where("day BETWEEN ? AND ?", from, to).
+		Group("referrer_domain").
+		Order("views DESC, referrer_domain").
+		Limit(limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *analyticsRepository) Traffic(ctx context.Context, from, to time.Time, path string) ([]model.AnalyticsDayStat, error) {
+	stats := []model.AnalyticsDayStat{}
+	query := r.db.WithContext(ctx)
+	if path != "" {
+		query = query.
+			Model(&model.AnalyticsDailyPage{}).
+			Select("TO_CHAR(day, 'YYYY-MM-DD') AS day, SUM(views) AS views, SUM(visitors) AS visitors").
+			Where("day BETWEEN ? AND ? AND path = ?", from, to, path).
+			Group("day")
+	} else {
+		// Site-wide visitors come from their own table: a visitor reading
+		// three pages is one visitor, not three
+		query = query.
+			Table("analytics_daily_pages AS p").
+			Select("TO_CHAR(p.day, 'YYYY-MM-DD') AS day, SUM(p.views) AS views, COALESCE(MAX(v.visitors), 0) AS visitors").
+			Joins("LEFT JOIN analytics_daily_visitors v ON v.tenant_id = p.tenant_id AND v.day = p.day").
+			Where("p.day BETWEEN ? AND ?", from, to).
+			Where("CAST(? AS uuid) IS NULL OR p.tenant_id = ?", tenantParam(ctx), tenantParam(ctx)).
+			Group("p.day")
+	}
+
+	if err := query.Order("day").Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *analyticsRepository) ListDailyPages(ctx context.Context, from, to time.Time) ([]model.AnalyticsDailyPage, error) {
+	var pages []model.AnalyticsDailyPage
+	err := r.db.WithContext(ctx).
+		Where("day BETWEEN ? AND ?", from, to).
+		Order("day, path, referrer_domain, device").
+		Find(&pages).Error
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"errors"
+	"sort"
+	"time"
+	"github.com/google/uuid"
 	"github.com/portfolio/backend/internal/model"
 	"gorm.io/gorm"
 )
@@ -11,12 +14,83 @@ type ArticleRepository interface {
 	Create(ctx context.Context, article *model.Article) error
 	GetByID(ctx context.Context, id string) (*model.Article, error)
 	GetBySlug(ctx context.Context, slug string) (*model.Article, error)
-	List(ctx context.Context, page, limit int, published bool) ([]model.Article, int64, error)
+	List(ctx context.Context, page, limit int, published bool, tag string) ([]model.Article, int64, error)
+	// ListByCursor returns up to limit articles next to the cursor, newest
+	// first, and whether more lie beyond them; a nil cursor starts at the top
+	ListByCursor(ctx context.Context, cursor *model.Cursor, limit int, published bool, tag string) ([]model.Article, bool, error)
+	Count(ctx context.Context, published bool, tag string) (int64, error)
+	ListLatestPublished(ctx context.Context, tag string, limit int) ([]model.Article, error)
+	// ListPinned returns the pinned published articles in pinned order
+	ListPinned(ctx context.Context) ([]model.Article, error)
+	// ReorderPinned pins the articles in the order listed and unpins every
+	// other article
+	ReorderPinned(ctx context.Context, ids []uuid.UUID) error
 	Update(ctx context.Context, article *model.Article) error
+	ReplaceTags(ctx context.Context, article *model.Article, tags []model.Tag) error
+	// ReplaceProjects links the article to exactly the given projects
+	ReplaceProjects(ctx context.Context, articleID uuid.UUID, projectIDs []uuid.UUID) error
+	// ListRelated suggests up to limit published articles similar to the
+	// given one, best match first
+	ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]model.Article, error)
+	ListDuePublish(ctx context.Context, now time.Time) ([]model.Article, error)
+	ListDueUnpublish(ctx context.Context, now time.Time) ([]model.Article, error)
+	MarkPublished(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+	MarkUnpublished(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+	ListScheduled(ctx context.Context, from, to time.Time) ([]model.CalendarEntry, error)
 	Delete(ctx context.Context, id string) error
 	WithTransaction(ctx context.Context, fn func(*gorm.DB) error) error
 }
 
+// approvedCommentCount fills Article.CommentCount; the status literal is
+// model.CommentStatusApproved
+const approvedCommentCount = "(SELECT COUNT(*) FROM comments WHERE comments.article_id = articles.id AND comments.status = 'approved') AS comment_count"
+
+// relatedArticlesSQL scores the published articles of @id's tenant against
+// it. An article's
+// topics are its tag names plus the technologies of its linked projects;
+// each shared topic scores 1. Text similarity adds the candidate's rank for
+// the source's title and excerpt words, plus trigram similarity of titles.
+const relatedArticlesSQL = `
+WITH src AS (
+	SELECT a.id, a.tenant_id, lower(a.title) AS title,
+		replace(plainto_tsquery('english', a.title || ' ' || coalesce(a.excerpt, ''))::text, '&', '|') AS terms
+	FROM articles a
+	WHERE a.id = @id AND (CAST(@tenant AS uuid) IS NULL OR a.tenant_id = @tenant)
+), topics AS (
+	SELECT at.article_id, lower(t.name) AS topic
+	FROM article_tags at
+	JOIN tags t ON t.id = at.tag_id
+	UNION
+	SELECT ap.article_id, lower(tech.value)
+	FROM article_projects ap
+	JOIN projects p ON p.id = ap.project_id AND p.deleted_at IS NULL
+	CROSS JOIN LATERAL jsonb_array_elements_text(p.technologies) AS tech(value)
+), scored AS (
+	SELECT a.id, a.published_at,
+		(SELECT COUNT(*) FROM topics c JOIN topics s ON s.topic = c.topic AND s.article_id = src.id WHERE c.article_id = a.id)
+		+ CASE WHEN src.terms = '' THEN 0 ELSE ts_rank_cd(a.search_vector, src.terms::tsquery) END
+		+ similarity(lower(a.title), src.title) AS score
+	FROM articles a, src
+	WHERE a.id <> src.id
+		AND a.tenant_id = src.tenant_id
+		AND a.published = true
+		AND a.deleted_at IS NULL
+)
+SELECT id FROM scored
+WHERE score >= @min_score
+ORDER BY score DESC, published_at DESC NULLS LAST
+LIMIT @limit
+`
+
+// Suggestions scoring below this share no topic and little text
+const relatedMinScore = 0.3
+
+// preloadLinkedProjects loads an article's projects without their galleries,
+// curated ones first
+func preloadLinkedProjects(db *gorm.DB) *gorm.DB {
+	return selectProjectListColumns(db).Order("position ASC NULLS LAST, created_at DESC")
+}
+
 type articleRepository struct {
 	db *gorm.DB
 }
@@ -39,6 +113,10 @@ func (r *articleRepository) Create(ctx context.Context, article *model.Article)
 func (r *articleRepository) GetByID(ctx context.Context, id string) (*model.Article, error) {
 	var article model.Article
 	err := r.db.WithContext(ctx).
+		Select("articles.*", approvedCommentCount).
+		Preload("Tags").
+		Preload("CoverImage").
+		Preload("Projects", preloadLinkedProjects).
 		Where("id = ?", id).
 		First(&article).Error
 	
@@ -54,6 +132,10 @@ func (r *articleRepository) GetByID(ctx context.Context, id string) (*model.Arti
 func (r *articleRepository) GetBySlug(ctx context.Context, slug string) (*model.Article, error) {
 	var article model.Article
 	err := r.db.WithContext(ctx).
+		Select("articles.*", approvedCommentCount).
+		Preload("Tags").
+		Preload("CoverImage").
+		Preload("Projects", preloadLinkedProjects).
 		Where("slug = ? AND published = ?", slug, true).
 		First(&article).Error
 	
@@ -66,16 +148,36 @@ func (r *articleRepository) GetBySlug(ctx context.Context, slug string) (*model.
 	return &article, nil
 }
 
-func (r *articleRepository) List(ctx context.Context, page, limit int, published bool) ([]model.Article, int64, error) {
-	var articles []model.Article
-	var total int64
+// selectArticleListColumns leaves out the article body, which lists never show
+func selectArticleListColumns(query *gorm.DB) *gorm.DB {
+	return query.Select("id", "title", "slug", "excerpt", "word_count", "reading_time_minutes", "view_count", "like_count", "clap_count", "cover_image_id", "author_id", "published", "position", "published_at", "created_at", "updated_at", approvedCommentCount)
+}
 
+// filtered scopes a list query to published articles and a tag slug
+func (r *articleRepository) filtered(ctx context.Context, published bool, tag string) *gorm.DB {
 	query := r.db.WithContext(ctx).Model(&model.Article{})
 	
 	if published {
 		query = query.Where("published = ?", true)
 	}
 
+	if tag != "" {
+		query = query.Where("id IN (?)", r.db.
+			Table("article_tags").
+			Select("article_tags.article_id").
+			Joins("JOIN tags ON tags.id = article_tags.tag_id").
+			Where("tags.slug = ?", tag))
+	}
+
+	return query
+}
+
+func (r *articleRepository) List(ctx context.Context, page, limit int, published bool, tag string) ([]model.Article, int64, error) {
+	var articles []model.Article
+	var total int64
+
+	query := r.filtered(ctx, published, tag)
+
 	// Count total (before pagination)
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -94,8 +196,9 @@ func (r *articleRepository) List(ctx context.Context, page, limit int, published
 
 	// Get paginated results with optimized query
 	offset := (page - 1) * limit
-	err := query.
-		Select("id", "title", "slug", "excerpt", "author_id", "published", "published_at", "created_at", "updated_at").
+	err := selectArticleListColumns(query).
+		Preload("Tags").
+		Preload("CoverImage").
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -108,18 +211,114 @@ func (r *articleRepository) List(ctx context.Context, page, limit int, published
 	return articles, total, nil
 }
 
+func (r *articleRepository) ListByCursor(ctx context.Context, cursor *model.Cursor, limit int, published bool, tag string) ([]model.Article, bool, error) {
+	var articles []model.Article
+	query := selectArticleListColumns(keyset(r.filtered(ctx, published, tag), "articles", cursor, limit)).
+		Preload("Tags").
+		Preload("CoverImage")
+	if err := query.Find(&articles).Error; err != nil {
+		return nil, false, err
+	}
+
+	more := len(articles) > limit
+	if more {
+		articles = articles[:limit]
+	}
+	if cursor != nil && cursor.Before {
+		reverse(articles)
+	}
+	return articles, more, nil
+}
+
+func (r *articleRepository) ListPinned(ctx context.Context) ([]model.Article, error) {
+	var articles []model.Article
+	err := selectArticleListColumns(r.filtered(ctx, true, "")).
+		Preload("Tags").
+		Preload("CoverImage").
+		Where("position IS NOT NULL").
+		Order("position ASC").
+		Find(&articles).Error
+	return articles, err
+}
+
+func (r *articleRepository) ReorderPinned(ctx context.Context, ids []uuid.UUID) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var found int64
+		if err := tx.Model(&model.Article{}).Where("id IN ?", ids).Count(&found).Error; err != nil {
+			return err
+		}
+		if found != int64(len(ids)) {
+			return ErrArticleNotFound
+		}
+
+		// Pinning isn't an edit, so neither write touches updated_at
+		if err := tx.Model(&model.Article{}).Where("position IS NOT NULL").UpdateColumn("position", nil).Error; err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if err := tx.Model(&model.Article{}).Where("id = ?", id).UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *articleRepository) Count(ctx context.Context, published bool, tag string) (int64, error) {
+	var total int64
+	err := r.filtered(ctx, published, tag).Count(&total).Error
+	return total, err
+}
+
+// ListLatestPublished returns full published articles, newest publication
+// first, for syndication feeds
+func (r *articleRepository) ListLatestPublished(ctx context.Context, tag string, limit int) ([]model.Article, error) {
+	var articles []model.Article
+
+	query := r.db.WithContext(ctx).
+		Preload("Tags").
+		Preload("CoverImage").
+		Where("published = ?", true)
+
+	if tag != "" {
+		query = query.Where("id IN (?)", r.db.
+			Table("article_tags").
+			Select("article_tags.article_id").
+			Joins("JOIN tags ON tags.id = article_tags.tag_id").
+			Where("tags.slug = ?", tag))
+	}
+
+	err := query.
+		Order("published_at DESC NULLS LAST, created_at DESC").
+		Limit(limit).
+		Find(&articles).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
 func (r *articleRepository) Update(ctx context.Context, article *model.Article) error {
 	// Use Updates to only update non-zero fields
 	result := r.db.WithContext(ctx).
 		Model(article).
 		Where("id = ?", article.ID).
 		Updates(map[string]interface{}{
-			"title":       article.Title,
-			"slug":        article.Slug,
-			"excerpt":     article.Excerpt,
-			"content":     article.Content,
-			"published":   article.Published,
-			"published_at": article.PublishedAt,
+			"title":                article.Title,
+			"slug":                 article.Slug,
+			"excerpt":              article.Excerpt,
+			"content":              article.Content,
+			"content_html":         article.ContentHTML,
+			"toc":                  article.TOC,
+			"word_count":           article.WordCount,
+			"reading_time_minutes": article.ReadingTimeMinutes,
+			"published":            article.Published,
+			"noindex":              article.NoIndex,
+			"cover_image_id":       article.CoverImageID,
+			"published_at":         article.PublishedAt,
+			"publish_at":           article.PublishAt,
+			"unpublish_at":         article.UnpublishAt,
 		})
 	
 	if result.Error != nil {
@@ -133,6 +332,158 @@ func (r *articleRepository) Update(ctx context.Context, article *model.Article)
 	return nil
 }
 
+// ReplaceTags swaps the article's tag set for the given tags
+func (r *articleRepository) ReplaceTags(ctx context.Context, article *model.Article, tags []model.Tag) error {
+	return r.db.WithContext(ctx).
+		Model(article).
+		Association("Tags").
+		Replace(tags)
+}
+
+func (r *articleRepository) ReplaceProjects(ctx context.Context, articleID uuid.UUID, projectIDs []uuid.UUID) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var found int64
+		if err := tx.Model(&model.Project{}).Where("id IN ?", projectIDs).Count(&found).Error; err != nil {
+			return err
+		}
+		if found != int64(len(projectIDs)) {
+			return ErrProjectNotFound
+		}
+
+		if err := tx.Exec("DELETE FROM article_projects WHERE article_id = ?", articleID).Error; err != nil {
+			return err
+		}
+		for _, projectID := range projectIDs {
+			if err := tx.Exec("INSERT INTO article_projects (article_id, project_id) VALUES (?, ?)", articleID, projectID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *articleRepository) ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]model.Article, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(relatedArticlesSQL, map[string]interface{}{
+		"id":        id,
+		"min_score": relatedMinScore,
+		"limit":     limit,
+		"tenant":    tenantParam(ctx),
+	}).Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return []model.Article{}, err
+	}
+
+	var articles []model.Article
+	err = selectArticleListColumns(r.db.WithContext(ctx).Model(&model.Article{})).
+		Preload("Tags").
+		Preload("CoverImage").
+		Where("id IN ?", ids).
+		Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Restore the ranking, which IN loses
+	rank := make(map[uuid.UUID]int, len(ids))
+	for i, id := range ids {
+		rank[id] = i
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		return rank[articles[i].ID] < rank[articles[j].ID]
+	})
+	return articles, nil
+}
+
+// ListDuePublish returns drafts whose publish_at has passed. Articles whose
+// unpublish_at has passed too (e.g. after downtime) are left to ListDueUnpublish.
+func (r *articleRepository) ListDuePublish(ctx context.Context, now time.Time) ([]model.Article, error) {
+	var articles []model.Article
+	err := r.db.WithContext(ctx).
+		Where("published = ? AND publish_at <= ?", false, now).
+		Where("unpublish_at IS NULL OR unpublish_at > ?", now).
+		Order("publish_at ASC").
+		Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+func (r *articleRepository) ListDueUnpublish(ctx context.Context, now time.Time) ([]model.Article, error) {
+	var articles []model.Article
+	err := r.db.WithContext(ctx).
+		Where("unpublish_at <= ?", now).
+		Order("unpublish_at ASC").
+		Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// MarkPublished applies a due scheduled publish. It reports false when the
+// article was changed or published by someone else in the meantime.
+func (r *articleRepository) MarkPublished(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.Article{}).
+		Where("id = ? AND published = ? AND publish_at <= ?", id, false, now).
+		Updates(map[string]interface{}{
+			"published":    true,
+			"published_at": gorm.Expr("COALESCE(published_at, publish_at)"),
+			"publish_at":   nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkUnpublished applies a due scheduled unpublish and clears any pending
+// schedule so the article is not published again
+func (r *articleRepository) MarkUnpublished(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.Article{}).
+		Where("id = ? AND unpublish_at <= ?", id, now).
+		Updates(map[string]interface{}{
+			"published":    false,
+			"publish_at":   nil,
+			"unpublish_at": nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListScheduled returns pending publish/unpublish transitions in [from, to)
+func (r *articleRepository) ListScheduled(ctx context.Context, from, to time.Time) ([]model.CalendarEntry, error) {
+	entries := []model.CalendarEntry{}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT id AS article_id, title, slug, CAST(@publish AS text) AS action, publish_at AS at
+		FROM articles
+		WHERE deleted_at IS NULL AND publish_at >= @from AND publish_at < @to
+			AND (CAST(@tenant AS uuid) IS NULL OR tenant_id = @tenant)
+		UNION ALL
+		SELECT id AS article_id, title, slug, CAST(@unpublish AS text) AS action, unpublish_at AS at
+		FROM articles
+		WHERE deleted_at IS NULL AND unpublish_at >= @from AND unpublish_at < @to
+			AND (CAST(@tenant AS uuid) IS NULL OR tenant_id = @tenant)
+		ORDER BY at ASC`,
+		map[string]interface{}{
+			"publish":   model.ScheduleActionPublish,
+			"unpublish": model.ScheduleActionUnpublish,
+			"from":      from,
+			"to":        to,
+			"tenant":    tenantParam(ctx),
+		},
+	).Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
 func (r *articleRepository) Delete(ctx context.Context, id string) error {
 	result := r.db.WithContext(ctx).
 		Where("id = ?", id).
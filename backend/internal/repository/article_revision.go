@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type ArticleRevisionRepository interface {
+	Create(ctx context.Context, revision *model.ArticleRevision) error
+	GetByID(ctx context.Context, articleID, id string) (*model.ArticleRevision, error)
+	GetPrevious(ctx context.Context, revision *model.ArticleRevision) (*model.ArticleRevision, error)
+	List(ctx context.Context, articleID string, includeAutosaves bool) ([]model.ArticleRevision, error)
+}
+
+type articleRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewArticleRevisionRepository accepts either the root DB handle or a
+// transaction, so revisions can be written atomically with the article
+func NewArticleRevisionRepository(db *gorm.DB) ArticleRevisionRepository {
+	return &articleRevisionRepository{db: db}
+}
+
+// Create assigns the next per-article revision number. The article row is
+// locked first so concurrent saves of the same article serialize.
+func (r *articleRevisionRepository) Create(ctx context.Context, revision *model.ArticleRevision) error {
+	db := r.db.WithContext(ctx)
+
+	var locked []string
+	if err := db.Raw("SELECT id FROM articles WHERE id = ? FOR UPDATE", revision.ArticleID).Scan(&locked).Error; err != nil {
+		return err
+	}
+	if len(locked) == 0 {
+		return ErrArticleNotFound
+	}
+
+	if err := db.Raw("SELECT COALESCE(MAX(number), 0) + 1 FROM article_revisions WHERE article_id = ?", revision.ArticleID).
+		Scan(&revision.Number).Error; err != nil {
+		return err
+	}
+
+	return db.Create(revision).Error
+}
+
+func (r *articleRevisionRepository) GetByID(ctx context.Context, articleID, id string) (*model.ArticleRevision, error) {
+	var revision model.ArticleRevision
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND article_id = ?", id, articleID).
+		First(&revision).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetPrevious returns the closest earlier non-autosave revision
+func (r *articleRevisionRepository) GetPrevious(ctx context.Context, revision *model.ArticleRevision) (*model.ArticleRevision, error) {
+	var previous model.ArticleRevision
+	err := r.db.WithContext(ctx).
+		Where("article_id = ? AND number < ? AND autosave = ?", revision.ArticleID, revision.Number, false).
+		Order("number DESC").
+		First(&previous).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return &previous, nil
+}
+
+// List returns revision metadata (without content), newest first
+func (r *articleRevisionRepository) List(ctx context.Context, articleID string, includeAutosaves bool) ([]model.ArticleRevision, error) {
+	revisions := []model.ArticleRevision{}
+
+	query := r.db.WithContext(ctx).Where("article_id = ?", articleID)
+	if !includeAutosaves {
+		query = query.Where("autosave = ?", false)
+	}
+
+	err := query.
+		Select("id", "article_id", "number", "author_id", "title", "slug", "excerpt", "published", "autosave", "restored_from_id", "created_at").
+		Order("number DESC").
+		Find(&revisions).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *model.Comment) error
+	GetByID(ctx context.Context, id string) (*model.Comment, error)
+	ListApproved(ctx context.Context, articleID string) ([]model.Comment, error)
+	List(ctx context.Context, page, limit int, status, articleID string) ([]model.Comment, int64, error)
+	UpdateStatus(ctx context.Context, id, status string, moderatedAt time.Time) error
+	Delete(ctx context.Context, id string) error
+	WithTransaction(ctx context.Context, fn func(*gorm.DB) error) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) WithTransaction(ctx context.Context, fn func(*gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *model.Comment) error {
+	if err := r.db.WithContext(ctx).Create(comment).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *commentRepository) GetByID(ctx context.Context, id string) (*model.Comment, error) {
+	var comment model.Comment
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&comment).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListApproved returns an article's public comments, oldest first
+func (r *commentRepository) ListApproved(ctx context.Context, articleID string) ([]model.Comment, error) {
+	var comments []model.Comment
+	err := r.db.WithContext(ctx).
+		Where("article_id = ? AND status = ?", articleID, model.CommentStatusApproved).
+		Order("created_at ASC").
+		Find(&comments).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// List pages through comments for moderation, newest first
+func (r *commentRepository) List(ctx context.Context, page, limit int, status, articleID string) ([]model.Comment, int64, error) {
+	var comments []model.Comment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Comment{})
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if articleID != "" {
+		query = query.Where("article_id = ?", articleID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	err := query.
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&comments).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+func (r *commentRepository) UpdateStatus(ctx context.Context, id, status string, moderatedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.Comment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"moderated_at": moderatedAt,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the comment; replies go with it through the foreign key
+func (r *commentRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Delete(&model.Comment{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrCommentNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type CounterRepository interface {
+	// Exists reports whether the target can be counted: a published article
+	// or any project
+	Exists(ctx context.Context, target, id string) (bool, error)
+	// Apply adds a batch of deltas in one transaction
+	Apply(ctx context.Context, deltas []model.CounterDelta) error
+}
+
+type counterRepository struct {
+	db *gorm.DB
+}
+
+func NewCounterRepository(db *gorm.DB) CounterRepository {
+	return &counterRepository{db: db}
+}
+
+func counterModel(target string) interface{} {
+	if target == model.CounterTargetProject {
+		return &model.Project{}
+	}
+	return &model.Article{}
+}
+
+func (r *counterRepository) Exists(ctx context.Context, target, id string) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(counterModel(target)).Where("id = ?", id)
+	if target == model.CounterTargetArticle {
+		query = query.Where("published = ?", true)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *counterRepository) Apply(ctx context.Context, deltas []model.CounterDelta) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, d := range deltas {
+			// UpdateColumns leaves updated_at alone: being read isn't an edit.
+			// Rows deleted since the views were buffered simply match nothing.
+			err := tx.Model(counterModel(d.Target)).
+				Where("id = ?", d.ID).
+				UpdateColumns(map[string]interface{}{
+					"view_count": gorm.Expr("view_count + ?", d.Views),
+					"like_count": gorm.Expr("like_count + ?", d.Likes),
+					"clap_count": gorm.Expr("clap_count + ?", d.Claps),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -4,8 +4,15 @@ import "errors"
 
 // Common repository errors
 var (
-	ErrArticleNotFound   = errors.New("article not found")
-	ErrProjectNotFound   = errors.New("project not found")
-	ErrPortfolioNotFound = errors.New("portfolio not found")
+	ErrArticleNotFound      = errors.New("article not found")
+	ErrProjectNotFound      = errors.New("project not found")
+	ErrPortfolioNotFound    = errors.New("portfolio not found")
+	ErrTagNotFound          = errors.New("tag not found")
+	ErrRevisionNotFound     = errors.New("revision not found")
+	ErrPreviewTokenNotFound = errors.New("preview token not found")
+	ErrMediaNotFound        = errors.New("media not found")
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrResumeEntryNotFound  = errors.New("resume entry not found")
+	ErrTranslationNotFound  = errors.New("translation not found")
+	ErrTenantNotFound       = errors.New("tenant not found")
 )
-
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type MediaRepository interface {
+	Create(ctx context.Context, media *model.Media) error
+	GetByID(ctx context.Context, id string) (*model.Media, error)
+	GetByIDs(ctx context.Context, ids []string) ([]model.Media, error)
+	List(ctx context.Context, page, limit int, contentType string) ([]model.Media, int64, error)
+	UpdateAlt(ctx context.Context, id, alt string) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+	SaveProcessed(ctx context.Context, media *model.Media) error
+	ListPending(ctx context.Context, createdBefore time.Time, limit int) ([]model.Media, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+func (r *mediaRepository) Create(ctx context.Context, media *model.Media) error {
+	if err := r.db.WithContext(ctx).Create(media).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *mediaRepository) GetByID(ctx context.Context, id string) (*model.Media, error) {
+	var media model.Media
+	err := r.db.WithContext(ctx).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("width ASC, format ASC")
+		}).
+		Where("id = ?", id).
+		First(&media).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMediaNotFound
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
+// GetByIDs returns ErrMediaNotFound unless every id resolves to a media item
+func (r *mediaRepository) GetByIDs(ctx context.Context, ids []string) ([]model.Media, error) {
+	media := []model.Media{}
+	if len(ids) == 0 {
+		return media, nil
+	}
+
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&media).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(media))
+	for _, m := range media {
+		found[m.ID.String()] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, ErrMediaNotFound
+		}
+	}
+	return media, nil
+}
+
+// List pages through the library, newest first. contentType filters by
+// prefix, so "image/" matches every image.
+func (r *mediaRepository) List(ctx context.Context, page, limit int, contentType string) ([]model.Media, int64, error) {
+	var media []model.Media
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Media{})
+
+	if contentType != "" {
+		query = query.Where("content_type LIKE ?", escapeLike(contentType)+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	err := query.
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&media).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+	return media, total, nil
+}
+
+func (r *mediaRepository) UpdateAlt(ctx context.Context, id, alt string) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.Media{}).
+		Where("id = ?", id).
+		Update("alt", alt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrMediaNotFound
+	}
+
+	return nil
+}
+
+func (r *mediaRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.Media{}).
+		Where("id = ?", id).
+		Update("status", status)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// SaveProcessed stores the pipeline's results: the media's dimensions,
+// placeholder and status, and its variants in place of any previous ones
+func (r *mediaRepository) SaveProcessed(ctx context.Context, media *model.Media) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Media{}).
+			Where("id = ?", media.ID).
+			Updates(map[string]interface{}{
+				"width":        media.Width,
+				"height":       media.Height,
+				"blur_hash":    media.BlurHash,
+				"status":       media.Status,
+				"processed_at": media.ProcessedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMediaNotFound
+		}
+
+		if err := tx.Where("media_id = ?", media.ID).Delete(&model.MediaVariant{}).Error; err != nil {
+			return err
+		}
+		if len(media.Variants) == 0 {
+			return nil
+		}
+		return tx.Create(&media.Variants).Error
+	})
+}
+
+// ListPending returns media still waiting for processing that were uploaded
+// before createdBefore, oldest first
+func (r *mediaRepository) ListPending(ctx context.Context, createdBefore time.Time, limit int) ([]model.Media, error) {
+	var media []model.Media
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND created_at < ?", model.MediaStatusPending, createdBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&media).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+func (r *mediaRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Delete(&model.Media{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// escapeLike escapes LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
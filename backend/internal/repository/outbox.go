@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	// Create records events; pass a transaction's DB to NewOutboxRepository
+	// so they commit or roll back with the change they describe
+	Create(ctx context.Context, events ...*model.OutboxEvent) error
+	// ListPending returns up to limit events neither sent nor parked, oldest
+	// first
+	ListPending(ctx context.Context, limit int) ([]model.OutboxEvent, error)
+	MarkSent(ctx context.Context, ids []int64, sentAt time.Time) error
+	// MarkFailed counts a failed publish attempt against each event
+	MarkFailed(ctx context.Context, ids []int64, reason string) error
+	// Park counts a last failed attempt against an event and stops relaying
+	// it; the row is kept so it can be replayed
+	Park(ctx context.Context, id int64, reason string, failedAt time.Time) error
+	// DeleteSentBefore prunes events published before the cutoff
+	DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, events ...*model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(events).Error
+}
+
+func (r *outboxRepository) ListPending(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := r.db.WithContext(ctx).
+		Where("sent_at IS NULL AND failed_at IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, ids []int64, sentAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"sent_at":    sentAt,
+			"last_error": "",
+		}).Error
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, ids []int64, reason string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error
+}
+
+func (r *outboxRepository) Park(ctx context.Context, id int64, reason string, failedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+			"failed_at":  failedAt,
+		}).Error
+}
+
+func (r *outboxRepository) DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("sent_at IS NOT NULL AND sent_at < ?", cutoff).
+		Delete(&model.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
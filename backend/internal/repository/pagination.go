@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// keyset orders query newest first by (created_at, id) and limits it to the
+// rows next to the cursor, plus one that shows whether more remain. Pages
+// before the cursor are read oldest first and must be reversed.
+func keyset(query *gorm.DB, table string, cursor *model.Cursor, limit int) *gorm.DB {
+	direction := "DESC"
+	if cursor != nil {
+		op := "<"
+		if cursor.Before {
+			op, direction = ">", "ASC"
+		}
+		query = query.Where(fmt.Sprintf("(%[1]s.created_at, %[1]s.id) %[2]s (?, ?)", table, op), cursor.CreatedAt, cursor.ID)
+	}
+	return query.
+		Order(fmt.Sprintf("%[1]s.created_at %[2]s, %[1]s.id %[2]s", table, direction)).
+		Limit(limit + 1)
+}
+
+func reverse[T any](items []T) {
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type PreviewTokenRepository interface {
+	Create(ctx context.Context, token *model.PreviewToken) error
+	GetByID(ctx context.Context, id string) (*model.PreviewToken, error)
+	ListByArticle(ctx context.Context, articleID string) ([]model.PreviewToken, error)
+	Revoke(ctx context.Context, articleID, id string) error
+}
+
+type previewTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPreviewTokenRepository(db *gorm.DB) PreviewTokenRepository {
+	return &previewTokenRepository{db: db}
+}
+
+func (r *previewTokenRepository) Create(ctx context.Context, token *model.PreviewToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *previewTokenRepository) GetByID(ctx context.Context, id string) (*model.PreviewToken, error) {
+	var token model.PreviewToken
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&token).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPreviewTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *previewTokenRepository) ListByArticle(ctx context.Context, articleID string) ([]model.PreviewToken, error) {
+	tokens := []model.PreviewToken{}
+	err := r.db.WithContext(ctx).
+		Where("article_id = ?", articleID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *previewTokenRepository) Revoke(ctx context.Context, articleID, id string) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.PreviewToken{}).
+		Where("id = ? AND article_id = ? AND revoked_at IS NULL", id, articleID).
+		Update("revoked_at", time.Now().UTC())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrPreviewTokenNotFound
+	}
+
+	return nil
+}
@@ -2,16 +2,41 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
+	"github.com/google/uuid"
 	"github.com/portfolio/backend/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProjectRepository interface {
 	Create(ctx context.Context, project *model.Project) error
 	GetByID(ctx context.Context, id string) (*model.Project, error)
-	List(ctx context.Context, page, limit int, featured *bool) ([]model.Project, int64, error)
+	List(ctx context.Context, page, limit int, filter model.ProjectFilter) ([]model.Project, int64, error)
+	// ListByCursor returns up to limit projects next to the cursor, newest
+	// first, and whether more lie beyond them; a nil cursor starts at the top.
+	// The filter's sort is ignored.
+	ListByCursor(ctx context.Context, cursor *model.Cursor, limit int, filter model.ProjectFilter) ([]model.Project, bool, error)
+	Count(ctx context.Context, filter model.ProjectFilter) (int64, error)
+	// TechnologyFacets counts the filtered projects per technology, most
+	// used first
+	TechnologyFacets(ctx context.Context, filter model.ProjectFilter) ([]model.TechnologyFacet, error)
 	Update(ctx context.Context, project *model.Project) error
+	ReplaceGallery(ctx context.Context, projectID uuid.UUID, items []model.ProjectMedia) error
+	// ReplaceArticles links the project to exactly the given articles
+	ReplaceArticles(ctx context.Context, projectID uuid.UUID, articleIDs []uuid.UUID) error
+	// ListGithubLinked returns up to limit projects with a GitHub URL, least
+	// recently synced first
+	ListGithubLinked(ctx context.Context, limit int) ([]model.Project, error)
+	// SaveGithub stores the synced GitHub metadata of a project
+	SaveGithub(ctx context.Context, info *model.ProjectGithub) error
+	DeleteGithub(ctx context.Context, projectID uuid.UUID) error
+	UpdateTechnologies(ctx context.Context, id uuid.UUID, technologies model.StringArray) error
+	// Reorder gives the projects positions in the order listed and clears
+	// the position of every other project
+	Reorder(ctx context.Context, ids []uuid.UUID) error
 	Delete(ctx context.Context, id string) error
 	WithTransaction(ctx context.Context, fn func(*gorm.DB) error) error
 }
@@ -38,6 +63,15 @@ func (r *projectRepository) Create(ctx context.Context, project *model.Project)
 func (r *projectRepository) GetByID(ctx context.Context, id string) (*model.Project, error) {
 	var project model.Project
 	err := r.db.WithContext(ctx).
+		Preload("CoverImage").
+		Preload("Gallery", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Gallery.Media").
+		Preload("Github").
+		Preload("Articles", func(db *gorm.DB) *gorm.DB {
+			return selectArticleListColumns(db).Where("published = ?", true).Order("published_at DESC")
+		}).
 		Where("id = ?", id).
 		First(&project).Error
 	
@@ -50,15 +84,85 @@ func (r *projectRepository) GetByID(ctx context.Context, id string) (*model.Proj
 	return &project, nil
 }
 
-func (r *projectRepository) List(ctx context.Context, page, limit int, featured *bool) ([]model.Project, int64, error) {
-	var projects []model.Project
-	var total int64
-
+// filtered scopes a list query to the filter; sorting is left to the caller
+func (r *projectRepository) filtered(ctx context.Context, filter model.ProjectFilter) *gorm.DB {
 	query := r.db.WithContext(ctx).Model(&model.Project{})
 	
-	if featured != nil {
-		query = query.Where("featured = ?", *featured)
+	if filter.Featured != nil {
+		query = query.Where("featured = ?", *filter.Featured)
+	}
+
+	// Containment keeps the match on the GIN index
+	if len(filter.Technologies) > 0 {
+		conditions := make([]string, len(filter.Technologies))
+		args := make([]interface{}, len(filter.Technologies))
+		for i, technology := range filter.Technologies {
+			encoded, _ := json.Marshal([]string{technology})
+			conditions[i] = "projects.technologies @> ?"
+			args[i] = string(encoded)
+		}
+		joiner := " OR "
+		if filter.MatchAll {
+			joiner = " AND "
+		}
+		query = query.Where(strings.Join(conditions, joiner), args...)
+	}
+
+	if filter.Search != "" {
+		pattern := "%" + escapeLike(filter.Search) + "%"
+		query = query.Where("projects.name ILIKE ? OR projects.description ILIKE ?", pattern, pattern)
+	}
+
+	if filter.CreatedFrom != nil {
+		query = query.Where("projects.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedUntil != nil {
+		query = query.Where("projects.created_at < ?", *filter.CreatedUntil)
+	}
+	if filter.UpdatedFrom != nil {
+		query = query.Where("projects.updated_at >= ?", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedUntil != nil {
+		query = query.Where("projects.updated_at < ?", *filter.UpdatedUntil)
+	}
+
+	return query
+}
+
+// sorted orders a list by the filter's sort fields, with the id as a final
+// tie-breaker so pages are stable
+func sorted(query *gorm.DB, fields []model.SortField) *gorm.DB {
+	if len(fields) == 0 {
+		fields = []model.SortField{{Column: "created_at", Desc: true}}
+	}
+	for _, field := range fields {
+		// Unordered projects follow the curated ones either way
+		if field.Column == "position" {
+			direction := "ASC"
+			if field.Desc {
+				direction = "DESC"
+			}
+			query = query.Order("projects.position " + direction + " NULLS LAST")
+			continue
+		}
+		query = query.Order(clause.OrderByColumn{
+			Column: clause.Column{Table: "projects", Name: field.Column},
+			Desc:   field.Desc,
+		})
 	}
+	return query.Order("projects.id DESC")
+}
+
+// selectProjectListColumns leaves out the gallery, which lists never show
+func selectProjectListColumns(query *gorm.DB) *gorm.DB {
+	return query.Select("id", "name", "description", "github_url", "live_url", "technologies", "featured", "position", "cover_image_id", "view_count", "like_count", "clap_count", "created_at", "updated_at")
+}
+
+func (r *projectRepository) List(ctx context.Context, page, limit int, filter model.ProjectFilter) ([]model.Project, int64, error) {
+	var projects []model.Project
+	var total int64
+
+	query := r.filtered(ctx, filter)
 
 	// Count total (before pagination)
 	if err := query.Count(&total).Error; err != nil {
@@ -78,9 +182,9 @@ func (r *projectRepository) List(ctx context.Context, page, limit int, featured
 
 	// Get paginated results with optimized query
 	offset := (page - 1) * limit
-	err := query.
-		Select("id", "name", "description", "github_url", "live_url", "technologies", "featured", "created_at", "updated_at").
-		Order("created_at DESC").
+	err := sorted(selectProjectListColumns(query), filter.Sort).
+		Preload("CoverImage").
+		Preload("Github").
 		Offset(offset).
 		Limit(limit).
 		Find(&projects).Error
@@ -92,6 +196,42 @@ func (r *projectRepository) List(ctx context.Context, page, limit int, featured
 	return projects, total, nil
 }
 
+func (r *projectRepository) ListByCursor(ctx context.Context, cursor *model.Cursor, limit int, filter model.ProjectFilter) ([]model.Project, bool, error) {
+	var projects []model.Project
+	query := selectProjectListColumns(keyset(r.filtered(ctx, filter), "projects", cursor, limit)).
+		Preload("CoverImage").
+		Preload("Github")
+	if err := query.Find(&projects).Error; err != nil {
+		return nil, false, err
+	}
+
+	more := len(projects) > limit
+	if more {
+		projects = projects[:limit]
+	}
+	if cursor != nil && cursor.Before {
+		reverse(projects)
+	}
+	return projects, more, nil
+}
+
+func (r *projectRepository) Count(ctx context.Context, filter model.ProjectFilter) (int64, error) {
+	var total int64
+	err := r.filtered(ctx, filter).Count(&total).Error
+	return total, err
+}
+
+func (r *projectRepository) TechnologyFacets(ctx context.Context, filter model.ProjectFilter) ([]model.TechnologyFacet, error) {
+	facets := []model.TechnologyFacet{}
+	err := r.filtered(ctx, filter).
+		Select("tech.value AS technology, COUNT(*) AS count").
+		Joins("CROSS JOIN LATERAL jsonb_array_elements_text(projects.technologies) AS tech(value)").
+		Group("tech.value").
+		Order("count DESC, technology ASC").
+		Scan(&facets).Error
+	return facets, err
+}
+
 func (r *projectRepository) Update(ctx context.Context, project *model.Project) error {
 	// Use Updates to only update non-zero fields
 	result := r.db.WithContext(ctx).
@@ -104,6 +244,7 @@ func (r *projectRepository) Update(ctx context.Context, project *model.Project)
 			"live_url":    project.LiveURL,
 			"technologies": project.Technologies,
 			"featured":    project.Featured,
+			"cover_image_id": project.CoverImageID,
 		})
 	
 	if result.Error != nil {
@@ -117,6 +258,23 @@ func (r *projectRepository) Update(ctx context.Context, project *model.Project)
 	return nil
 }
 
+// ReplaceGallery swaps the project's gallery for items, in the given order
+func (r *projectRepository) ReplaceGallery(ctx context.Context, projectID uuid.UUID, items []model.ProjectMedia) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectMedia{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		for i := range items {
+			items[i].ProjectID = projectID
+			items[i].Position = i
+		}
+		return tx.Omit("Media").Create(&items).Error
+	})
+}
+
 func (r *projectRepository) Delete(ctx context.Context, id string) error {
 	result := r.db.WithContext(ctx).
 		Where("id = ?", id).
@@ -133,3 +291,85 @@ func (r *projectRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (r *projectRepository) Reorder(ctx context.Context, ids []uuid.UUID) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var found int64
+		if err := tx.Model(&model.Project{}).Where("id IN ?", ids).Count(&found).Error; err != nil {
+			return err
+		}
+		if found != int64(len(ids)) {
+			return ErrProjectNotFound
+		}
+
+		// Positions aren't content, so neither write touches updated_at
+		if err := tx.Model(&model.Project{}).Where("position IS NOT NULL").UpdateColumn("position", nil).Error; err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if err := tx.Model(&model.Project{}).Where("id = ?", id).UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *projectRepository) ReplaceArticles(ctx context.Context, projectID uuid.UUID, articleIDs []uuid.UUID) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var found int64
+		if err := tx.Model(&model.Article{}).Where("id IN ?", articleIDs).Count(&found).Error; err != nil {
+			return err
+		}
+		if found != int64(len(articleIDs)) {
+			return ErrArticleNotFound
+		}
+
+		if err := tx.Exec("DELETE FROM article_projects WHERE project_id = ?", projectID).Error; err != nil {
+			return err
+		}
+		for _, articleID := range articleIDs {
+			if err := tx.Exec("INSERT INTO article_projects (article_id, project_id) VALUES (?, ?)", articleID, projectID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *projectRepository) ListGithubLinked(ctx context.Context, limit int) ([]model.Project, error) {
+	var projects []model.Project
+	err := r.db.WithContext(ctx).
+		Select("id", "tenant_id", "github_url", "technologies").
+		Preload("Github").
+		Where("github_url <> ''").
+		Order("(SELECT synced_at FROM project_github WHERE project_github.project_id = projects.id) ASC NULLS FIRST").
+		Limit(limit).
+		Find(&projects).Error
+	return projects, err
+}
+
+func (r *projectRepository) SaveGithub(ctx context.Context, info *model.ProjectGithub) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(info).Error
+}
+
+func (r *projectRepository) DeleteGithub(ctx context.Context, projectID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Delete(&model.ProjectGithub{}).Error
+}
+
+func (r *projectRepository) UpdateTechnologies(ctx context.Context, id uuid.UUID, technologies model.StringArray) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.Project{}).
+		Where("id = ?", id).
+		Update("technologies", technologies)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// ResumeRepository stores the entries of one resume section
+type ResumeRepository[T any] interface {
+	WithTransaction(ctx context.Context, fn func(*gorm.DB) error) error
+	// WithTx returns the repository bound to a transaction
+	WithTx(tx *gorm.DB) ResumeRepository[T]
+	// List returns the section in display order: curated entries first,
+	// then the rest, most recent first
+	List(ctx context.Context) ([]T, error)
+	GetByID(ctx context.Context, id string) (*T, error)
+	Create(ctx context.Context, entry *T) error
+	// Update overwrites every field but the id, position and creation time
+	Update(ctx context.Context, id string, entry *T) error
+	Delete(ctx context.Context, id string) error
+	// Reorder gives the entries positions in the order listed and clears
+	// the position of every other entry in the section
+	Reorder(ctx context.Context, ids []uuid.UUID) error
+}
+
+type resumeRepository[T any] struct {
+	db *gorm.DB
+	// Order of entries without a position
+	order string
+}
+
+func NewExperienceRepository(db *gorm.DB) ResumeRepository[model.Experience] {
+	return &resumeRepository[model.Experience]{db: db, order: "start_date DESC"}
+}
+
+func NewEducationRepository(db *gorm.DB) ResumeRepository[model.Education] {
+	return &resumeRepository[model.Education]{db: db, order: "start_date DESC"}
+}
+
+func NewSkillRepository(db *gorm.DB) ResumeRepository[model.Skill] {
+	return &resumeRepository[model.Skill]{db: db, order: "category ASC, name ASC"}
+}
+
+func NewCertificationRepository(db *gorm.DB) ResumeRepository[model.Certification] {
+	return &resumeRepository[model.Certification]{db: db, order: "issued_on DESC"}
+}
+
+func NewAwardRepository(db *gorm.DB) ResumeRepository[model.Award] {
+	return &resumeRepository[model.Award]{db: db, order: "awarded_on DESC"}
+}
+
+func (r *resumeRepository[T]) WithTransaction(ctx context.Context, fn func(*gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *resumeRepository[T]) WithTx(tx *gorm.DB) ResumeRepository[T] {
+	return &resumeRepository[T]{db: tx, order: r.order}
+}
+
+func (r *resumeRepository[T]) List(ctx context.Context) ([]T, error) {
+	entries := []T{}
+	err := r.db.WithContext(ctx).
+		Order("position ASC NULLS LAST").
+		Order(r.order).
+		Order("id ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *resumeRepository[T]) GetByID(ctx context.Context, id string) (*T, error) {
+	var entry T
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResumeEntryNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *resumeRepository[T]) Create(ctx context.Context, entry *T) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *resumeRepository[T]) Update(ctx context.Context, id string, entry *T) error {
+	// Select("*") writes zero values too, so fields can be cleared
+	result := r.db.WithContext(ctx).
+		Model(new(T)).
+		Where("id = ?", id).
+		Select("*").
+		Omit("id", "tenant_id", "position", "created_at").
+		Updates(entry)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrResumeEntryNotFound
+	}
+
+	return nil
+}
+
+func (r *resumeRepository[T]) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Delete(new(T))
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrResumeEntryNotFound
+	}
+
+	return nil
+}
+
+func (r *resumeRepository[T]) Reorder(ctx context.Context, ids []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var found int64
+		if err := tx.Model(new(T)).Where("id IN ?", ids).Count(&found).Error; err != nil {
+			return err
+		}
+		if found != int64(len(ids)) {
+			return ErrResumeEntryNotFound
+		}
+
+		if err := tx.Model(new(T)).Where("position IS NOT NULL").UpdateColumn("position", nil).Error; err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if err := tx.Model(new(T)).Where("id = ?", id).UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// SearchQuery describes a full-text search request across content types
+type SearchQuery struct {
+	Query      string
+	Types      []string
+	Technology string
+	Page       int
+	Limit      int
+}
+
+// SearchRepository is implemented by every search backend (Postgres today,
+// an embedded index could be plugged in later without touching the service)
+type SearchRepository interface {
+	Search(ctx context.Context, query SearchQuery) (*model.SearchResult, error)
+}
+
+// Minimum pg_trgm word similarity for a title/name to count as a typo match
+const searchSimilarityThreshold = 0.4
+
+// searchHitsSQL collects the tenant's matching articles and projects in one
+// relation. Rows match either the tsvector index or, for typo tolerance, a trigram match on
+// the title, project name or technologies. Highlights and snippets are HTML:
+// the text is escaped before ts_headline wraps matches in <mark>.
+var searchHitsSQL = `
+WITH q AS (
+	SELECT websearch_to_tsquery('english', @query) AS tsq, lower(@query) AS raw
+), hits AS (
+	SELECT 'article' AS type, a.id, a.title, a.slug,
+		ts_headline('english', ` + htmlEscapeSQL("a.title") + `, q.tsq, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight,
+		ts_headline('english', ` + htmlEscapeSQL("coalesce(a.excerpt, '') || ' ' || a.content") + `, q.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS snippet,
+		NULL::jsonb AS technologies,
+		ts_rank_cd(a.search_vector, q.tsq) + word_similarity(q.raw, lower(a.title)) AS score,
+		a.created_at
+	FROM articles a, q
+	WHERE a.deleted_at IS NULL
+		AND a.published = true
+		AND (CAST(@tenant AS uuid) IS NULL OR a.tenant_id = @tenant)
+		AND (a.search_vector @@ q.tsq OR q.raw <% lower(a.title))
+	UNION ALL
+	SELECT 'project' AS type, p.id, p.name AS title, '' AS slug,
+		ts_headline('english', ` + htmlEscapeSQL("p.name") + `, q.tsq, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight,
+		ts_headline('english', ` + htmlEscapeSQL("coalesce(p.description, '')") + `, q.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS snippet,
+		p.technologies,
+		ts_rank_cd(p.search_vector, q.tsq) + word_similarity(q.raw, lower(p.name)) AS score,
+		p.created_at
+	FROM projects p, q
+	WHERE p.deleted_at IS NULL
+		AND (CAST(@tenant AS uuid) IS NULL OR p.tenant_id = @tenant)
+		AND (p.search_vector @@ q.tsq OR q.raw <% lower(p.name) OR q.raw <% lower(p.technologies::text))
+)
+`
+
+// htmlEscapeSQL wraps a SQL text expression so it evaluates to the text
+// escaped for HTML, as html.EscapeString would
+func htmlEscapeSQL(expr string) string {
+	return "replace(replace(replace(replace(replace(" + expr +
+		", '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '\"', '&#34;'), '''', '&#39;')"
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+func (r *searchRepository) Search(ctx context.Context, query SearchQuery) (*model.SearchResult, error) {
+	// Validate pagination
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = 10
+	}
+	if query.Limit > 100 {
+		query.Limit = 100
+	}
+
+	params := map[string]interface{}{
+		"query":      query.Query,
+		"types":      query.Types,
+		"technology": query.Technology,
+		"limit":      query.Limit,
+		"offset":     (query.Page - 1) * query.Limit,
+		"tenant":     tenantParam(ctx),
+	}
+
+	// Facets ignore their own filter so the UI can still offer the other values
+	typeFilter := ""
+	if len(query.Types) > 0 {
+		typeFilter = "type IN @types"
+	}
+	techFilter := ""
+	if query.Technology != "" {
+		techFilter = "technologies @> jsonb_build_array(CAST(@technology AS text))"
+	}
+	where := joinConditions(typeFilter, techFilter)
+
+	result := &model.SearchResult{
+		Hits: []model.SearchHit{},
+		Facets: model.SearchFacets{
+			Types:        []model.FacetCount{},
+			Technologies: []model.FacetCount{},
+		},
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SET does not accept bind parameters; the threshold is a constant
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.word_similarity_threshold = %.2f", searchSimilarityThreshold)).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Raw(searchHitsSQL+"SELECT * FROM hits"+where+" ORDER BY score DESC, created_at DESC LIMIT @limit OFFSET @offset", params).
+			Scan(&result.Hits).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Raw(searchHitsSQL+"SELECT COUNT(*) FROM hits"+where, params).
+			Scan(&result.Total).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Raw(searchHitsSQL+"SELECT type AS value, COUNT(*) AS count FROM hits"+joinConditions(techFilter)+" GROUP BY type ORDER BY count DESC", params).
+			Scan(&result.Facets.Types).Error; err != nil {
+			return err
+		}
+
+		techFacetFilter := joinConditions(typeFilter, "technologies IS NOT NULL")
+		return tx.Raw(searchHitsSQL+"SELECT tech.value, COUNT(*) AS count FROM hits, jsonb_array_elements_text(hits.technologies) AS tech(value)"+techFacetFilter+" GROUP BY tech.value ORDER BY count DESC, tech.value", params).
+			Scan(&result.Facets.Technologies).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// joinConditions builds a WHERE clause from the non-empty conditions
+func joinConditions(conditions ...string) string {
+	var parts []string
+	for _, c := range conditions {
+		if c != "" {
+			parts = append(parts, c)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(parts, " AND ")
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"html"
+	"regexp"
+	"testing"
+)
+
+func TestHTMLEscapeSQL(t *testing.T) {
+	db := openScopedDB(t)
+
+	for _, s := range []string{
+		"Plain title",
+		`<script>alert("x")</script>`,
+		"Tom & Jerry's <b>tips</b>",
+		"&amp; already escaped",
+	} {
+		var got string
+		if err := db.Raw("SELECT "+htmlEscapeSQL("@text"), map[string]interface{}{"text": s}).Scan(&got).Error; err != nil {
+			t.Fatal(err)
+		}
+		if want := html.EscapeString(s); got != want {
+			t.Errorf("escaped %q to %q, want %q", s, got, want)
+		}
+	}
+}
+
+// A title's markup comes back escaped, leaving the <mark> tags ts_headline
+// adds as the only HTML in a highlight
+func TestSearchHighlightEscapesTitle(t *testing.T) {
+	db := openScopedDB(t)
+
+	// replace() stands in for ts_headline, which SQLite lacks, marking "Go"
+	title := `<script>alert("Go")</script> Go's tips`
+	var got string
+	err := db.Raw("SELECT replace("+htmlEscapeSQL("@title")+", 'Go', '<mark>Go</mark>')",
+		map[string]interface{}{"title": title}).Scan(&got).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `&lt;script&gt;alert(&#34;<mark>Go</mark>&#34;)&lt;/script&gt; <mark>Go</mark>&#39;s tips`
+	if got != want {
+		t.Errorf("highlight = %q, want %q", got, want)
+	}
+
+	// Every highlight and snippet the query builds escapes its text first
+	headlines := regexp.MustCompile(`ts_headline\('english', ([a-z]+)\(`).FindAllStringSubmatch(searchHitsSQL, -1)
+	if len(headlines) != 4 {
+		t.Fatalf("found %d ts_headline calls, want 4", len(headlines))
+	}
+	for _, m := range headlines {
+		if m[1] != "replace" {
+			t.Errorf("ts_headline over %s(...), not escaped text", m[1])
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// sitemapEntriesSQL lists every indexable page of the tenant in a stable order so the
+// sitemap can be split into files by offset. Each translation of a page is
+// a page of its own, and every one carries the keys of all the others.
+const sitemapEntriesSQL = `
+SELECT 'article' AS type, '' AS locale, a.slug AS key, a.slug AS default_key, a.updated_at,
+    (SELECT jsonb_agg(jsonb_build_object('locale', t.locale, 'key', t.slug) ORDER BY t.locale)
+     FROM article_translations t WHERE t.article_id = a.id) AS translations
+FROM articles a
+WHERE a.deleted_at IS NULL AND a.published = true AND a.noindex = false
+    AND (CAST(@tenant AS uuid) IS NULL OR a.tenant_id = @tenant)
+UNION ALL
+SELECT 'article' AS type, tr.locale, tr.slug AS key, a.slug AS default_key, GREATEST(a.updated_at, tr.updated_at) AS updated_at,
+    (SELECT jsonb_agg(jsonb_build_object('locale', t.locale, 'key', t.slug) ORDER BY t.locale)
+     FROM article_translations t WHERE t.article_id = a.id) AS translations
+FROM article_translations tr
+JOIN articles a ON a.id = tr.article_id
+WHERE a.deleted_at IS NULL AND a.published = true AND a.noindex = false
+    AND (CAST(@tenant AS uuid) IS NULL OR a.tenant_id = @tenant)
+UNION ALL
+SELECT 'project' AS type, '' AS locale, CAST(p.id AS text) AS key, CAST(p.id AS text) AS default_key, p.updated_at,
+    (SELECT jsonb_agg(jsonb_build_object('locale', t.locale, 'key', CAST(p.id AS text)) ORDER BY t.locale)
+     FROM project_translations t WHERE t.project_id = p.id) AS translations
+FROM projects p
+WHERE p.deleted_at IS NULL
+    AND (CAST(@tenant AS uuid) IS NULL OR p.tenant_id = @tenant)
+UNION ALL
+SELECT 'project' AS type, tr.locale, CAST(p.id AS text) AS key, CAST(p.id AS text) AS default_key, GREATEST(p.updated_at, tr.updated_at) AS updated_at,
+    (SELECT jsonb_agg(jsonb_build_object('locale', t.locale, 'key', CAST(p.id AS text)) ORDER BY t.locale)
+     FROM project_translations t WHERE t.project_id = p.id) AS translations
+FROM project_translations tr
+JOIN projects p ON p.id = tr.project_id
+WHERE p.deleted_at IS NULL
+    AND (CAST(@tenant AS uuid) IS NULL OR p.tenant_id = @tenant)
+`
+
+type SitemapRepository interface {
+	Count(ctx context.Context) (int64, error)
+	List(ctx context.Context, offset, limit int) ([]model.SitemapEntry, error)
+}
+
+type sitemapRepository struct {
+	db *gorm.DB
+}
+
+func NewSitemapRepository(db *gorm.DB) SitemapRepository {
+	return &sitemapRepository{db: db}
+}
+
+func (r *sitemapRepository) Count(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).
+		Raw("SELECT COUNT(*) FROM ("+sitemapEntriesSQL+") entries", map[string]interface{}{"tenant": tenantParam(ctx)}).
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *sitemapRepository) List(ctx context.Context, offset, limit int) ([]model.SitemapEntry, error) {
+	entries := []model.SitemapEntry{}
+	if limit < 1 {
+		return entries, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Raw(sitemapEntriesSQL+" ORDER BY type, default_key, locale LIMIT @limit OFFSET @offset", map[string]interface{}{
+			"tenant": tenantParam(ctx),
+			"limit":  limit,
+			"offset": offset,
+		}).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	Create(ctx context.Context, tag *model.Tag) error
+	GetByID(ctx context.Context, id string) (*model.Tag, error)
+	GetBySlug(ctx context.Context, slug string) (*model.Tag, error)
+	GetBySlugs(ctx context.Context, slugs []string) ([]model.Tag, error)
+	List(ctx context.Context, kind string) ([]model.Tag, error)
+	Update(ctx context.Context, tag *model.Tag) error
+	Delete(ctx context.Context, id string) error
+	WithTransaction(ctx context.Context, fn func(*gorm.DB) error) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) WithTransaction(ctx context.Context, fn func(*gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *model.Tag) error {
+	if err := r.db.WithContext(ctx).Create(tag).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, id string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&tag).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) GetBySlug(ctx context.Context, slug string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.WithContext(ctx).
+		Where("slug = ?", slug).
+		First(&tag).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetBySlugs returns ErrTagNotFound unless every slug resolves to a tag
+func (r *tagRepository) GetBySlugs(ctx context.Context, slugs []string) ([]model.Tag, error) {
+	tags := []model.Tag{}
+	if len(slugs) == 0 {
+		return tags, nil
+	}
+
+	if err := r.db.WithContext(ctx).Where("slug IN ?", slugs).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		found[t.Slug] = true
+	}
+	for _, slug := range slugs {
+		if !found[slug] {
+			return nil, ErrTagNotFound
+		}
+	}
+	return tags, nil
+}
+
+// List returns tags with the number of published articles attached to each
+func (r *tagRepository) List(ctx context.Context, kind string) ([]model.Tag, error) {
+	var tags []model.Tag
+
+	query := r.db.WithContext(ctx).
+		Model(&model.Tag{}).
+		Select("tags.*, COUNT(articles.id) AS article_count").
+		Joins("LEFT JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.published = ? AND articles.deleted_at IS NULL", true)
+
+	if kind != "" {
+		query = query.Where("tags.kind = ?", kind)
+	}
+
+	err := query.
+		Group("tags.id").
+		Order("tags.name ASC").
+		Find(&tags).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *model.Tag) error {
+	result := r.db.WithContext(ctx).
+		Model(tag).
+		Where("id = ?", tag.ID).
+		Updates(map[string]interface{}{
+			"name":        tag.Name,
+			"slug":        tag.Slug,
+			"kind":        tag.Kind,
+			"description": tag.Description,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTagNotFound
+	}
+
+	return nil
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id string) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM article_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", id).Delete(&model.Tag{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrTagNotFound
+		}
+
+		return nil
+	})
+}
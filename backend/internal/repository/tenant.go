@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type TenantRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Tenant, error)
+	GetBySlug(ctx context.Context, slug string) (*model.Tenant, error)
+	// GetByHost returns the tenant serving a host name
+	GetByHost(ctx context.Context, host string) (*model.Tenant, error)
+	Create(ctx context.Context, t *model.Tenant) error
+}
+
+type tenantRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantRepository(db *gorm.DB) TenantRepository {
+	return &tenantRepository{db: db}
+}
+
+func (r *tenantRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Tenant, error) {
+	return r.get(ctx, "id = ?", id)
+}
+
+func (r *tenantRepository) GetBySlug(ctx context.Context, slug string) (*model.Tenant, error) {
+	return r.get(ctx, "slug = ?", slug)
+}
+
+func (r *tenantRepository) GetByHost(ctx context.Context, host string) (*model.Tenant, error) {
+	return r.get(ctx, "hosts @> jsonb_build_array(CAST(? AS text))", strings.ToLower(host))
+}
+
+func (r *tenantRepository) get(ctx context.Context, query string, args ...interface{}) (*model.Tenant, error) {
+	var t model.Tenant
+	err := r.db.WithContext(ctx).Where(query, args...).First(&t).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *tenantRepository) Create(ctx context.Context, t *model.Tenant) error {
+	return r.db.WithContext(ctx).Create(t).Error
+}
+
+var tenantIDType = reflect.TypeOf(uuid.UUID{})
+
+// RegisterTenantScope makes every query on db stay inside the tenant its
+// context is scoped to. Models opt in with a TenantID uuid.UUID field: reads,
+// updates and deletes of them only match the tenant's rows, and rows created
+// or saved are stamped with it, whatever TenantID the caller set. Contexts
+// without a tenant are left alone.
+//
+// Raw SQL and Table() queries are not covered; they filter on tenantParam.
+func RegisterTenantScope(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:scope", func(db *gorm.DB) {
+		stampTenant(db)
+		scopeTenant(db)
+	}); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:stamp", func(db *gorm.DB) {
+		stampTenant(db)
+		scopeUpsert(db)
+	})
+}
+
+// tenantField returns the statement model's TenantID field and the tenant
+// its context is scoped to, if both exist
+func tenantField(db *gorm.DB) (*schema.Field, uuid.UUID, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return nil, uuid.Nil, false
+	}
+	field := db.Statement.Schema.LookUpField("TenantID")
+	if field == nil || field.FieldType != tenantIDType {
+		return nil, uuid.Nil, false
+	}
+	id, ok := tenant.FromContext(db.Statement.Context)
+	return field, id, ok
+}
+
+func scopeTenant(db *gorm.DB) {
+	field, id, ok := tenantField(db)
+	if !ok {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id},
+	}})
+}
+
+// scopeUpsert keeps the update half of an upsert, such as the one Save falls
+// back to when its update matched nothing, off other tenants' rows. A
+// conflicting row of another tenant is left as it is.
+func scopeUpsert(db *gorm.DB) {
+	field, id, ok := tenantField(db)
+	if !ok {
+		return
+	}
+	c, ok := db.Statement.Clauses["ON CONFLICT"]
+	if !ok {
+		return
+	}
+	onConflict, ok := c.Expression.(clause.OnConflict)
+	if !ok || onConflict.DoNothing {
+		return
+	}
+	onConflict.Where.Exprs = append(onConflict.Where.Exprs,
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id})
+	c.Expression = onConflict
+	db.Statement.Clauses["ON CONFLICT"] = c
+}
+
+// stampTenant sets TenantID on the rows being written, overriding whatever
+// the caller put there, in a map of updates too
+func stampTenant(db *gorm.DB) {
+	field, id, ok := tenantField(db)
+	if !ok {
+		return
+	}
+	if updates, ok := db.Statement.Dest.(map[string]interface{}); ok {
+		for key := range updates {
+			if key == field.DBName || key == field.Name {
+				updates[key] = id
+			}
+		}
+	}
+	ctx, rv := db.Statement.Context, db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			row := reflect.Indirect(rv.Index(i))
+			if row.Kind() == reflect.Struct {
+				db.AddError(field.Set(ctx, row, id))
+			}
+		}
+	case reflect.Struct:
+		db.AddError(field.Set(ctx, rv, id))
+	}
+}
+
+// tenantParam is the tenant ctx is scoped to, or nil, for raw SQL that
+// filters with (CAST(@tenant AS uuid) IS NULL OR x.tenant_id = @tenant)
+func tenantParam(ctx context.Context) interface{} {
+	if id, ok := tenant.FromContext(ctx); ok {
+		return id
+	}
+	return nil
+}
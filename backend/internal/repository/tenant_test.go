@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/tenant"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openScopedDB opens an in-memory database with the tenant scope and the
+// tables the tag repository touches
+func openScopedDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: is a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := RegisterTenantScope(db); err != nil {
+		t.Fatalf("RegisterTenantScope: %v", err)
+	}
+	for _, ddl := range []string{
+		`CREATE TABLE tags (
+			id TEXT PRIMARY KEY,
+			tenant_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			slug TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'tag',
+			description TEXT,
+			created_at DATETIME,
+			updated_at DATETIME,
+			UNIQUE (tenant_id, slug)
+		)`,
+		`CREATE TABLE articles (id TEXT PRIMARY KEY, published BOOLEAN, deleted_at DATETIME)`,
+		`CREATE TABLE article_tags (article_id TEXT, tag_id TEXT)`,
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+// twoTenants returns contexts scoped to tenants A and B, each owning a "go"
+// tag created through the repository
+func twoTenants(t *testing.T, repo TagRepository) (ctxA, ctxB context.Context, tagA, tagB *model.Tag) {
+	t.Helper()
+	ctxA = tenant.NewContext(context.Background(), uuid.New())
+	ctxB = tenant.NewContext(context.Background(), uuid.New())
+	tagA = &model.Tag{Name: "Go", Slug: "go", Kind: model.TagKindTag}
+	tagB = &model.Tag{Name: "Go", Slug: "go", Kind: model.TagKindTag}
+	if err := repo.Create(ctxA, tagA); err != nil {
+		t.Fatalf("Create for A: %v", err)
+	}
+	if err := repo.Create(ctxB, tagB); err != nil {
+		t.Fatalf("Create for B: %v", err)
+	}
+	return ctxA, ctxB, tagA, tagB
+}
+
+func tenantOf(ctx context.Context) uuid.UUID {
+	id, _ := tenant.FromContext(ctx)
+	return id
+}
+
+// rawTag reads a tag past the scope
+func rawTag(t *testing.T, db *gorm.DB, id uuid.UUID) model.Tag {
+	t.Helper()
+	var tag model.Tag
+	if err := db.Raw("SELECT * FROM tags WHERE id = ?", id).Scan(&tag).Error; err != nil {
+		t.Fatal(err)
+	}
+	return tag
+}
+
+func TestTenantScopeReads(t *testing.T) {
+	db := openScopedDB(t)
+	repo := NewTagRepository(db)
+	ctxA, ctxB, tagA, tagB := twoTenants(t, repo)
+
+	if _, err := repo.GetByID(ctxA, tagB.ID.String()); !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("A read B's tag by ID: err = %v", err)
+	}
+	got, err := repo.GetBySlug(ctxA, "go")
+	if err != nil || got.ID != tagA.ID {
+		t.Errorf("A's GetBySlug = %+v, %v; want its own tag", got, err)
+	}
+	got, err = repo.GetBySlug(ctxB, "go")
+	if err != nil || got.ID != tagB.ID {
+		t.Errorf("B's GetBySlug = %+v, %v; want its own tag", got, err)
+	}
+
+	tags, err := repo.List(ctxA, "")
+	if err != nil || len(tags) != 1 || tags[0].ID != tagA.ID {
+		t.Errorf("A's List = %+v, %v", tags, err)
+	}
+
+	var count int64
+	if err := db.WithContext(ctxA).Model(&model.Tag{}).Count(&count).Error; err != nil || count != 1 {
+		t.Errorf("A counts %d tags, %v", count, err)
+	}
+	// An ID condition that would match both tenants still only finds A's
+	var found []model.Tag
+	db.WithContext(ctxA).Where("id IN ?", []uuid.UUID{tagA.ID, tagB.ID}).Find(&found)
+	if len(found) != 1 || found[0].ID != tagA.ID {
+		t.Errorf("A found %+v", found)
+	}
+	var ids []string
+	db.WithContext(ctxA).Model(&model.Tag{}).Pluck("id", &ids)
+	if len(ids) != 1 || ids[0] != tagA.ID.String() {
+		t.Errorf("A plucked %v", ids)
+	}
+
+	// Work done for no tenant, like the background workers, sees everything
+	if err := db.WithContext(context.Background()).Model(&model.Tag{}).Count(&count).Error; err != nil || count != 2 {
+		t.Errorf("unscoped count = %d, %v; want 2", count, err)
+	}
+}
+
+func TestTenantScopeUpdates(t *testing.T) {
+	db := openScopedDB(t)
+	repo := NewTagRepository(db)
+	ctxA, ctxB, tagA, tagB := twoTenants(t, repo)
+
+	hijack := &model.Tag{ID: tagB.ID, Name: "Hijacked", Slug: "hijacked", Kind: model.TagKindTag}
+	if err := repo.Update(ctxA, hijack); !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("A updating B's tag: err = %v, want ErrTagNotFound", err)
+	}
+	// Moving a row to another tenant is overridden too
+	moved := db.WithContext(ctxA).Model(&model.Tag{}).Where("id = ?", tagA.ID).
+		Updates(map[string]interface{}{"name": "Golang", "tenant_id": tenantOf(ctxB)})
+	if moved.Error != nil || moved.RowsAffected != 1 {
+		t.Errorf("A's own update: %v, %d rows", moved.Error, moved.RowsAffected)
+	}
+	all := db.WithContext(ctxA).Model(&model.Tag{}).Where("1 = 1").Update("description", "touched")
+	if all.Error != nil || all.RowsAffected != 1 {
+		t.Errorf("A's blanket update: %v, %d rows; want 1", all.Error, all.RowsAffected)
+	}
+
+	if got := rawTag(t, db, tagB.ID); got.Name != "Go" || got.Slug != "go" || got.Description != "" || got.TenantID != tenantOf(ctxB) {
+		t.Errorf("B's tag after A's updates = %+v", got)
+	}
+	if got := rawTag(t, db, tagA.ID); got.Name != "Golang" || got.Description != "touched" || got.TenantID != tenantOf(ctxA) {
+		t.Errorf("A's tag after its updates = %+v", got)
+	}
+}
+
+func TestTenantScopeSave(t *testing.T) {
+	db := openScopedDB(t)
+	repo := NewTagRepository(db)
+	ctxA, ctxB, _, tagB := twoTenants(t, repo)
+
+	// Save of a row A can't see must not take over B's row either
+	hijack := rawTag(t, db, tagB.ID)
+	hijack.Name = "Hijacked"
+	hijack.Slug = "hijacked"
+	db.WithContext(ctxA).Save(&hijack)
+
+	if got := rawTag(t, db, tagB.ID); got.Name != "Go" || got.TenantID != tenantOf(ctxB) {
+		t.Errorf("B's tag after A's Save = %+v", got)
+	}
+}
+
+func TestTenantScopeDeletes(t *testing.T) {
+	db := openScopedDB(t)
+	repo := NewTagRepository(db)
+	ctxA, ctxB, tagA, tagB := twoTenants(t, repo)
+
+	if err := repo.Delete(ctxA, tagB.ID.String()); !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("A deleting B's tag: err = %v, want ErrTagNotFound", err)
+	}
+	deleted := db.WithContext(ctxA).Where("1 = 1").Delete(&model.Tag{})
+	if deleted.Error != nil || deleted.RowsAffected != 1 {
+		t.Errorf("A's blanket delete: %v, %d rows; want 1", deleted.Error, deleted.RowsAffected)
+	}
+
+	if _, err := repo.GetByID(ctxB, tagB.ID.String()); err != nil {
+		t.Errorf("B's tag is gone: %v", err)
+	}
+	if _, err := repo.GetByID(ctxA, tagA.ID.String()); !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("A's own tag survived its delete: %v", err)
+	}
+}
+
+func TestTenantScopeStampsCreates(t *testing.T) {
+	db := openScopedDB(t)
+	repo := NewTagRepository(db)
+	ctxA := tenant.NewContext(context.Background(), uuid.New())
+	other := uuid.New()
+
+	tag := &model.Tag{TenantID: other, Name: "Rust", Slug: "rust", Kind: model.TagKindTag}
+	if err := repo.Create(ctxA, tag); err != nil {
+		t.Fatal(err)
+	}
+	if tag.TenantID != tenantOf(ctxA) {
+		t.Errorf("TenantID = %v, want A's %v", tag.TenantID, tenantOf(ctxA))
+	}
+	if got := rawTag(t, db, tag.ID); got.TenantID != tenantOf(ctxA) {
+		t.Errorf("stored TenantID = %v, want A's", got.TenantID)
+	}
+
+	// Batch creates are stamped row by row
+	batch := []*model.Tag{
+		{TenantID: other, Name: "Zig", Slug: "zig", Kind: model.TagKindTag},
+		{Name: "Nim", Slug: "nim", Kind: model.TagKindTag},
+	}
+	if err := db.WithContext(ctxA).Create(batch).Error; err != nil {
+		t.Fatal(err)
+	}
+	values := []model.Tag{{TenantID: other, Name: "Odin", Slug: "odin", Kind: model.TagKindTag}}
+	if err := db.WithContext(ctxA).Create(&values).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var count int64
+	db.Raw("SELECT COUNT(*) FROM tags WHERE tenant_id = ?", other).Scan(&count)
+	if count != 0 {
+		t.Errorf("%d tags stored under the caller's TenantID", count)
+	}
+	db.Raw("SELECT COUNT(*) FROM tags WHERE tenant_id = ?", tenantOf(ctxA)).Scan(&count)
+	if count != 4 {
+		t.Errorf("A owns %d tags, want 4", count)
+	}
+
+	// Without a tenant the caller's TenantID stands
+	free := &model.Tag{TenantID: other, Name: "C", Slug: "c", Kind: model.TagKindTag}
+	if err := repo.Create(context.Background(), free); err != nil {
+		t.Fatal(err)
+	}
+	if got := rawTag(t, db, free.ID); got.TenantID != other {
+		t.Errorf("unscoped create stored TenantID %v, want %v", got.TenantID, other)
+	}
+}
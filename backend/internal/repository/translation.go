@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// TranslationRepository stores the translations of one kind of content,
+// at most one per owner and locale
+type TranslationRepository[T any] interface {
+	// WithTx returns the repository bound to a transaction
+	WithTx(tx *gorm.DB) TranslationRepository[T]
+	// List returns the owner's translations ordered by locale
+	List(ctx context.Context, ownerID uuid.UUID) ([]T, error)
+	// ListByOwners returns every translation of the given owners
+	ListByOwners(ctx context.Context, ownerIDs []uuid.UUID) ([]T, error)
+	Get(ctx context.Context, ownerID uuid.UUID, locale string) (*T, error)
+	// Save inserts the translation, or overwrites it when its id exists
+	Save(ctx context.Context, translation *T) error
+	Delete(ctx context.Context, ownerID uuid.UUID, locale string) error
+}
+
+// ArticleTranslationRepository adds the per-locale slug lookup
+type ArticleTranslationRepository interface {
+	TranslationRepository[model.ArticleTranslation]
+	// GetBySlug finds the translation using slug in the locale, whatever
+	// the state of its article
+	GetBySlug(ctx context.Context, locale, slug string) (*model.ArticleTranslation, error)
+}
+
+type translationRepository[T any] struct {
+	db *gorm.DB
+	// Column referencing the translated row
+	owner string
+}
+
+type articleTranslationRepository struct {
+	*translationRepository[model.ArticleTranslation]
+}
+
+func NewArticleTranslationRepository(db *gorm.DB) ArticleTranslationRepository {
+	return &articleTranslationRepository{&translationRepository[model.ArticleTranslation]{db: db, owner: "article_id"}}
+}
+
+func NewProjectTranslationRepository(db *gorm.DB) TranslationRepository[model.ProjectTranslation] {
+	return &translationRepository[model.ProjectTranslation]{db: db, owner: "project_id"}
+}
+
+func NewPortfolioTranslationRepository(db *gorm.DB) TranslationRepository[model.PortfolioTranslation] {
+	return &translationRepository[model.PortfolioTranslation]{db: db, owner: "portfolio_id"}
+}
+
+func (r *translationRepository[T]) WithTx(tx *gorm.DB) TranslationRepository[T] {
+	return &translationRepository[T]{db: tx, owner: r.owner}
+}
+
+func (r *translationRepository[T]) List(ctx context.Context, ownerID uuid.UUID) ([]T, error) {
+	translations := []T{}
+	err := r.db.WithContext(ctx).
+		Where(r.owner+" = ?", ownerID).
+		Order("locale ASC").
+		Find(&translations).Error
+	return translations, err
+}
+
+func (r *translationRepository[T]) ListByOwners(ctx context.Context, ownerIDs []uuid.UUID) ([]T, error) {
+	translations := []T{}
+	if len(ownerIDs) == 0 {
+		return translations, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where(r.owner+" IN ?", ownerIDs).
+		Order("locale ASC").
+		Find(&translations).Error
+	return translations, err
+}
+
+func (r *translationRepository[T]) Get(ctx context.Context, ownerID uuid.UUID, locale string) (*T, error) {
+	var translation T
+	err := r.db.WithContext(ctx).
+		Where(r.owner+" = ? AND locale = ?", ownerID, locale).
+		First(&translation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTranslationNotFound
+		}
+		return nil, err
+	}
+	return &translation, nil
+}
+
+func (r *translationRepository[T]) Save(ctx context.Context, translation *T) error {
+	return r.db.WithContext(ctx).Save(translation).Error
+}
+
+func (r *translationRepository[T]) Delete(ctx context.Context, ownerID uuid.UUID, locale string) error {
+	result := r.db.WithContext(ctx).
+		Where(r.owner+" = ? AND locale = ?", ownerID, locale).
+		Delete(new(T))
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTranslationNotFound
+	}
+
+	return nil
+}
+
+func (r *articleTranslationRepository) GetBySlug(ctx context.Context, locale, slug string) (*model.ArticleTranslation, error) {
+	var translation model.ArticleTranslation
+	err := r.db.WithContext(ctx).
+		Where("locale = ? AND slug = ?", locale, slug).
+		First(&translation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTranslationNotFound
+		}
+		return nil, err
+	}
+	return &translation, nil
+}
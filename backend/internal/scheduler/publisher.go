@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/service"
+	"go.uber.org/zap"
+)
+
+const publisherLockKey = "article-scheduler"
+
+// ArticlePublisher periodically applies scheduled publish/unpublish
+// transitions. Every replica runs one, but a Redis lock makes sure only a
+// single replica does the work on each tick.
+type ArticlePublisher struct {
+	service  service.ArticleService
+	cache    *cache.RedisCache
+	logger   *zap.Logger
+	interval time.Duration
+	instance string
+}
+
+func NewArticlePublisher(service service.ArticleService, cache *cache.RedisCache, logger *zap.Logger, interval time.Duration) *ArticlePublisher {
+	return &ArticlePublisher{
+		service:  service,
+		cache:    cache,
+		logger:   logger,
+		interval: interval,
+		instance: uuid.New().String(),
+	}
+}
+
+// Run blocks until ctx is cancelled. The first tick runs immediately so
+// transitions missed during downtime are caught up on startup.
+func (p *ArticlePublisher) Run(ctx context.Context) {
+	p.logger.Info("Article scheduler started", zap.Duration("interval", p.interval))
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("Article scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *ArticlePublisher) tick(ctx context.Context) {
+	// The lock outlives a tick so a crashed holder is replaced on the next one
+	acquired, err := p.cache.AcquireLock(ctx, publisherLockKey, p.instance, 2*p.interval)
+	if err != nil {
+		p.logger.Error("Failed to acquire scheduler lock", zap.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer p.cache.ReleaseLock(context.Background(), publisherLockKey, p.instance)
+
+	applied, err := p.service.RunScheduledTransitions(ctx, time.Now().UTC())
+	if err != nil {
+		p.logger.Error("Failed to run scheduled article transitions", zap.Error(err))
+	}
+	if applied > 0 {
+		p.logger.Info("Applied scheduled article transitions", zap.Int("count", applied))
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+)
+
+var ErrInvalidAnalyticsRange = errors.New("to must not be before from, and the range can be at most 366 days")
+
+const (
+	// Salts and dedup markers outlive their day so late events still match
+	analyticsKeyTTL  = 48 * time.Hour
+	maxPathLength    = 500
+	maxAnalyticsDays = 366
+)
+
+var (
+	botPattern    = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|archiver|facebookexternalhit|embedly|preview|headless|phantomjs|lighthouse|pingdom|uptime|monitor|curl|wget|python|go-http-client|java/|okhttp|axios|node-fetch|libwww|httpclient`)
+	tabletPattern = regexp.MustCompile(`(?i)ipad|tablet|kindle|silk|playbook`)
+	mobilePattern = regexp.MustCompile(`(?i)mobi|iphone|ipod|android|blackberry|opera mini|windows phone`)
+)
+
+// PageViewHit is a page view as reported by the browser
+type PageViewHit struct {
+	Path      string
+	Referrer  string
+	IP        string
+	UserAgent string
+}
+
+type AnalyticsService interface {
+	// RecordPageView publishes a page view to the analytics topic. Bots are
+	// dropped; the result reports whether the view was recorded.
+	RecordPageView(ctx context.Context, hit PageViewHit) (bool, error)
+	// Aggregate turns a batch of page view events into rollup increments,
+	// skipping events already counted and counting each visitor once per day
+	Aggregate(ctx context.Context, views []model.PageView) (*model.AnalyticsRollup, error)
+	SaveRollup(ctx context.Context, rollup *model.AnalyticsRollup) error
+	GetTopPages(ctx context.Context, from, to time.Time, limit int) ([]model.AnalyticsPageStat, error)
+	GetTopReferrers(ctx context.Context, from, to time.Time, limit int) ([]model.AnalyticsReferrerStat, error)
+	// GetTraffic returns one entry per day of the range, zero-filled
+	GetTraffic(ctx context.Context, from, to time.Time, path string) ([]model.AnalyticsDayStat, error)
+	ExportDailyPages(ctx context.Context, from, to time.Time) ([]model.AnalyticsDailyPage, error)
+}
+
+type analyticsService struct {
+	repo    repository.AnalyticsRepository
+	kafka   *kafka.Producer
+	cache   *cache.RedisCache
+	tenants TenantService
+}
+
+func NewAnalyticsService(repo repository.AnalyticsRepository, kafka *kafka.Producer, cache *cache.RedisCache, tenants TenantService) AnalyticsService {
+	return &analyticsService{
+		repo:    repo,
+		kafka:   kafka,
+		cache:   cache,
+		tenants: tenants,
+	}
+}
+
+func (s *analyticsService) RecordPageView(ctx context.Context, hit PageViewHit) (bool, error) {
+	device, ok := deviceClass(hit.UserAgent)
+	if !ok {
+		return false, nil
+	}
+	path := normalizePath(hit.Path)
+	if path == "" {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	salt, err := s.dailySalt(ctx, now)
+	if err != nil {
+		return false, err
+	}
+	sum := sha256.Sum256([]byte(salt + hit.IP + "|" + hit.UserAgent))
+
+	view := model.PageView{
+		ID:          uuid.New().String(),
+		Path:        path,
+		Device:      device,
+		VisitorHash: hex.EncodeToString(sum[:16]),
+		ViewedAt:    now,
+	}
+	urls, err := s.tenants.URLs(ctx)
+	if err != nil {
+		return false, err
+	}
+	if domain := referrer(hit.Referrer, urls.Site); domain != "" {
+		view.ReferrerDomain = domain
+	}
+
+	if err := s.kafka.PublishPageView(ctx, view); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// dailySalt returns the random salt for the day of t, creating it on first
+// use. It expires with the day, after which a visitor's hashes can't be
+// linked to their earlier ones or reversed. Each tenant has its own salt.
+func (s *analyticsService) dailySalt(ctx context.Context, t time.Time) (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	salt, err := s.cache.GetOrSet(ctx, fmt.Sprintf("analytics:salt:%s", t.Format("2006-01-02")), []byte(hex.EncodeToString(random)), analyticsKeyTTL)
+	if err != nil {
+		return "", err
+	}
+	return string(salt), nil
+}
+
+// referrer reduces a referrer URL to its domain. Links from the site itself
+// are navigation, not referrals, and count as direct.
+func referrer(raw, siteURL string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	domain := referrerDomain(u.Hostname())
+	if site, err := url.Parse(siteURL); err == nil && domain == referrerDomain(site.Hostname()) {
+		return ""
+	}
+	return domain
+}
+
+func referrerDomain(host string) string {
+	return truncate(strings.TrimPrefix(strings.ToLower(host), "www."), 255)
+}
+
+// normalizePath keeps only the path of a page URL: query strings and
+// fragments can carry personal data and would split one page into many
+func normalizePath(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	path := u.EscapedPath()
+	if !strings.HasPrefix(path, "/") {
+		return ""
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return truncate(path, maxPathLength)
+}
+
+// deviceClass buckets a user agent, reporting false for bots and for
+// requests with no user agent at all
+func deviceClass(userAgent string) (string, bool) {
+	if strings.TrimSpace(userAgent) == "" || botPattern.MatchString(userAgent) {
+		return "", false
+	}
+	switch {
+	case tabletPattern.MatchString(userAgent):
+		return model.DeviceTablet, true
+	case strings.Contains(strings.ToLower(userAgent), "android") && !strings.Contains(strings.ToLower(userAgent), "mobile"):
+		// Android tablets leave "Mobile" out of their user agent
+		return model.DeviceTablet, true
+	case mobilePattern.MatchString(userAgent):
+		return model.DeviceMobile, true
+	}
+	return model.DeviceDesktop, true
+}
+
+func (s *analyticsService) Aggregate(ctx context.Context, views []model.PageView) (*model.AnalyticsRollup, error) {
+	pages := make(map[model.AnalyticsDailyPage]*model.AnalyticsDailyPage)
+	visitors := make(map[model.AnalyticsDailyVisitors]*model.AnalyticsDailyVisitors)
+
+	for _, view := range views {
+		// Kafka delivers at least once; each event counts once
+		isNew, err := s.cache.MarkSeen(ctx, fmt.Sprintf("analytics:event:%s", view.ID), analyticsKeyTTL)
+		if err != nil {
+			return nil, err
+		}
+		if !isNew {
+			continue
+		}
+
+		day := view.ViewedAt.UTC().Truncate(24 * time.Hour)
+		dayKey := day.Format("2006-01-02")
+		key := model.AnalyticsDailyPage{TenantID: view.TenantID, Day: day, Path: view.Path, ReferrerDomain: view.ReferrerDomain, Device: view.Device}
+		page, ok := pages[key]
+		if !ok {
+			page = &model.AnalyticsDailyPage{TenantID: view.TenantID, Day: day, Path: view.Path, ReferrerDomain: view.ReferrerDomain, Device: view.Device}
+			pages[key] = page
+		}
+		page.Views++
+
+		// Visitors are counted per tenant, each tenant being its own site
+		firstOnPage, err := s.cache.MarkSeen(ctx, fmt.Sprintf("analytics:seen:%s:%s:%s:%s", view.TenantID, dayKey, view.VisitorHash, view.Path), analyticsKeyTTL)
+		if err != nil {
+			return nil, err
+		}
+		if firstOnPage {
+			page.Visitors++
+		}
+
+		firstToday, err := s.cache.MarkSeen(ctx, fmt.Sprintf("analytics:seen:%s:%s:%s", view.TenantID, dayKey, view.VisitorHash), analyticsKeyTTL)
+		if err != nil {
+			return nil, err
+		}
+		if firstToday {
+			dayVisitors := model.AnalyticsDailyVisitors{TenantID: view.TenantID, Day: day}
+			v, ok := visitors[dayVisitors]
+			if !ok {
+				v = &dayVisitors
+				visitors[dayVisitors] = v
+			}
+			v.Visitors++
+		}
+	}
+
+	rollup := &model.AnalyticsRollup{}
+	for _, page := range pages {
+		rollup.Pages = append(rollup.Pages, *page)
+	}
+	for _, v := range visitors {
+		rollup.Visitors = append(rollup.Visitors, *v)
+	}
+
+	// A fixed row order keeps concurrent upserts from deadlocking
+	sort.Slice(rollup.Pages, func(i, j int) bool {
+		a, b := rollup.Pages[i], rollup.Pages[j]
+		if a.TenantID != b.TenantID {
+			return a.TenantID.String() < b.TenantID.String()
+		}
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.ReferrerDomain != b.ReferrerDomain {
+			return a.ReferrerDomain < b.ReferrerDomain
+		}
+		return a.Device < b.Device
+	})
+	sort.Slice(rollup.Visitors, func(i, j int) bool {
+		a, b := rollup.Visitors[i], rollup.Visitors[j]
+		if a.TenantID != b.TenantID {
+			return a.TenantID.String() < b.TenantID.String()
+		}
+		return a.Day.Before(b.Day)
+	})
+	return rollup, nil
+}
+
+func (s *analyticsService) SaveRollup(ctx context.Context, rollup *model.AnalyticsRollup) error {
+	if len(rollup.Pages) == 0 && len(rollup.Visitors) == 0 {
+		return nil
+	}
+	return s.repo.SaveRollup(ctx, rollup)
+}
+
+func (s *analyticsService) GetTopPages(ctx context.Context, from, to time.Time, limit int) ([]model.AnalyticsPageStat, error) {
+	if err := validateAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+	return s.repo.TopPages(ctx, from, to, clampAnalyticsLimit(limit))
+}
+
+func (s *analyticsService) GetTopReferrers(ctx context.Context, from, to time.Time, limit int) ([]model.AnalyticsReferrerStat, error) {
+	if err := validateAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+	return s.repo.TopReferrers(ctx, from, to, clampAnalyticsLimit(limit))
+}
+
+func (s *analyticsService) GetTraffic(ctx context.Context, from, to time.Time, path string) ([]model.AnalyticsDayStat, error) {
+	if err := validateAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+	if path != "" {
+		path = normalizePath(path)
+	}
+
+	stats, err := s.repo.Traffic(ctx, from, to, path)
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[string]model.AnalyticsDayStat, len(stats))
+	for _, stat := range stats {
+		byDay[stat.Day] = stat
+	}
+
+	series := []model.AnalyticsDayStat{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		stat, ok := byDay[key]
+		if !ok {
+			stat = model.AnalyticsDayStat{Day: key}
+		}
+		series = append(series, stat)
+	}
+	return series, nil
+}
+
+func (s *analyticsService) ExportDailyPages(ctx context.Context, from, to time.Time) ([]model.AnalyticsDailyPage, error) {
+	if err := validateAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDailyPages(ctx, from, to)
+}
+
+func validateAnalyticsRange(from, to time.Time) error {
+	if to.Before(from) || to.Sub(from) > maxAnalyticsDays*24*time.Hour {
+		return ErrInvalidAnalyticsRange
+	}
+	return nil
+}
+
+func clampAnalyticsLimit(limit int) int {
+	if limit < 1 || limit > 100 {
+		return 10
+	}
+	return limit
+}
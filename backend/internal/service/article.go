@@ -3,53 +3,131 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/portfolio/backend/internal/cache"
 	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/markdown"
 	"github.com/portfolio/backend/internal/model"
 	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/tenant"
 	"time"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// Reads take the locale to serve; articles without a translation into it,
+// and every read with an empty locale, are in the default locale
 type ArticleService interface {
-	GetArticles(ctx context.Context, page, limit int) ([]model.Article, int64, error)
-	GetArticleByID(ctx context.Context, id string) (*model.Article, error)
-	GetArticleBySlug(ctx context.Context, slug string) (*model.Article, error)
+	GetArticles(ctx context.Context, page, limit int, tag, locale string) ([]model.Article, int64, error)
+	// GetArticlesByCursor pages through published articles by cursor; the
+	// total is only counted when asked for
+	GetArticlesByCursor(ctx context.Context, cursor string, limit int, tag, locale string, withTotal bool) ([]model.Article, *model.CursorPage, error)
+	GetArticleByID(ctx context.Context, id, locale string) (*model.Article, error)
+	// GetArticleBySlug finds a published article by its slug in the locale,
+	// falling back to its default slug
+	GetArticleBySlug(ctx context.Context, slug, locale string) (*model.Article, error)
+	GetPinnedArticles(ctx context.Context, locale string) ([]model.Article, error)
 	CreateArticle(ctx context.Context, article *model.Article) error
-	UpdateArticle(ctx context.Context, id string, article *model.Article) error
+	UpdateArticle(ctx context.Context, id string, article *model.Article, editorID uuid.UUID) error
 	DeleteArticle(ctx context.Context, id string) error
+	// PinArticles pins the listed articles in that order and unpins the rest
+	PinArticles(ctx context.Context, ids []uuid.UUID) error
+	// LinkProjects relates the article to exactly the given projects
+	LinkProjects(ctx context.Context, id string, projectIDs []uuid.UUID) (*model.Article, error)
+	AutosaveArticle(ctx context.Context, id string, draft *model.Article, editorID uuid.UUID) (*model.ArticleRevision, error)
+	ListRevisions(ctx context.Context, id string, includeAutosaves bool) ([]model.ArticleRevision, error)
+	GetRevision(ctx context.Context, id, revisionID string) (*model.ArticleRevision, error)
+	DiffRevisions(ctx context.Context, id, revisionID, againstID string) (*model.ArticleRevisionDiff, error)
+	RestoreRevision(ctx context.Context, id, revisionID string, editorID uuid.UUID) (*model.Article, error)
+	RunScheduledTransitions(ctx context.Context, now time.Time) (int, error)
+	GetCalendar(ctx context.Context, from, to time.Time) ([]model.CalendarEntry, error)
 }
 
+var ErrInvalidSchedule = errors.New("unpublish_at must be after publish_at and in the future")
+
+// Suggestions shown with each article
+const relatedArticleCount = 4
+
 type articleService struct {
-	repo   repository.ArticleRepository
-	kafka  *kafka.Producer
-	cache  *cache.RedisCache
+	repo            repository.ArticleRepository
+	tagRepo         repository.TagRepository
+	revisionRepo    repository.ArticleRevisionRepository
+	mediaRepo       repository.MediaRepository
+	translationRepo repository.ArticleTranslationRepository
+	renderer        *markdown.Renderer
+	cache           *cache.RedisCache
+	defaultLocale   string
 }
 
-func NewArticleService(repo repository.ArticleRepository, kafka *kafka.Producer, cache *cache.RedisCache) ArticleService {
+func NewArticleService(repo repository.ArticleRepository, tagRepo repository.TagRepository, revisionRepo repository.ArticleRevisionRepository, mediaRepo repository.MediaRepository, translationRepo repository.ArticleTranslationRepository, renderer *markdown.Renderer, cache *cache.RedisCache, defaultLocale string) ArticleService {
 	return &articleService{
-		repo:  repo,
-		kafka: kafka,
-		cache: cache,
+		repo:            repo,
+		tagRepo:         tagRepo,
+		revisionRepo:    revisionRepo,
+		mediaRepo:       mediaRepo,
+		translationRepo: translationRepo,
+		renderer:        renderer,
+		cache:           cache,
+		defaultLocale:   defaultLocale,
 	}
 }
 
-func (s *articleService) GetArticles(ctx context.Context, page, limit int) ([]model.Article, int64, error) {
+func (s *articleService) GetArticles(ctx context.Context, page, limit int, tag, locale string) ([]model.Article, int64, error) {
 	if page < 1 {
 		page = 1
 	}
 	if limit < 1 || limit > 100 {
 		limit = 10
 	}
-	return s.repo.List(ctx, page, limit, true)
+	articles, total, err := s.repo.List(ctx, page, limit, true, tag)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := translateArticles(ctx, s.translationRepo, articles, locale, s.defaultLocale); err != nil {
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
+
+func (s *articleService) GetArticlesByCursor(ctx context.Context, cursor string, limit int, tag, locale string, withTotal bool) ([]model.Article, *model.CursorPage, error) {
+	position, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	limit = cursorLimit(limit)
+
+	articles, more, err := s.repo.ListByCursor(ctx, position, limit, true, tag)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := cursorPage(articles, func(a model.Article) (time.Time, uuid.UUID) {
+		return a.CreatedAt, a.ID
+	}, position, more, limit)
+	if withTotal {
+		total, err := s.repo.Count(ctx, true, tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		page.Total = &total
+	}
+	if err := translateArticles(ctx, s.translationRepo, articles, locale, s.defaultLocale); err != nil {
+		return nil, nil, err
+	}
+	return articles, page, nil
 }
 
-func (s *articleService) GetArticleByID(ctx context.Context, id string) (*model.Article, error) {
+func (s *articleService) GetArticleByID(ctx context.Context, id, locale string) (*model.Article, error) {
 	// Try cache first
 	cached, err := s.cache.GetArticle(ctx, id)
 	if err == nil {
 		var article model.Article
 		json.Unmarshal(cached, &article)
+		s.attachRelated(ctx, &article)
+		if err := s.translate(ctx, &article, locale); err != nil {
+			return nil, err
+		}
 		return &article, nil
 	}
 
@@ -58,54 +136,247 @@ func (s *articleService) GetArticleByID(ctx context.Context, id string) (*model.
 	if err != nil {
 		return nil, err
 	}
+	s.renderIfMissing(article)
 
 	// Store in cache
 	s.cache.SetArticle(ctx, id, article, 10*time.Minute)
 
+	s.attachRelated(ctx, article)
+	if err := s.translate(ctx, article, locale); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+func (s *articleService) GetArticleBySlug(ctx context.Context, slug, locale string) (*model.Article, error) {
+	article, err := s.getBySlug(ctx, slug, locale)
+	if err != nil {
+		return nil, err
+	}
+	s.renderIfMissing(article)
+	s.attachRelated(ctx, article)
+	if err := s.translate(ctx, article, locale); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// getBySlug looks the slug up among the locale's translations, then among
+// the default slugs. A default slug still serves the translation, whose
+// slug the client can redirect to.
+func (s *articleService) getBySlug(ctx context.Context, slug, locale string) (*model.Article, error) {
+	if locale == "" || locale == s.defaultLocale {
+		return s.repo.GetBySlug(ctx, slug)
+	}
+
+	translation, err := s.translationRepo.GetBySlug(ctx, locale, slug)
+	if errors.Is(err, repository.ErrTranslationNotFound) {
+		return s.repo.GetBySlug(ctx, slug)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	article, err := s.repo.GetByID(ctx, translation.ArticleID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !article.Published {
+		return nil, repository.ErrArticleNotFound
+	}
 	return article, nil
 }
 
-func (s *articleService) GetArticleBySlug(ctx context.Context, slug string) (*model.Article, error) {
-	return s.repo.GetBySlug(ctx, slug)
+// translate serves a single article in the locale and lists every locale it
+// is in. Its translations are cached under articles:*, so any article write
+// drops them.
+func (s *articleService) translate(ctx context.Context, article *model.Article, locale string) error {
+	key := fmt.Sprintf("articles:translations:%s", article.ID)
+	translations, err := cachedTranslations[model.ArticleTranslation](ctx, s.cache, key, s.translationRepo, article.ID)
+	if err != nil {
+		return err
+	}
+
+	article.Locale = s.defaultLocale
+	article.Alternates = []model.Alternate{{Locale: s.defaultLocale, Slug: article.Slug}}
+	for i, t := range translations {
+		article.Alternates = append(article.Alternates, model.Alternate{Locale: t.Locale, Slug: t.Slug})
+		if t.Locale == locale {
+			translateArticle(article, &translations[i])
+		}
+	}
+	return translateArticles(ctx, s.translationRepo, article.Related, locale, s.defaultLocale)
+}
+
+// attachRelated fills in suggested articles. They are cached apart from the
+// article under articles:*, so any article write refreshes them; failing to
+// load them leaves the article without suggestions rather than failing it.
+func (s *articleService) attachRelated(ctx context.Context, article *model.Article) {
+	key := fmt.Sprintf("articles:related:%s", article.ID)
+	if cached, err := s.cache.Get(ctx, key); err == nil {
+		var related []model.Article
+		if err := json.Unmarshal(cached, &related); err == nil {
+			article.Related = related
+			return
+		}
+	}
+
+	related, err := s.repo.ListRelated(ctx, article.ID, relatedArticleCount)
+	if err != nil {
+		return
+	}
+	article.Related = related
+
+	if data, err := json.Marshal(related); err == nil {
+		s.cache.Set(ctx, key, data, time.Hour)
+	}
+}
+
+func (s *articleService) LinkProjects(ctx context.Context, id string, projectIDs []uuid.UUID) (*model.Article, error) {
+	article, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUniqueIDs(projectIDs); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		repo := repository.NewArticleRepository(tx)
+		if err := repo.ReplaceProjects(ctx, article.ID, projectIDs); err != nil {
+			return err
+		}
+
+		article, err = repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicArticles, kafka.EventArticleUpdated, id, article)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache (project details list their articles)
+	s.cache.InvalidateArticles(ctx)
+	s.cache.Delete(ctx, fmt.Sprintf("article:detail:%s", id))
+	s.cache.DeletePattern(ctx, "project:detail:*")
+
+	s.renderIfMissing(article)
+	return article, nil
 }
 
 func (s *articleService) CreateArticle(ctx context.Context, article *model.Article) error {
+	// Articles are pinned only through PinArticles and linked to projects
+	// only through LinkProjects
+	article.Position = nil
+	article.Projects = nil
+	article.Related = nil
+	if err := s.resolveTags(ctx, article); err != nil {
+		return err
+	}
+	if err := s.resolveCoverImage(ctx, article); err != nil {
+		return err
+	}
+
+	if err := applySchedule(article, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := s.render(article); err != nil {
+		return err
+	}
+
 	if article.Published {
 		now := time.Now()
 		article.PublishedAt = &now
 	}
 
-	if err := s.repo.Create(ctx, article); err != nil {
+	// The article and its first revision are written together
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewArticleRepository(tx).Create(ctx, article); err != nil {
+			return err
+		}
+		if err := repository.NewArticleRevisionRepository(tx).Create(ctx, model.NewArticleRevision(article, article.AuthorID)); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicArticles, kafka.EventArticleCreated, article.ID.String(), article)
+	})
+	if err != nil {
 		return err
 	}
 
-	// Publish Kafka event
-	s.kafka.PublishArticleCreated(ctx, article)
-
 	// Invalidate cache
 	s.cache.InvalidateArticles(ctx)
 
 	return nil
 }
 
-func (s *articleService) UpdateArticle(ctx context.Context, id string, article *model.Article) error {
+func (s *articleService) UpdateArticle(ctx context.Context, id string, article *model.Article, editorID uuid.UUID) error {
+	return s.update(ctx, id, article, editorID, nil)
+}
+
+// update overwrites the article and records the result as a new revision in
+// the same transaction; restoredFrom marks revisions produced by a restore
+func (s *articleService) update(ctx context.Context, id string, article *model.Article, editorID uuid.UUID, restoredFrom *uuid.UUID) error {
 	existing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return err // Repository already returns ErrArticleNotFound
 	}
 
 	article.ID = existing.ID
-	if article.Published && existing.PublishedAt == nil {
-		now := time.Now()
-		article.PublishedAt = &now
+	article.Position = existing.Position
+	if err := applySchedule(article, time.Now().UTC()); err != nil {
+		return err
+	}
+	if err := s.render(article); err != nil {
+		return err
+	}
+	if article.Published {
+		if existing.PublishedAt == nil {
+			now := time.Now()
+			article.PublishedAt = &now
+		} else {
+			// Keep the original publication date on re-saves
+			article.PublishedAt = existing.PublishedAt
+		}
 	}
 
-	if err := s.repo.Update(ctx, article); err != nil {
+	// nil Tags leaves the current tag set untouched
+	replaceTags := article.Tags != nil
+	if err := s.resolveTags(ctx, article); err != nil {
+		return err
+	}
+	if err := s.resolveCoverImage(ctx, article); err != nil {
 		return err
 	}
 
-	// Publish Kafka event
-	s.kafka.PublishArticleUpdated(ctx, article)
+	err = s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		repo := repository.NewArticleRepository(tx)
+		if err := repo.Update(ctx, article); err != nil {
+			return err
+		}
+
+		if replaceTags {
+			if err := repo.ReplaceTags(ctx, article, article.Tags); err != nil {
+				return err
+			}
+		}
+
+		revision := model.NewArticleRevision(article, editorID)
+		revision.RestoredFromID = restoredFrom
+		if err := repository.NewArticleRevisionRepository(tx).Create(ctx, revision); err != nil {
+			return err
+		}
+
+		if !replaceTags {
+			article.Tags = existing.Tags
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicArticles, kafka.EventArticleUpdated, id, article)
+	})
+	if err != nil {
+		return err
+	}
 
 	// Invalidate cache
 	s.cache.InvalidateArticles(ctx)
@@ -115,17 +386,338 @@ func (s *articleService) UpdateArticle(ctx context.Context, id string, article *
 }
 
 func (s *articleService) DeleteArticle(ctx context.Context, id string) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewArticleRepository(tx).Delete(ctx, id); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicArticles, kafka.EventArticleDeleted, id, map[string]string{"id": id})
+	})
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	s.cache.InvalidateArticles(ctx)
+	s.cache.Delete(ctx, fmt.Sprintf("article:detail:%s", id))
+
+	return nil
+}
+
+func (s *articleService) GetPinnedArticles(ctx context.Context, locale string) ([]model.Article, error) {
+	articles, err := s.pinned(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := translateArticles(ctx, s.translationRepo, articles, locale, s.defaultLocale); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// pinned returns the pinned articles in the default locale
+func (s *articleService) pinned(ctx context.Context) ([]model.Article, error) {
+	// Lives under articles:* so any article write drops it
+	key := "articles:pinned"
+	if cached, err := s.cache.Get(ctx, key); err == nil {
+		var articles []model.Article
+		if err := json.Unmarshal(cached, &articles); err == nil {
+			return articles, nil
+		}
+	}
+
+	articles, err := s.repo.ListPinned(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(articles); err == nil {
+		s.cache.Set(ctx, key, data, 10*time.Minute)
+	}
+
+	return articles, nil
+}
+
+func (s *articleService) PinArticles(ctx context.Context, ids []uuid.UUID) error {
+	if err := checkUniqueIDs(ids); err != nil {
 		return err
 	}
 
-	// Publish Kafka event
-	s.kafka.PublishArticleDeleted(ctx, id)
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewArticleRepository(tx).ReorderPinned(ctx, ids); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicArticles, kafka.EventArticlesReordered, "", map[string][]uuid.UUID{"ids": ids})
+	})
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache (lists and details carry positions)
+	s.cache.InvalidateArticles(ctx)
+	s.cache.DeletePattern(ctx, "article:detail:*")
+
+	return nil
+}
+
+// AutosaveArticle stores a draft as an autosave revision without touching the
+// live article, so it is neither published nor announced
+func (s *articleService) AutosaveArticle(ctx context.Context, id string, draft *model.Article, editorID uuid.UUID) (*model.ArticleRevision, error) {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	draft.ID = existing.ID
+	draft.Published = existing.Published
+	revision := model.NewArticleRevision(draft, editorID)
+	revision.Autosave = true
+
+	err = s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		return repository.NewArticleRevisionRepository(tx).Create(ctx, revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (s *articleService) ListRevisions(ctx context.Context, id string, includeAutosaves bool) ([]model.ArticleRevision, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.List(ctx, id, includeAutosaves)
+}
+
+func (s *articleService) GetRevision(ctx context.Context, id, revisionID string) (*model.ArticleRevision, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.GetByID(ctx, id, revisionID)
+}
+
+// DiffRevisions diffs revisionID against againstID, or against the previous
+// saved revision when againstID is empty
+func (s *articleService) DiffRevisions(ctx context.Context, id, revisionID, againstID string) (*model.ArticleRevisionDiff, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	to, err := s.revisionRepo.GetByID(ctx, id, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var from *model.ArticleRevision
+	if againstID != "" {
+		from, err = s.revisionRepo.GetByID(ctx, id, againstID)
+	} else {
+		from, err = s.revisionRepo.GetPrevious(ctx, to)
+	}
+	if err != nil {
+		if againstID == "" && errors.Is(err, repository.ErrRevisionNotFound) {
+			// First revision: diff against an empty document
+			from = &model.ArticleRevision{ArticleID: to.ArticleID}
+		} else {
+			return nil, err
+		}
+	}
+
+	return model.DiffArticleRevisions(from, to), nil
+}
+
+// RestoreRevision copies an old revision's content onto the article. The
+// restore is saved as a new revision; publication state is left as it is.
+func (s *articleService) RestoreRevision(ctx context.Context, id, revisionID string, editorID uuid.UUID) (*model.Article, error) {
+	revision, err := s.revisionRepo.GetByID(ctx, id, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	article := &model.Article{
+		Title:        revision.Title,
+		Slug:         revision.Slug,
+		Excerpt:      revision.Excerpt,
+		Content:      revision.Content,
+		AuthorID:     existing.AuthorID,
+		Published:    existing.Published,
+		NoIndex:      existing.NoIndex,
+		CoverImageID: existing.CoverImageID,
+		PublishAt:    existing.PublishAt,
+		UnpublishAt:  existing.UnpublishAt,
+	}
+	if err := s.update(ctx, id, article, editorID, &revision.ID); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// RunScheduledTransitions publishes and unpublishes every article whose
+// schedule has passed, including ones missed while no scheduler was running.
+// Each transition fires the usual article.updated event.
+func (s *articleService) RunScheduledTransitions(ctx context.Context, now time.Time) (int, error) {
+	applied := 0
+
+	due, err := s.repo.ListDuePublish(ctx, now)
+	if err != nil {
+		return applied, err
+	}
+	for _, article := range due {
+		// Each transition runs, and is cached and published, as its tenant
+		tenantCtx := tenant.NewContext(ctx, article.TenantID)
+		ok, err := s.applyTransition(tenantCtx, article.ID, func(repo repository.ArticleRepository) (bool, error) {
+			return repo.MarkPublished(tenantCtx, article.ID, now)
+		})
+		if err != nil {
+			return applied, err
+		}
+		if ok {
+			applied++
+		}
+	}
+
+	due, err = s.repo.ListDueUnpublish(ctx, now)
+	if err != nil {
+		return applied, err
+	}
+	for _, article := range due {
+		tenantCtx := tenant.NewContext(ctx, article.TenantID)
+		ok, err := s.applyTransition(tenantCtx, article.ID, func(repo repository.ArticleRepository) (bool, error) {
+			return repo.MarkUnpublished(tenantCtx, article.ID, now)
+		})
+		if err != nil {
+			return applied, err
+		}
+		if ok {
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+// applyTransition runs one scheduled transition and, if it changed the
+// article, records an article.updated event with the stored state in the
+// same transaction
+func (s *articleService) applyTransition(ctx context.Context, id uuid.UUID, mark func(repo repository.ArticleRepository) (bool, error)) (bool, error) {
+	applied := false
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		repo := repository.NewArticleRepository(tx)
+		ok, err := mark(repo)
+		if err != nil || !ok {
+			return err
+		}
+		article, err := repo.GetByID(ctx, id.String())
+		if err != nil {
+			return err
+		}
+		applied = true
+		return enqueueEvent(ctx, tx, kafka.TopicArticles, kafka.EventArticleUpdated, id.String(), article)
+	})
+	if err != nil || !applied {
+		return false, err
+	}
 
 	// Invalidate cache
 	s.cache.InvalidateArticles(ctx)
 	s.cache.Delete(ctx, fmt.Sprintf("article:detail:%s", id))
 
+	return true, nil
+}
+
+func (s *articleService) GetCalendar(ctx context.Context, from, to time.Time) ([]model.CalendarEntry, error) {
+	return s.repo.ListScheduled(ctx, from.UTC(), to.UTC())
+}
+
+// applySchedule normalizes publish_at/unpublish_at. A future publish_at keeps
+// the article as a draft until the scheduler flips it; a past one publishes
+// immediately.
+func applySchedule(article *model.Article, now time.Time) error {
+	if article.PublishAt != nil {
+		publishAt := article.PublishAt.UTC()
+		if publishAt.After(now) {
+			article.Published = false
+			article.PublishedAt = nil
+			article.PublishAt = &publishAt
+		} else {
+			article.Published = true
+			article.PublishAt = nil
+		}
+	}
+
+	if article.UnpublishAt != nil {
+		unpublishAt := article.UnpublishAt.UTC()
+		if !unpublishAt.After(now) || (article.PublishAt != nil && !unpublishAt.After(*article.PublishAt)) {
+			return ErrInvalidSchedule
+		}
+		article.UnpublishAt = &unpublishAt
+	}
+	return nil
+}
+
+// render stores the HTML and reading metadata for the article's Markdown and
+// fills in the excerpt from the first paragraph when none was given
+func (s *articleService) render(article *model.Article) error {
+	result, err := s.renderer.Render(article.Content)
+	if err != nil {
+		return err
+	}
+
+	article.ContentHTML = result.HTML
+	article.TOC = make(model.TableOfContents, len(result.TOC))
+	for i, entry := range result.TOC {
+		article.TOC[i] = model.TOCEntry{Level: entry.Level, Text: entry.Text, ID: entry.ID}
+	}
+	article.WordCount = result.WordCount
+	article.ReadingTimeMinutes = result.ReadingTimeMinutes
+	if article.Excerpt == "" {
+		article.Excerpt = result.Excerpt
+	}
+	return nil
+}
+
+// renderIfMissing covers articles written before rendering on save existed;
+// the output is served but not persisted until the next edit
+func (s *articleService) renderIfMissing(article *model.Article) {
+	if article.ContentHTML != "" || article.Content == "" {
+		return
+	}
+	s.render(article)
+}
+
+// resolveCoverImage checks the cover exists and attaches it for the response
+func (s *articleService) resolveCoverImage(ctx context.Context, article *model.Article) error {
+	if article.CoverImageID == nil {
+		article.CoverImage = nil
+		return nil
+	}
+	media, err := s.mediaRepo.GetByID(ctx, article.CoverImageID.String())
+	if err != nil {
+		return err // ErrMediaNotFound for unknown ids
+	}
+	article.CoverImage = media
 	return nil
 }
 
+// resolveTags replaces the slug-only tags set by the handler with stored tags
+func (s *articleService) resolveTags(ctx context.Context, article *model.Article) error {
+	if article.Tags == nil {
+		return nil
+	}
+
+	slugs := make([]string, 0, len(article.Tags))
+	for _, t := range article.Tags {
+		slugs = append(slugs, t.Slug)
+	}
+
+	tags, err := s.tagRepo.GetBySlugs(ctx, slugs)
+	if err != nil {
+		return err
+	}
+	article.Tags = tags
+	return nil
+}
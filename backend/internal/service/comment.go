@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCommentRateLimited   = errors.New("too many comments, please try again later")
+	ErrInvalidParentComment = errors.New("parent comment not found on this article")
+	ErrInvalidCommentStatus = errors.New("status must be pending, approved or spam")
+)
+
+// Comments linking out more than this are held as spam
+const maxCommentLinks = 2
+
+var commentLinkPattern = regexp.MustCompile(`(?i)https?://|www\.`)
+
+// CommentSubmission is a visitor's comment as posted, before moderation
+type CommentSubmission struct {
+	ArticleID   string
+	ParentID    *uuid.UUID
+	AuthorName  string
+	AuthorEmail string
+	Content     string
+	// Honeypot is a form field hidden from people; only bots fill it in
+	Honeypot  string
+	IP        string
+	UserAgent string
+}
+
+type CommentService interface {
+	// CreateComment queues a comment for moderation. Honeypot hits return a
+	// nil comment and no error so bots can't tell they were caught.
+	CreateComment(ctx context.Context, submission CommentSubmission) (*model.Comment, error)
+	// GetArticleComments returns an article's approved comments as threads
+	GetArticleComments(ctx context.Context, articleID string) ([]model.Comment, error)
+	ListComments(ctx context.Context, page, limit int, status, articleID string) ([]model.Comment, int64, error)
+	UpdateCommentStatus(ctx context.Context, id, status string) (*model.Comment, error)
+	DeleteComment(ctx context.Context, id string) error
+}
+
+type commentService struct {
+	repo        repository.CommentRepository
+	articleRepo repository.ArticleRepository
+	cache       *cache.RedisCache
+	ipSalt      string
+	rateLimit   int
+	rateWindow  time.Duration
+}
+
+func NewCommentService(repo repository.CommentRepository, articleRepo repository.ArticleRepository, cache *cache.RedisCache, ipSalt string, rateLimit int, rateWindow time.Duration) CommentService {
+	return &commentService{
+		repo:        repo,
+		articleRepo: articleRepo,
+		cache:       cache,
+		ipSalt:      ipSalt,
+		rateLimit:   rateLimit,
+		rateWindow:  rateWindow,
+	}
+}
+
+func (s *commentService) CreateComment(ctx context.Context, submission CommentSubmission) (*model.Comment, error) {
+	if strings.TrimSpace(submission.Honeypot) != "" {
+		return nil, nil
+	}
+
+	// Only published articles take comments
+	article, err := s.articleRepo.GetByID(ctx, submission.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	if !article.Published {
+		return nil, repository.ErrArticleNotFound
+	}
+
+	ipHash := s.hashIP(submission.IP)
+	if s.rateLimit > 0 {
+		count, err := s.cache.Increment(ctx, fmt.Sprintf("ratelimit:comments:%s", ipHash), s.rateWindow)
+		if err == nil && count > int64(s.rateLimit) {
+			return nil, ErrCommentRateLimited
+		}
+	}
+
+	if submission.ParentID != nil {
+		parent, err := s.repo.GetByID(ctx, submission.ParentID.String())
+		if err != nil {
+			if errors.Is(err, repository.ErrCommentNotFound) {
+				return nil, ErrInvalidParentComment
+			}
+			return nil, err
+		}
+		if parent.ArticleID != article.ID || parent.Status != model.CommentStatusApproved {
+			return nil, ErrInvalidParentComment
+		}
+	}
+
+	comment := &model.Comment{
+		ArticleID:   article.ID,
+		ParentID:    submission.ParentID,
+		AuthorName:  strings.TrimSpace(submission.AuthorName),
+		AuthorEmail: strings.ToLower(strings.TrimSpace(submission.AuthorEmail)),
+		Content:     strings.TrimSpace(submission.Content),
+		Status:      model.CommentStatusPending,
+		IPHash:      ipHash,
+		UserAgent:   truncate(submission.UserAgent, 500),
+	}
+	if len(commentLinkPattern.FindAllStringIndex(comment.Content, -1)) > maxCommentLinks {
+		comment.Status = model.CommentStatusSpam
+	}
+
+	err = s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewCommentRepository(tx).Create(ctx, comment); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicComments, kafka.EventCommentCreated, comment.ID.String(), comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (s *commentService) GetArticleComments(ctx context.Context, articleID string) ([]model.Comment, error) {
+	cacheKey := fmt.Sprintf("comments:article:%s", articleID)
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil {
+		var comments []model.Comment
+		if err := json.Unmarshal(cached, &comments); err == nil {
+			return comments, nil
+		}
+	}
+
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if !article.Published {
+		return nil, repository.ErrArticleNotFound
+	}
+
+	comments, err := s.repo.ListApproved(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emails are for moderators, not the public
+	for i := range comments {
+		comments[i].AuthorEmail = ""
+	}
+	threads := buildThreads(comments)
+
+	if data, err := json.Marshal(threads); err == nil {
+		s.cache.Set(ctx, cacheKey, data, 10*time.Minute)
+	}
+
+	return threads, nil
+}
+
+// buildThreads nests replies under their parents. comments must be in
+// chronological order; replies whose parent isn't in the list (because it
+// was moderated away) are dropped along with their own replies.
+func buildThreads(comments []model.Comment) []model.Comment {
+	children := make(map[uuid.UUID][]model.Comment)
+	var roots []model.Comment
+	for _, c := range comments {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var attach func(c *model.Comment)
+	attach = func(c *model.Comment) {
+		c.Replies = children[c.ID]
+		for i := range c.Replies {
+			attach(&c.Replies[i])
+		}
+	}
+
+	threads := make([]model.Comment, len(roots))
+	for i := range roots {
+		threads[i] = roots[i]
+		attach(&threads[i])
+	}
+	return threads
+}
+
+func (s *commentService) ListComments(ctx context.Context, page, limit int, status, articleID string) ([]model.Comment, int64, error) {
+	if status != "" && !validCommentStatus(status) {
+		return nil, 0, ErrInvalidCommentStatus
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.repo.List(ctx, page, limit, status, articleID)
+}
+
+func (s *commentService) UpdateCommentStatus(ctx context.Context, id, status string) (*model.Comment, error) {
+	if !validCommentStatus(status) {
+		return nil, ErrInvalidCommentStatus
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, status, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, comment.ArticleID)
+
+	return comment, nil
+}
+
+func (s *commentService) DeleteComment(ctx context.Context, id string) error {
+	comment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, comment.ArticleID)
+
+	return nil
+}
+
+// invalidate drops the article's cached thread and the cached articles that
+// carry its comment count
+func (s *commentService) invalidate(ctx context.Context, articleID uuid.UUID) {
+	s.cache.Delete(ctx, fmt.Sprintf("comments:article:%s", articleID))
+	s.cache.InvalidateArticles(ctx)
+	s.cache.Delete(ctx, fmt.Sprintf("article:detail:%s", articleID))
+}
+
+// hashIP keeps visitors' addresses out of the database while still letting
+// comments from one address be rate limited and matched up
+func (s *commentService) hashIP(ip string) string {
+	sum := sha256.Sum256([]byte(s.ipSalt + ip))
+	return hex.EncodeToString(sum[:])
+}
+
+func validCommentStatus(status string) bool {
+	switch status {
+	case model.CommentStatusPending, model.CommentStatusApproved, model.CommentStatusSpam:
+		return true
+	}
+	return false
+}
+
+// truncate cuts s to at most n bytes of valid UTF-8, ending on a rune
+// boundary; Postgres rejects anything else
+func truncate(s string, n int) string {
+	s = strings.ToValidUTF8(s, "")
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
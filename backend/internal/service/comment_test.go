@@ -0,0 +1,41 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"short", "curl/8.0", 500, "curl/8.0"},
+		{"exact", "abcde", 5, "abcde"},
+		{"ascii", "abcdef", 4, "abcd"},
+		{"cut before a two-byte rune", "abcé", 4, "abc"},
+		{"cut inside a three-byte rune", "ab€cd", 4, "ab"},
+		{"cut after a rune", "ab€cd", 5, "ab€"},
+		{"cut inside a four-byte rune", "😀😀", 6, "😀"},
+		{"nothing fits", "😀", 3, ""},
+		{"invalid bytes dropped", "Mozilla\xff/5.0", 500, "Mozilla/5.0"},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.s, tt.n)
+		if got != tt.want {
+			t.Errorf("%s: truncate(%q, %d) = %q, want %q", tt.name, tt.s, tt.n, got, tt.want)
+		}
+		if !utf8.ValidString(got) || len(got) > tt.n {
+			t.Errorf("%s: truncate(%q, %d) = %q is invalid or too long", tt.name, tt.s, tt.n, got)
+		}
+	}
+
+	// A long user agent of multi-byte text, as a 500-byte column limit sees it
+	ua := strings.Repeat("ü", 300)
+	if got := truncate(ua, 500); len(got) != 500 || !utf8.ValidString(got) {
+		t.Errorf("truncated to %d bytes, valid %v", len(got), utf8.ValidString(got))
+	}
+}
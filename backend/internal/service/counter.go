@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+)
+
+var ErrInvalidReaction = errors.New("reaction must be like or clap")
+
+// Buffered counts live in one Redis hash, fields "<target>:<id>:<metric>"
+const pendingCountersKey = "counters:pending"
+
+// Visitor identifies a reader well enough to count them once, without
+// storing who they are
+type Visitor struct {
+	IP        string
+	UserAgent string
+}
+
+type CounterService interface {
+	// RecordView counts a view unless the visitor already viewed the target
+	// within the view window. It reports whether the view was counted.
+	RecordView(ctx context.Context, target, id string, visitor Visitor) (bool, error)
+	// React counts a like (once per visitor per window) or a clap (up to the
+	// clap limit per visitor per window)
+	React(ctx context.Context, target, id, reaction string, visitor Visitor) (bool, error)
+	// Flush writes buffered counts to the database and reports how many
+	// articles and projects were updated
+	Flush(ctx context.Context) (int, error)
+}
+
+type counterService struct {
+	repo           repository.CounterRepository
+	cache          *cache.RedisCache
+	visitorSalt    string
+	viewWindow     time.Duration
+	reactionWindow time.Duration
+	maxClaps       int
+}
+
+func NewCounterService(repo repository.CounterRepository, cache *cache.RedisCache, visitorSalt string, viewWindow, reactionWindow time.Duration, maxClaps int) CounterService {
+	if maxClaps < 1 {
+		maxClaps = 1
+	}
+	return &counterService{
+		repo:           repo,
+		cache:          cache,
+		visitorSalt:    visitorSalt,
+		viewWindow:     viewWindow,
+		reactionWindow: reactionWindow,
+		maxClaps:       maxClaps,
+	}
+}
+
+func (s *counterService) RecordView(ctx context.Context, target, id string, visitor Visitor) (bool, error) {
+	return s.count(ctx, target, id, model.CounterView, 1, s.viewWindow, visitor)
+}
+
+func (s *counterService) React(ctx context.Context, target, id, reaction string, visitor Visitor) (bool, error) {
+	switch reaction {
+	case model.ReactionLike:
+		return s.count(ctx, target, id, reaction, 1, s.reactionWindow, visitor)
+	case model.ReactionClap:
+		return s.count(ctx, target, id, reaction, s.maxClaps, s.reactionWindow, visitor)
+	}
+	return false, ErrInvalidReaction
+}
+
+// count buffers one interaction if the visitor has made fewer than limit of
+// them on the target within window
+func (s *counterService) count(ctx context.Context, target, id, metric string, limit int, window time.Duration, visitor Visitor) (bool, error) {
+	targetID, err := uuid.Parse(id)
+	if err != nil {
+		return false, notFound(target)
+	}
+
+	seenKey := fmt.Sprintf("counters:seen:%s:%s:%s:%s", target, targetID, metric, s.hashVisitor(visitor))
+	seen, err := s.cache.Increment(ctx, seenKey, window)
+	if err != nil {
+		return false, err
+	}
+	if seen > int64(limit) {
+		return false, nil
+	}
+
+	// Checked only for counted interactions so repeat visits stay off the
+	// database entirely
+	exists, err := s.repo.Exists(ctx, target, targetID.String())
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, notFound(target)
+	}
+
+	field := fmt.Sprintf("%s:%s:%s", target, targetID, metric)
+	if err := s.cache.IncrementField(ctx, pendingCountersKey, field, 1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *counterService) Flush(ctx context.Context) (int, error) {
+	pending, err := s.cache.DrainHash(ctx, pendingCountersKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	byTarget := make(map[string]*model.CounterDelta)
+	for field, n := range pending {
+		parts := strings.Split(field, ":")
+		if len(parts) != 3 {
+			continue
+		}
+		id, err := uuid.Parse(parts[1])
+		if err != nil {
+			continue
+		}
+
+		key := parts[0] + ":" + parts[1]
+		delta, ok := byTarget[key]
+		if !ok {
+			delta = &model.CounterDelta{Target: parts[0], ID: id}
+			byTarget[key] = delta
+		}
+		switch parts[2] {
+		case model.CounterView:
+			delta.Views += n
+		case model.ReactionLike:
+			delta.Likes += n
+		case model.ReactionClap:
+			delta.Claps += n
+		}
+	}
+
+	// A fixed row order keeps concurrent flushes from deadlocking
+	keys := make([]string, 0, len(byTarget))
+	for key := range byTarget {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	deltas := make([]model.CounterDelta, 0, len(keys))
+	for _, key := range keys {
+		deltas = append(deltas, *byTarget[key])
+	}
+
+	if err := s.repo.Apply(ctx, deltas); err != nil {
+		// Put the counts back for the next flush rather than losing them
+		for field, n := range pending {
+			s.cache.IncrementField(context.Background(), pendingCountersKey, field, n)
+		}
+		return 0, err
+	}
+	return len(deltas), nil
+}
+
+// hashVisitor derives a stable, salted id from the visitor's IP and browser
+func (s *counterService) hashVisitor(visitor Visitor) string {
+	sum := sha256.Sum256([]byte(s.visitorSalt + visitor.IP + "|" + visitor.UserAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+func notFound(target string) error {
+	if target == model.CounterTargetProject {
+		return repository.ErrProjectNotFound
+	}
+	return repository.ErrArticleNotFound
+}
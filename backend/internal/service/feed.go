@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/pkg/feed"
+)
+
+var ErrInvalidFeedFormat = errors.New("invalid feed format")
+
+// Number of most recent articles included in every feed
+const feedItemLimit = 20
+
+// Paths the feeds are served from, relative to the API URL
+var feedPaths = map[string]string{
+	feed.FormatRSS:  "/feed.xml",
+	feed.FormatAtom: "/atom.xml",
+	feed.FormatJSON: "/feed.json",
+}
+
+// RenderedFeed is an encoded feed plus the validators for conditional GETs
+type RenderedFeed struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+type FeedService interface {
+	// GetFeed renders the latest articles, in the locale where translated;
+	// an empty locale is the default
+	GetFeed(ctx context.Context, format, tag, locale string) (*RenderedFeed, error)
+}
+
+type feedService struct {
+	articleRepo     repository.ArticleRepository
+	tagRepo         repository.TagRepository
+	portfolioRepo   repository.PortfolioRepository
+	translationRepo repository.ArticleTranslationRepository
+	cache           *cache.RedisCache
+	tenants         TenantService
+	defaultLocale   string
+	locales         []string
+}
+
+func NewFeedService(articleRepo repository.ArticleRepository, tagRepo repository.TagRepository, portfolioRepo repository.PortfolioRepository, translationRepo repository.ArticleTranslationRepository, cache *cache.RedisCache, tenants TenantService, defaultLocale string, locales []string) FeedService {
+	return &feedService{
+		articleRepo:     articleRepo,
+		tagRepo:         tagRepo,
+		portfolioRepo:   portfolioRepo,
+		translationRepo: translationRepo,
+		cache:           cache,
+		tenants:         tenants,
+		defaultLocale:   defaultLocale,
+		locales:         locales,
+	}
+}
+
+func (s *feedService) GetFeed(ctx context.Context, format, tag, locale string) (*RenderedFeed, error) {
+	path, ok := feedPaths[format]
+	if !ok {
+		return nil, ErrInvalidFeedFormat
+	}
+	if locale == "" {
+		locale = s.defaultLocale
+	}
+
+	// Feeds live under articles:* so every article write drops them
+	key := fmt.Sprintf("articles:feed:%s:%s:%s", format, locale, tag)
+	if cached, err := s.cache.Get(ctx, key); err == nil {
+		var rendered RenderedFeed
+		if err := json.Unmarshal(cached, &rendered); err == nil {
+			return &rendered, nil
+		}
+	}
+
+	f, err := s.build(ctx, path, tag, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := f.Encode(format)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	rendered := &RenderedFeed{
+		Body:         body,
+		ContentType:  feed.ContentTypes[format],
+		ETag:         fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16])),
+		LastModified: f.Updated,
+	}
+
+	if data, err := json.Marshal(rendered); err == nil {
+		s.cache.Set(ctx, key, data, time.Hour)
+	}
+
+	return rendered, nil
+}
+
+func (s *feedService) build(ctx context.Context, path, tagSlug, locale string) (*feed.Feed, error) {
+	portfolio, err := s.portfolioRepo.Get(ctx)
+	if err != nil && !errors.Is(err, repository.ErrPortfolioNotFound) {
+		return nil, err
+	}
+	if portfolio == nil {
+		portfolio = &model.Portfolio{Name: "Blog"}
+	}
+	urls, err := s.tenants.URLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &feed.Feed{
+		Title:       portfolio.Name,
+		Description: portfolio.Title,
+		Link:        localizedURL(urls.Site, locale, s.defaultLocale, ""),
+		FeedURL:     urls.API + path,
+		Language:    locale,
+		Author:      feed.Author{Name: portfolio.Name, Email: portfolio.Email, URL: urls.Site},
+		Updated:     portfolio.UpdatedAt,
+	}
+	if locale == s.defaultLocale {
+		// The site language may name a region the default locale leaves out
+		if language := portfolioLanguage(portfolio); language != "" {
+			f.Language = language
+		}
+	}
+	for _, loc := range s.locales {
+		f.Alternates = append(f.Alternates, feed.Alternate{Language: loc, URL: localizedURL(urls.Site, loc, s.defaultLocale, "")})
+	}
+
+	query := url.Values{}
+	if locale != s.defaultLocale {
+		query.Set("lang", locale)
+	}
+
+	if tagSlug != "" {
+		tag, err := s.tagRepo.GetBySlug(ctx, tagSlug)
+		if err != nil {
+			return nil, err // ErrTagNotFound for unknown tags
+		}
+		f.Title = fmt.Sprintf("%s - %s", portfolio.Name, tag.Name)
+		if tag.Description != "" {
+			f.Description = tag.Description
+		}
+		query.Set("tag", tag.Slug)
+	}
+	if len(query) > 0 {
+		f.FeedURL += "?" + query.Encode()
+	}
+
+	articles, err := s.articleRepo.ListLatestPublished(ctx, tagSlug, feedItemLimit)
+	if err != nil {
+		return nil, err
+	}
+	alternates, err := s.alternates(ctx, urls.Site, articles)
+	if err != nil {
+		return nil, err
+	}
+	if err := translateArticles(ctx, s.translationRepo, articles, locale, s.defaultLocale); err != nil {
+		return nil, err
+	}
+
+	for _, article := range articles {
+		published := article.CreatedAt
+		if article.PublishedAt != nil {
+			published = *article.PublishedAt
+		}
+
+		item := feed.Item{
+			ID:          "urn:uuid:" + article.ID.String(),
+			Title:       article.Title,
+			Link:        s.articleURL(urls.Site, article.Locale, article.Slug),
+			Summary:     article.Excerpt,
+			ContentHTML: article.ContentHTML,
+			Published:   published,
+			Updated:     article.UpdatedAt,
+			Alternates:  alternates[article.ID],
+		}
+		for _, t := range article.Tags {
+			item.Tags = append(item.Tags, t.Name)
+		}
+		f.Items = append(f.Items, item)
+
+		if article.UpdatedAt.After(f.Updated) {
+			f.Updated = article.UpdatedAt
+		}
+	}
+
+	// HTTP dates have second precision
+	f.Updated = f.Updated.UTC().Truncate(time.Second)
+	return f, nil
+}
+
+// alternates returns the page of each article in every locale it is in
+func (s *feedService) alternates(ctx context.Context, siteURL string, articles []model.Article) (map[uuid.UUID][]feed.Alternate, error) {
+	ids := make([]uuid.UUID, len(articles))
+	alternates := make(map[uuid.UUID][]feed.Alternate, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+		alternates[article.ID] = []feed.Alternate{{Language: s.defaultLocale, URL: s.articleURL(siteURL, s.defaultLocale, article.Slug)}}
+	}
+
+	translations, err := s.translationRepo.ListByOwners(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range translations {
+		alternates[t.ArticleID] = append(alternates[t.ArticleID], feed.Alternate{Language: t.Locale, URL: s.articleURL(siteURL, t.Locale, t.Slug)})
+	}
+	return alternates, nil
+}
+
+func (s *feedService) articleURL(siteURL, locale, slug string) string {
+	return localizedURL(siteURL, locale, s.defaultLocale, "/articles/"+url.PathEscape(slug))
+}
+
+// portfolioLanguage reads the site language from the portfolio settings
+func portfolioLanguage(portfolio *model.Portfolio) string {
+	var settings struct {
+		Language string `json:"language"`
+	}
+	if len(portfolio.Settings) > 0 {
+		json.Unmarshal(portfolio.Settings, &settings)
+	}
+	return settings.Language
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/github"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/tenant"
+	"gorm.io/gorm"
+)
+
+const (
+	// Projects synced per pass
+	githubSyncBatch = 100
+	// Requests left in reserve when a pass stops for the rate limit, so
+	// other clients of the same token aren't starved
+	githubRateReserve = 10
+	// A language must make up this share of a repository's code to count
+	// as one of its primary languages
+	githubLanguageShare = 0.05
+)
+
+type GithubSyncService interface {
+	// SyncAll refreshes the GitHub metadata of linked projects, least
+	// recently synced first, and returns how many it synced. A pass that
+	// runs into the rate limit stops early with a *github.RateLimitError.
+	SyncAll(ctx context.Context) (int, error)
+}
+
+type githubSyncService struct {
+	repo             repository.ProjectRepository
+	client           github.Client
+	cache            *cache.RedisCache
+	syncTechnologies bool
+}
+
+// NewGithubSyncService syncs through client. With syncTechnologies the
+// primary languages of a repository are added to its project's
+// technologies.
+func NewGithubSyncService(repo repository.ProjectRepository, client github.Client, cache *cache.RedisCache, syncTechnologies bool) GithubSyncService {
+	return &githubSyncService{
+		repo:             repo,
+		client:           client,
+		cache:            cache,
+		syncTechnologies: syncTechnologies,
+	}
+}
+
+func (s *githubSyncService) SyncAll(ctx context.Context) (int, error) {
+	projects, err := s.repo.ListGithubLinked(ctx, githubSyncBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for i := range projects {
+		err := s.sync(tenant.NewContext(ctx, projects[i].TenantID), &projects[i])
+		var limited *github.RateLimitError
+		switch {
+		case errors.As(err, &limited):
+			return synced, err
+		case errors.Is(err, github.ErrInvalidURL), errors.Is(err, github.ErrNotFound):
+			// Nothing to sync until the URL is fixed; the project keeps
+			// whatever was synced before. The attempt still counts, so it
+			// goes to the back of the queue instead of being retried first.
+			if err := s.recordFailure(tenant.NewContext(ctx, projects[i].TenantID), &projects[i], err); err != nil {
+				return synced, fmt.Errorf("sync project %s: %w", projects[i].ID, err)
+			}
+			continue
+		case err != nil:
+			if ctx.Err() != nil {
+				return synced, ctx.Err()
+			}
+			return synced, fmt.Errorf("sync project %s: %w", projects[i].ID, err)
+		}
+		synced++
+	}
+	return synced, nil
+}
+
+// sync fetches the project's repository, its languages and latest release,
+// each conditionally on the ETag of the last sync, and stores what changed
+func (s *githubSyncService) sync(ctx context.Context, project *model.Project) error {
+	owner, name, err := github.ParseRepoURL(project.GithubURL)
+	if err != nil {
+		return err
+	}
+
+	// A repository the URL no longer points to starts from scratch
+	info := project.Github
+	if info == nil || !strings.EqualFold(info.Owner, owner) || !strings.EqualFold(info.Name, name) {
+		info = &model.ProjectGithub{ProjectID: project.ID, Owner: owner, Name: name}
+	}
+
+	changed := false
+	var rate github.RateLimit
+
+	repo, resp, err := s.client.GetRepository(ctx, owner, name, info.RepoETag)
+	if err != nil {
+		return err
+	}
+	rate = resp.Rate
+	if !resp.NotModified {
+		applyGithubRepository(info, repo)
+		info.RepoETag = resp.ETag
+		changed = true
+	}
+
+	languages, resp, err := s.client.GetLanguages(ctx, owner, name, info.LanguagesETag)
+	if err != nil {
+		return err
+	}
+	rate = resp.Rate
+	if !resp.NotModified {
+		info.Languages = primaryLanguages(languages)
+		info.LanguagesETag = resp.ETag
+		changed = true
+	}
+
+	release, resp, err := s.client.GetLatestRelease(ctx, owner, name, info.ReleaseETag)
+	if err != nil {
+		return err
+	}
+	rate = resp.Rate
+	if !resp.NotModified {
+		applyGithubRelease(info, release)
+		info.ReleaseETag = resp.ETag
+		changed = true
+	}
+
+	var technologies model.StringArray
+	if s.syncTechnologies {
+		technologies = mergeTechnologies(project.Technologies, info.Languages)
+	}
+	info.SyncedAt = time.Now()
+	info.SyncError = ""
+
+	err = s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		repo := repository.NewProjectRepository(tx)
+		if err := repo.SaveGithub(ctx, info); err != nil {
+			return err
+		}
+		if technologies == nil {
+			return nil
+		}
+		if err := repo.UpdateTechnologies(ctx, project.ID, technologies); err != nil {
+			return err
+		}
+		updated, err := repo.GetByID(ctx, project.ID.String())
+		if err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicProjects, kafka.EventProjectUpdated, project.ID.String(), updated)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	if changed || technologies != nil {
+		s.cache.DeletePattern(ctx, "projects:*")
+		s.cache.Delete(ctx, fmt.Sprintf("project:detail:%s", project.ID))
+	}
+	if technologies != nil {
+		s.cache.DeletePattern(ctx, "resume:*") // Keywords of featured projects
+	}
+
+	// Stop before the budget runs out rather than after
+	if rate.Remaining >= 0 && rate.Remaining < githubRateReserve {
+		return &github.RateLimitError{Reset: rate.Reset}
+	}
+	return nil
+}
+
+// recordFailure marks the project synced now with the error that stopped the
+// sync, keeping any metadata from earlier syncs
+func (s *githubSyncService) recordFailure(ctx context.Context, project *model.Project, syncErr error) error {
+	info := project.Github
+	if info == nil {
+		info = &model.ProjectGithub{ProjectID: project.ID}
+		info.Owner, info.Name, _ = github.ParseRepoURL(project.GithubURL)
+	}
+	info.SyncedAt = time.Now()
+	info.SyncError = syncErr.Error()
+	return s.repo.SaveGithub(ctx, info)
+}
+
+func applyGithubRepository(info *model.ProjectGithub, repo *github.Repository) {
+	info.Stars = repo.Stars
+	info.Forks = repo.Forks
+	info.Topics = repo.Topics
+	info.Archived = repo.Archived
+	info.PushedAt = repo.PushedAt
+	info.License = ""
+	// GitHub reports licenses it can't identify as NOASSERTION
+	if repo.License != nil && repo.License.SPDXID != "NOASSERTION" {
+		info.License = repo.License.SPDXID
+	}
+}
+
+func applyGithubRelease(info *model.ProjectGithub, release *github.Release) {
+	if release == nil {
+		info.LatestRelease = ""
+		info.LatestReleaseURL = ""
+		info.LatestReleaseAt = nil
+		return
+	}
+	info.LatestRelease = release.TagName
+	info.LatestReleaseURL = release.HTMLURL
+	info.LatestReleaseAt = release.PublishedAt
+}
+
+// primaryLanguages keeps the languages that make up a meaningful share of
+// the code, largest first. The largest is always kept.
+func primaryLanguages(bytes map[string]int64) model.StringArray {
+	var total int64
+	languages := make([]string, 0, len(bytes))
+	for language, n := range bytes {
+		total += n
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if bytes[languages[i]] != bytes[languages[j]] {
+			return bytes[languages[i]] > bytes[languages[j]]
+		}
+		return languages[i] < languages[j]
+	})
+
+	primary := model.StringArray{}
+	for i, language := range languages {
+		if i > 0 && float64(bytes[language]) < githubLanguageShare*float64(total) {
+			break
+		}
+		primary = append(primary, language)
+	}
+	return primary
+}
+
+// mergeTechnologies adds the languages missing from technologies, matching
+// case-insensitively so curated spellings win. It returns nil when nothing
+// was added.
+func mergeTechnologies(technologies, languages model.StringArray) model.StringArray {
+	have := make(map[string]bool, len(technologies))
+	for _, technology := range technologies {
+		have[strings.ToLower(technology)] = true
+	}
+
+	var merged model.StringArray
+	for _, language := range languages {
+		if have[strings.ToLower(language)] {
+			continue
+		}
+		if merged == nil {
+			merged = append(model.StringArray{}, technologies...)
+		}
+		merged = append(merged, language)
+		have[strings.ToLower(language)] = true
+	}
+	return merged
+}
@@ -0,0 +1,623 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/pkg/resume"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Skill levels in imported documents, which are free text. Skills with
+// other levels or none (LinkedIn has none) keep their stored level, and new
+// ones start at intermediate.
+var importSkillLevels = map[string]string{
+	"novice":       model.SkillLevelBeginner,
+	"basic":        model.SkillLevelBeginner,
+	"beginner":     model.SkillLevelBeginner,
+	"intermediate": model.SkillLevelIntermediate,
+	"advanced":     model.SkillLevelAdvanced,
+	"expert":       model.SkillLevelExpert,
+	"master":       model.SkillLevelExpert,
+}
+
+// Columns an import never writes
+var importIgnoredFields = map[string]bool{
+	"id":         true,
+	"position":   true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// ImportResult is what an import changed, or would change on a dry run
+type ImportResult struct {
+	Format    string                  `json:"format"`
+	DryRun    bool                    `json:"dry_run"`
+	Portfolio []FieldChange           `json:"portfolio"`
+	Sections  map[string]*SectionDiff `json:"sections"`
+	Projects  *SectionDiff            `json:"projects"`
+}
+
+// SectionDiff is what an import does to the entries of a section.
+// Entries are matched to existing ones by a natural key, such as company,
+// role and start month for experience, or the name of a skill or project.
+type SectionDiff struct {
+	Created   []string       `json:"created"`
+	Updated   []EntryChange  `json:"updated"`
+	Unchanged int            `json:"unchanged"`
+	Skipped   []SkippedEntry `json:"skipped"` // Invalid entries, left out of the import
+}
+
+type EntryChange struct {
+	ID      uuid.UUID     `json:"id"`
+	Label   string        `json:"label"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange is one field's stored value and the value imported over it
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type SkippedEntry struct {
+	Label  string `json:"label"`
+	Reason string `json:"reason"`
+}
+
+type ImportService interface {
+	// Import merges a JSON Resume document or LinkedIn data export into the
+	// portfolio, resume sections and projects. Fields the document leaves
+	// empty keep their stored values and nothing is deleted. With dryRun
+	// nothing is written.
+	Import(ctx context.Context, data []byte, dryRun bool) (*ImportResult, error)
+}
+
+type importService struct {
+	portfolioRepo repository.PortfolioRepository
+	resume        ResumeRepositories
+	projectRepo   repository.ProjectRepository
+	cache         *cache.RedisCache
+}
+
+func NewImportService(portfolioRepo repository.PortfolioRepository, resume ResumeRepositories, projectRepo repository.ProjectRepository, cache *cache.RedisCache) ImportService {
+	return &importService{
+		portfolioRepo: portfolioRepo,
+		resume:        resume,
+		projectRepo:   projectRepo,
+		cache:         cache,
+	}
+}
+
+// sectionPlan holds the writes an import makes to one section
+type sectionPlan[T any] struct {
+	create []*T
+	update []*T
+	diff   *SectionDiff
+}
+
+func (p *sectionPlan[T]) changed() bool {
+	return len(p.create) > 0 || len(p.update) > 0
+}
+
+func (s *importService) Import(ctx context.Context, data []byte, dryRun bool) (*ImportResult, error) {
+	doc, format, err := resume.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio, portfolioChanges, err := s.planPortfolio(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	experience, err := planResumeSection(ctx, s.resume.Experience, importExperience(doc), validateExperience,
+		func(e *model.Experience) string {
+			return importKey(e.Company, e.Role, e.StartDate.Format("2006-01"))
+		},
+		func(e *model.Experience) string {
+			return fmt.Sprintf("%s at %s", e.Role, e.Company)
+		})
+	if err != nil {
+		return nil, err
+	}
+	education, err := planResumeSection(ctx, s.resume.Education, importEducation(doc), validateEducation,
+		func(e *model.Education) string { return importKey(e.Institution, e.StartDate.Format("2006")) },
+		func(e *model.Education) string { return e.Institution })
+	if err != nil {
+		return nil, err
+	}
+	skills, err := planResumeSection(ctx, s.resume.Skills, importSkills(doc), validateImportedSkill,
+		func(e *model.Skill) string { return importKey(e.Name) },
+		func(e *model.Skill) string { return e.Name })
+	if err != nil {
+		return nil, err
+	}
+	for _, skill := range skills.create {
+		if skill.Level == "" {
+			skill.Level = model.SkillLevelIntermediate
+		}
+	}
+	certifications, err := planResumeSection(ctx, s.resume.Certifications, importCertifications(doc), validateCertification,
+		func(e *model.Certification) string { return importKey(e.Name, e.Issuer) },
+		func(e *model.Certification) string { return e.Name })
+	if err != nil {
+		return nil, err
+	}
+	awards, err := planResumeSection(ctx, s.resume.Awards, importAwards(doc), validateAward,
+		func(e *model.Award) string { return importKey(e.Title, e.AwardedOn.Format("2006")) },
+		func(e *model.Award) string { return e.Title })
+	if err != nil {
+		return nil, err
+	}
+
+	existingProjects, err := s.allProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := planEntries(existingProjects, importProjects(doc),
+		func(p *model.Project) error {
+			return requireFields(map[string]bool{"name": filled(p.Name)})
+		},
+		func(p *model.Project) string { return importKey(p.Name) },
+		func(p *model.Project) string { return p.Name })
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		Format:    format,
+		DryRun:    dryRun,
+		Portfolio: portfolioChanges,
+		Sections: map[string]*SectionDiff{
+			model.ResumeExperience:     experience.diff,
+			model.ResumeEducation:      education.diff,
+			model.ResumeSkills:         skills.diff,
+			model.ResumeCertifications: certifications.diff,
+			model.ResumeAwards:         awards.diff,
+		},
+		Projects: projects.diff,
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	// One transaction, so a failed import leaves everything as it was
+	err = s.portfolioRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if len(portfolioChanges) > 0 {
+			if err := repository.NewPortfolioRepository(tx).CreateOrUpdate(ctx, portfolio); err != nil {
+				return err
+			}
+			if err := enqueueEvent(ctx, tx, kafka.TopicResume, kafka.EventPortfolioUpdated, portfolio.ID.String(), portfolio); err != nil {
+				return err
+			}
+		}
+		if err := applyResumeSection(ctx, tx, s.resume.Experience, model.ResumeExperience, experience); err != nil {
+			return err
+		}
+		if err := applyResumeSection(ctx, tx, s.resume.Education, model.ResumeEducation, education); err != nil {
+			return err
+		}
+		if err := applyResumeSection(ctx, tx, s.resume.Skills, model.ResumeSkills, skills); err != nil {
+			return err
+		}
+		if err := applyResumeSection(ctx, tx, s.resume.Certifications, model.ResumeCertifications, certifications); err != nil {
+			return err
+		}
+		if err := applyResumeSection(ctx, tx, s.resume.Awards, model.ResumeAwards, awards); err != nil {
+			return err
+		}
+		return applyProjects(ctx, tx, projects)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	invalidatePortfolio(ctx, s.cache)
+	if projects.changed() {
+		s.cache.DeletePattern(ctx, "projects:*")
+		s.cache.DeletePattern(ctx, "project:detail:*")
+		s.cache.DeletePattern(ctx, "articles:sitemap:*")
+	}
+
+	return result, nil
+}
+
+// planPortfolio merges the document's basics into the stored portfolio.
+// Social links are merged by network rather than replaced.
+func (s *importService) planPortfolio(ctx context.Context, doc *resume.Resume) (*model.Portfolio, []FieldChange, error) {
+	existing, err := s.portfolioRepo.Get(ctx)
+	if errors.Is(err, repository.ErrPortfolioNotFound) {
+		existing, err = &model.Portfolio{}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	links := map[string]string{}
+	if len(existing.SocialLinks) > 0 {
+		json.Unmarshal(existing.SocialLinks, &links)
+	}
+	imported := map[string]string{}
+	for k, v := range links {
+		imported[k] = v
+	}
+	if doc.Basics.Phone != "" {
+		imported["phone"] = doc.Basics.Phone
+	}
+	if doc.Basics.URL != "" {
+		imported["website"] = doc.Basics.URL
+	}
+	for _, profile := range doc.Basics.Profiles {
+		if profile.URL != "" && profile.Network != "" {
+			imported[strings.ToLower(profile.Network)] = profile.URL
+		}
+	}
+
+	incoming := &model.Portfolio{
+		Name:  doc.Basics.Name,
+		Title: doc.Basics.Label,
+		Bio:   doc.Basics.Summary,
+		Email: doc.Basics.Email,
+	}
+	if !reflect.DeepEqual(links, imported) {
+		socialLinks, err := json.Marshal(imported)
+		if err != nil {
+			return nil, nil, err
+		}
+		incoming.SocialLinks = datatypes.JSON(socialLinks)
+	}
+
+	merged, changes, err := mergeImported(existing, incoming)
+	if err != nil {
+		return nil, nil, err
+	}
+	if changes == nil {
+		changes = []FieldChange{}
+	}
+	if len(changes) > 0 && !filled(merged.Name) {
+		return nil, nil, fmt.Errorf("%w: name", ErrResumeFieldRequired)
+	}
+	return merged, changes, nil
+}
+
+func (s *importService) allProjects(ctx context.Context) ([]model.Project, error) {
+	var all []model.Project
+	for page := 1; ; page++ {
+		projects, total, err := s.projectRepo.List(ctx, page, 100, model.ProjectFilter{})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, projects...)
+		if len(projects) == 0 || int64(len(all)) >= total {
+			return all, nil
+		}
+	}
+}
+
+func planResumeSection[T any, P resumeEntry[T]](ctx context.Context, repo repository.ResumeRepository[T], imported []T, validate func(*T) error, key, label func(*T) string) (*sectionPlan[T], error) {
+	existing, err := repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return planEntries(existing, imported, func(entry *T) error {
+		return checkResumeEntry[T, P](entry, validate)
+	}, key, label)
+}
+
+// planEntries matches imported entries to existing ones by key: matches
+// become updates if the import changes them and the rest are created
+func planEntries[T any](existing, imported []T, validate func(*T) error, key, label func(*T) string) (*sectionPlan[T], error) {
+	byKey := make(map[string]*T, len(existing))
+	for i := range existing {
+		byKey[key(&existing[i])] = &existing[i]
+	}
+
+	plan := &sectionPlan[T]{diff: &SectionDiff{
+		Created: []string{},
+		Updated: []EntryChange{},
+		Skipped: []SkippedEntry{},
+	}}
+	seen := make(map[string]bool, len(imported))
+	for i := range imported {
+		entry := &imported[i]
+		if err := validate(entry); err != nil {
+			plan.diff.Skipped = append(plan.diff.Skipped, SkippedEntry{Label: label(entry), Reason: err.Error()})
+			continue
+		}
+		k := key(entry)
+		if seen[k] {
+			plan.diff.Skipped = append(plan.diff.Skipped, SkippedEntry{Label: label(entry), Reason: "listed more than once"})
+			continue
+		}
+		seen[k] = true
+
+		current, ok := byKey[k]
+		if !ok {
+			plan.create = append(plan.create, entry)
+			plan.diff.Created = append(plan.diff.Created, label(entry))
+			continue
+		}
+
+		merged, changes, err := mergeImported(current, entry)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			plan.diff.Unchanged++
+			continue
+		}
+		plan.update = append(plan.update, merged)
+		plan.diff.Updated = append(plan.diff.Updated, EntryChange{ID: entryID(merged), Label: label(entry), Changes: changes})
+	}
+	return plan, nil
+}
+
+// mergeImported overlays the fields an imported entry sets on the stored
+// one and lists those that differ. It works on the JSON form, so it applies
+// to every model the import writes.
+func mergeImported[T any](current, imported *T) (*T, []FieldChange, error) {
+	var stored, incoming map[string]interface{}
+	if err := roundTrip(current, &stored); err != nil {
+		return nil, nil, err
+	}
+	if err := roundTrip(imported, &incoming); err != nil {
+		return nil, nil, err
+	}
+
+	fields := make([]string, 0, len(incoming))
+	for field := range incoming {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var changes []FieldChange
+	for _, field := range fields {
+		value := incoming[field]
+		if importIgnoredFields[field] || isEmptyJSON(value) || reflect.DeepEqual(stored[field], value) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, From: stored[field], To: value})
+		stored[field] = value
+	}
+
+	merged := new(T)
+	if err := roundTrip(stored, merged); err != nil {
+		return nil, nil, err
+	}
+	return merged, changes, nil
+}
+
+func roundTrip(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// isEmptyJSON reports whether a decoded JSON value carries nothing to
+// import. False and zero are empty too: imports set no flags or counters.
+func isEmptyJSON(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// entryID reads the id of a resume entry or project
+func entryID(entry interface{}) uuid.UUID {
+	switch e := entry.(type) {
+	case model.ResumeEntry:
+		return e.Item().ID
+	case *model.Project:
+		return e.ID
+	}
+	return uuid.Nil
+}
+
+func applyResumeSection[T any, P resumeEntry[T]](ctx context.Context, tx *gorm.DB, repo repository.ResumeRepository[T], section string, plan *sectionPlan[T]) error {
+	if !plan.changed() {
+		return nil
+	}
+	repo = repo.WithTx(tx)
+	for _, entry := range plan.create {
+		// New entries join the unordered tail until the next reorder
+		item := P(entry).Item()
+		item.ID = uuid.Nil
+		item.Position = nil
+		if err := repo.Create(ctx, entry); err != nil {
+			return err
+		}
+	}
+	for _, entry := range plan.update {
+		if err := repo.Update(ctx, P(entry).Item().ID.String(), entry); err != nil {
+			return err
+		}
+	}
+	return enqueueEvent(ctx, tx, kafka.TopicResume, kafka.EventResumeUpdated, section, map[string]string{"section": section})
+}
+
+func applyProjects(ctx context.Context, tx *gorm.DB, plan *sectionPlan[model.Project]) error {
+	repo := repository.NewProjectRepository(tx)
+	for _, project := range plan.create {
+		// A resume lists the projects its owner wants shown
+		project.Featured = true
+		if err := repo.Create(ctx, project); err != nil {
+			return err
+		}
+		if err := enqueueEvent(ctx, tx, kafka.TopicProjects, kafka.EventProjectCreated, project.ID.String(), project); err != nil {
+			return err
+		}
+	}
+	for _, project := range plan.update {
+		if err := repo.Update(ctx, project); err != nil {
+			return err
+		}
+		if err := enqueueEvent(ctx, tx, kafka.TopicProjects, kafka.EventProjectUpdated, project.ID.String(), project); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importKey joins the fields an entry is matched on, ignoring case
+func importKey(fields ...string) string {
+	for i, field := range fields {
+		fields[i] = strings.ToLower(strings.TrimSpace(field))
+	}
+	return strings.Join(fields, "\x00")
+}
+
+// importDate reads a JSON Resume date; nil if absent or unreadable
+func importDate(date string) *time.Time {
+	t := resume.ParseDate(date)
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// requiredDate is importDate for required columns, which validation then
+// rejects when zero
+func requiredDate(date string) time.Time {
+	if t := importDate(date); t != nil {
+		return *t
+	}
+	return time.Time{}
+}
+
+func importExperience(doc *resume.Resume) []model.Experience {
+	entries := make([]model.Experience, 0, len(doc.Work))
+	for _, w := range doc.Work {
+		entries = append(entries, model.Experience{
+			Company:     w.Name,
+			Role:        w.Position,
+			Location:    w.Location,
+			URL:         w.URL,
+			Description: w.Summary,
+			Highlights:  w.Highlights,
+			StartDate:   requiredDate(w.StartDate),
+			EndDate:     importDate(w.EndDate),
+		})
+	}
+	return entries
+}
+
+func importEducation(doc *resume.Resume) []model.Education {
+	entries := make([]model.Education, 0, len(doc.Education))
+	for _, e := range doc.Education {
+		entries = append(entries, model.Education{
+			Institution: e.Institution,
+			Degree:      e.StudyType,
+			Field:       e.Area,
+			Grade:       e.Score,
+			URL:         e.URL,
+			StartDate:   requiredDate(e.StartDate),
+			EndDate:     importDate(e.EndDate),
+		})
+	}
+	return entries
+}
+
+// importSkills takes a skill with several keywords as a category of them,
+// as in {"name": "Web", "keywords": ["HTML", "CSS"]}, and otherwise as one
+// skill whose keyword, if any, is its category
+func importSkills(doc *resume.Resume) []model.Skill {
+	var entries []model.Skill
+	for _, s := range doc.Skills {
+		level := importSkillLevels[strings.ToLower(strings.TrimSpace(s.Level))]
+		if len(s.Keywords) > 1 {
+			for _, keyword := range s.Keywords {
+				entries = append(entries, model.Skill{Name: keyword, Category: s.Name, Level: level})
+			}
+			continue
+		}
+		skill := model.Skill{Name: s.Name, Level: level}
+		if len(s.Keywords) == 1 {
+			skill.Category = s.Keywords[0]
+		}
+		entries = append(entries, skill)
+	}
+	return entries
+}
+
+// validateImportedSkill lets a skill have no level, which leaves a stored
+// skill's level alone
+func validateImportedSkill(s *model.Skill) error {
+	if s.Level == "" {
+		return requireFields(map[string]bool{"name": filled(s.Name)})
+	}
+	return validateSkill(s)
+}
+
+func importCertifications(doc *resume.Resume) []model.Certification {
+	entries := make([]model.Certification, 0, len(doc.Certificates))
+	for _, c := range doc.Certificates {
+		entries = append(entries, model.Certification{
+			Name:     c.Name,
+			Issuer:   c.Issuer,
+			URL:      c.URL,
+			IssuedOn: requiredDate(c.Date),
+		})
+	}
+	return entries
+}
+
+func importAwards(doc *resume.Resume) []model.Award {
+	entries := make([]model.Award, 0, len(doc.Awards))
+	for _, a := range doc.Awards {
+		entries = append(entries, model.Award{
+			Title:       a.Title,
+			Issuer:      a.Awarder,
+			Description: a.Summary,
+			AwardedOn:   requiredDate(a.Date),
+		})
+	}
+	return entries
+}
+
+// importProjects sends GitHub URLs to github_url and others to live_url
+func importProjects(doc *resume.Resume) []model.Project {
+	entries := make([]model.Project, 0, len(doc.Projects))
+	for _, p := range doc.Projects {
+		project := model.Project{
+			Name:         p.Name,
+			Description:  p.Description,
+			Technologies: p.Keywords,
+		}
+		if u, err := url.Parse(p.URL); err == nil && strings.EqualFold(strings.TrimPrefix(u.Host, "www."), "github.com") {
+			project.GithubURL = p.URL
+		} else {
+			project.LiveURL = p.URL
+		}
+		entries = append(entries, project)
+	}
+	return entries
+}
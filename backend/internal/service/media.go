@@ -0,0 +1,545 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/storage"
+	"github.com/portfolio/backend/internal/tenant"
+	"github.com/portfolio/backend/pkg/blurhash"
+	"github.com/portfolio/backend/pkg/imagemeta"
+	"github.com/portfolio/backend/pkg/webp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrMediaTooLarge        = errors.New("file exceeds the upload size limit")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrEmptyUpload          = errors.New("uploaded file is empty")
+	ErrImageTooLarge        = errors.New("image dimensions exceed the supported maximum")
+	ErrInvalidMediaFormat   = errors.New("unsupported image format; use jpeg, png or webp")
+)
+
+// MediaQueue is the job queue uploads wait on for derivative processing
+const MediaQueue = "media"
+
+// Images above this many pixels are refused, so a small compressed file
+// can't expand into gigabytes of memory when it is decoded
+const maxImagePixels = 50_000_000
+
+const jpegQuality = 85
+
+// Content types accepted for upload, keyed by the sniffed type. SVG is left
+// out on purpose: it can carry script and would be served from our origin.
+var mediaExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// Output formats, by the name used in ?fmt=
+var mediaFormats = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"webp": "image/webp",
+}
+
+// Formats variants are rendered in, keyed by the upload's content type; the
+// first is the fallback for clients without WebP. GIFs get a placeholder but
+// no variants, which would lose their animation.
+var variantFormats = map[string][]string{
+	"image/jpeg": {"jpeg", "webp"},
+	"image/png":  {"png", "webp"},
+	"image/webp": {"webp", "png"},
+}
+
+// MediaFile is an original or variant opened for download
+type MediaFile struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+	ETag        string
+	// Final is set once processing is done: the same request will always
+	// get these bytes, so they can be cached indefinitely
+	Final bool
+}
+
+type MediaService interface {
+	Upload(ctx context.Context, r io.Reader, filename, alt string, uploadedBy uuid.UUID) (*model.Media, error)
+	GetMedia(ctx context.Context, id string) (*model.Media, error)
+	ListMedia(ctx context.Context, page, limit int, contentType string) ([]model.Media, int64, error)
+	UpdateMedia(ctx context.Context, id, alt string) (*model.Media, error)
+	DeleteMedia(ctx context.Context, id string) error
+	// OpenMedia picks the variant closest to the requested width and format,
+	// falling back to the original while variants are still being rendered
+	OpenMedia(ctx context.Context, id string, width int, format string) (*MediaFile, error)
+	// ProcessMedia renders variants and the placeholder for a pending upload
+	ProcessMedia(ctx context.Context, id string) error
+	ReprocessMedia(ctx context.Context, id string) (*model.Media, error)
+	// RequeuePending re-enqueues uploads still pending after olderThan, in
+	// case their job was lost
+	RequeuePending(ctx context.Context, olderThan time.Duration) (int, error)
+}
+
+type mediaService struct {
+	repo          repository.MediaRepository
+	storage       storage.Storage
+	cache         *cache.RedisCache
+	maxSize       int64
+	variantWidths []int
+}
+
+func NewMediaService(repo repository.MediaRepository, storage storage.Storage, cache *cache.RedisCache, maxSize int64, variantWidths []int) MediaService {
+	return &mediaService{
+		repo:          repo,
+		storage:       storage,
+		cache:         cache,
+		maxSize:       maxSize,
+		variantWidths: variantWidths,
+	}
+}
+
+// Upload stores the file under a fresh key and records it. The type is taken
+// from the file's bytes, never from the client's filename or headers.
+// Metadata is stripped before anything is stored; images are then queued for
+// processing rather than resized here.
+func (s *mediaService) Upload(ctx context.Context, r io.Reader, filename, alt string, uploadedBy uuid.UUID) (*model.Media, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrMediaTooLarge
+	}
+	if len(data) == 0 {
+		return nil, ErrEmptyUpload
+	}
+
+	contentType := http.DetectContentType(data)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	ext, ok := mediaExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	media := &model.Media{
+		ID:          uuid.New(),
+		Filename:    filepath.Base(filename),
+		ContentType: contentType,
+		Alt:         alt,
+		UploadedBy:  uploadedBy,
+		Status:      model.MediaStatusReady,
+	}
+	if strings.HasPrefix(contentType, "image/") {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrUnsupportedMediaType
+		}
+		if cfg.Width*cfg.Height > maxImagePixels {
+			return nil, ErrImageTooLarge
+		}
+		media.Width, media.Height = cfg.Width, cfg.Height
+		media.Status = model.MediaStatusPending
+
+		// EXIF can carry GPS coordinates and camera serials
+		if data, err = imagemeta.Strip(contentType, data); err != nil {
+			return nil, ErrUnsupportedMediaType
+		}
+	}
+
+	sum := sha256.Sum256(data)
+	media.Size = int64(len(data))
+	media.Checksum = hex.EncodeToString(sum[:])
+
+	media.Key = fmt.Sprintf("%s/%s%s", time.Now().UTC().Format("2006/01"), media.ID, ext)
+	media.URL = s.storage.URL(media.Key)
+
+	if err := s.storage.Put(ctx, media.Key, bytes.NewReader(data), media.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, media); err != nil {
+		// Don't leave an orphaned object behind
+		s.storage.Delete(ctx, media.Key)
+		return nil, err
+	}
+
+	// A failed enqueue is picked up later by RequeuePending
+	if media.Status == model.MediaStatusPending {
+		s.cache.Enqueue(ctx, MediaQueue, []byte(media.ID.String()))
+	}
+
+	return media, nil
+}
+
+func (s *mediaService) GetMedia(ctx context.Context, id string) (*model.Media, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *mediaService) ListMedia(ctx context.Context, page, limit int, contentType string) ([]model.Media, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.repo.List(ctx, page, limit, contentType)
+}
+
+func (s *mediaService) UpdateMedia(ctx context.Context, id, alt string) (*model.Media, error) {
+	if err := s.repo.UpdateAlt(ctx, id, alt); err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx)
+	return s.repo.GetByID(ctx, id)
+}
+
+// DeleteMedia removes the record first; covers referencing it are cleared and
+// gallery entries dropped by the foreign keys
+func (s *mediaService) DeleteMedia(ctx context.Context, id string) error {
+	media, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+
+	for _, variant := range media.Variants {
+		s.storage.Delete(ctx, variant.Key)
+	}
+	return s.storage.Delete(ctx, media.Key)
+}
+
+func (s *mediaService) OpenMedia(ctx context.Context, id string, width int, format string) (*MediaFile, error) {
+	if format == "jpg" {
+		format = "jpeg"
+	}
+	if _, ok := mediaFormats[format]; format != "" && !ok {
+		return nil, ErrInvalidMediaFormat
+	}
+
+	media, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The original counts as the full-width rendition in its own format
+	renditions := append([]model.MediaVariant{{
+		Width:  media.Width,
+		Format: mediaFormatOf(media.ContentType),
+		Key:    media.Key,
+		Size:   media.Size,
+	}}, media.Variants...)
+	chosen := chooseRendition(renditions, width, format)
+
+	body, err := s.storage.Open(ctx, chosen.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := media.ContentType
+	if chosen.Key != media.Key {
+		contentType = mediaFormats[chosen.Format]
+	}
+
+	return &MediaFile{
+		Body:        body,
+		ContentType: contentType,
+		Size:        chosen.Size,
+		ETag:        strconv.Quote(chosen.Key),
+		Final:       media.Status == model.MediaStatusReady,
+	}, nil
+}
+
+// chooseRendition picks the smallest width at least width wide (the largest
+// when none is, or when width is 0), then the requested format at that width.
+// Formats that weren't rendered there, because they came out larger or the
+// upload hasn't been processed yet, fall back to the original's format.
+func chooseRendition(renditions []model.MediaVariant, width int, format string) model.MediaVariant {
+	target, largest := 0, 0
+	for _, r := range renditions {
+		if r.Width > largest {
+			largest = r.Width
+		}
+		if width > 0 && r.Width >= width && (target == 0 || r.Width < target) {
+			target = r.Width
+		}
+	}
+	if target == 0 {
+		target = largest
+	}
+
+	chosen := renditions[0]
+	for _, r := range renditions {
+		if r.Width != target {
+			continue
+		}
+		if r.Format == format {
+			return r
+		}
+		if chosen.Width != target || r.Format == renditions[0].Format {
+			chosen = r
+		}
+	}
+	return chosen
+}
+
+func mediaFormatOf(contentType string) string {
+	for format, ct := range mediaFormats {
+		if ct == contentType {
+			return format
+		}
+	}
+	return ""
+}
+
+func (s *mediaService) ProcessMedia(ctx context.Context, id string) error {
+	media, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if media.Status != model.MediaStatusPending {
+		return nil
+	}
+	// Jobs are queued by ID alone; the rest runs as the media's tenant
+	ctx = tenant.NewContext(ctx, media.TenantID)
+
+	previous := media.Variants
+	if err := s.process(ctx, media); err != nil {
+		s.repo.UpdateStatus(ctx, media.ID, model.MediaStatusFailed)
+		return fmt.Errorf("process media %s: %w", media.ID, err)
+	}
+
+	// Drop objects of earlier variants that weren't rendered again, e.g.
+	// after the configured widths changed
+	current := make(map[string]bool, len(media.Variants))
+	for _, variant := range media.Variants {
+		current[variant.Key] = true
+	}
+	for _, variant := range previous {
+		if !current[variant.Key] {
+			s.storage.Delete(ctx, variant.Key)
+		}
+	}
+
+	s.invalidate(ctx)
+	return nil
+}
+
+func (s *mediaService) process(ctx context.Context, media *model.Media) error {
+	r, err := s.storage.Open(ctx, media.Key)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	img = imagemeta.Orient(img, imagemeta.Orientation(data))
+
+	bounds := img.Bounds()
+	media.Width, media.Height = bounds.Dx(), bounds.Dy()
+	if media.BlurHash, err = placeholder(img); err != nil {
+		return err
+	}
+
+	media.Variants = nil
+	original := mediaFormatOf(media.ContentType)
+	formats := variantFormats[media.ContentType]
+	for _, width := range s.widthsFor(media.Width) {
+		if len(formats) == 0 {
+			break
+		}
+		var resized image.Image = img
+		if width < media.Width {
+			resized = resize(img, width)
+		}
+
+		// The first format is the fallback every client can show
+		var fallbackSize int64
+		for _, format := range formats {
+			// The original already serves its own format at full size
+			if format == original && width == media.Width {
+				fallbackSize = media.Size
+				continue
+			}
+			data, err := encodeImage(resized, format)
+			if err != nil {
+				return err
+			}
+			if fallbackSize == 0 {
+				fallbackSize = int64(len(data))
+			} else if format == "webp" && original != "webp" && int64(len(data)) >= fallbackSize {
+				// WebP variants are lossless, which rarely pays off for photos
+				continue
+			}
+
+			variant, err := s.storeVariant(ctx, media, resized.Bounds(), format, data)
+			if err != nil {
+				return err
+			}
+			media.Variants = append(media.Variants, *variant)
+		}
+	}
+
+	now := time.Now().UTC()
+	media.Status = model.MediaStatusReady
+	media.ProcessedAt = &now
+	return s.repo.SaveProcessed(ctx, media)
+}
+
+// widthsFor lists the variant widths for an image width pixels wide: each
+// configured width below it, plus the full width if it doesn't exceed the
+// largest configured one. Images are never upscaled.
+func (s *mediaService) widthsFor(width int) []int {
+	var widths []int
+	for _, w := range s.variantWidths {
+		if w < width {
+			widths = append(widths, w)
+		}
+	}
+	if n := len(s.variantWidths); n > 0 && width <= s.variantWidths[n-1] {
+		widths = append(widths, width)
+	}
+	return widths
+}
+
+func encodeImage(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	case "webp":
+		err = webp.Encode(&buf, img)
+	default:
+		err = ErrInvalidMediaFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *mediaService) storeVariant(ctx context.Context, media *model.Media, bounds image.Rectangle, format string, data []byte) (*model.MediaVariant, error) {
+	ext := format
+	if ext == "jpeg" {
+		ext = "jpg"
+	}
+	// Variants live next to the original: 2024/05/<uuid>/640.webp
+	key := fmt.Sprintf("%s/%d.%s", strings.TrimSuffix(media.Key, path.Ext(media.Key)), bounds.Dx(), ext)
+	if err := s.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), mediaFormats[format]); err != nil {
+		return nil, err
+	}
+
+	return &model.MediaVariant{
+		MediaID: media.ID,
+		Width:   bounds.Dx(),
+		Height:  bounds.Dy(),
+		Format:  format,
+		Key:     key,
+		URL:     s.storage.URL(key),
+		Size:    int64(len(data)),
+	}, nil
+}
+
+// resize scales img to width, keeping its aspect ratio
+func resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// placeholder computes the BlurHash from a thumbnail; the hash only holds a
+// few components, so more pixels would just cost time
+func placeholder(img image.Image) (string, error) {
+	bounds := img.Bounds()
+	thumb := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	draw.ApproxBiLinear.Scale(thumb, thumb.Bounds(), img, bounds, draw.Src, nil)
+
+	if bounds.Dy() > bounds.Dx() {
+		return blurhash.Encode(3, 4, thumb)
+	}
+	return blurhash.Encode(4, 3, thumb)
+}
+
+func (s *mediaService) ReprocessMedia(ctx context.Context, id string) (*model.Media, error) {
+	media, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(media.ContentType, "image/") {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	if err := s.repo.UpdateStatus(ctx, media.ID, model.MediaStatusPending); err != nil {
+		return nil, err
+	}
+	if err := s.cache.Enqueue(ctx, MediaQueue, []byte(media.ID.String())); err != nil {
+		return nil, err
+	}
+
+	media.Status = model.MediaStatusPending
+	return media, nil
+}
+
+func (s *mediaService) RequeuePending(ctx context.Context, olderThan time.Duration) (int, error) {
+	pending, err := s.repo.ListPending(ctx, time.Now().UTC().Add(-olderThan), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, media := range pending {
+		if err := s.cache.Enqueue(ctx, MediaQueue, []byte(media.ID.String())); err != nil {
+			return 0, err
+		}
+	}
+	return len(pending), nil
+}
+
+// invalidate drops cached articles and projects, which embed their media
+func (s *mediaService) invalidate(ctx context.Context) {
+	s.cache.InvalidateArticles(ctx)
+	s.cache.DeletePattern(ctx, "article:detail:*")
+	s.cache.DeletePattern(ctx, "projects:*")
+	s.cache.DeletePattern(ctx, "project:detail:*")
+}
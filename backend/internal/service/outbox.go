@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/tenant"
+	"gorm.io/gorm"
+)
+
+type OutboxService interface {
+	// Relay publishes up to limit pending events in order and marks them
+	// sent. It parks events that have failed too often; an error means some
+	// events are still waiting to be retried.
+	Relay(ctx context.Context, limit int) (*RelayResult, error)
+	// Prune deletes events sent longer ago than retention
+	Prune(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// RelayResult is what one Relay call did with the events it read
+type RelayResult struct {
+	Read   int // Fewer than the limit means the outbox is drained
+	Sent   int
+	Parked []model.OutboxEvent // Given up on; kept in the outbox for replay
+}
+
+// eventPublisher is the part of the Kafka producer the relay needs
+type eventPublisher interface {
+	PublishMessages(ctx context.Context, messages []kafka.Message) error
+}
+
+type outboxService struct {
+	repo        repository.OutboxRepository
+	kafka       eventPublisher
+	maxAttempts int
+}
+
+// NewOutboxService relays events through producer, parking any that fail
+// maxAttempts times
+func NewOutboxService(repo repository.OutboxRepository, producer *kafka.Producer, maxAttempts int) OutboxService {
+	if maxAttempts < 1 {
+		maxAttempts = 10
+	}
+	return &outboxService{
+		repo:        repo,
+		kafka:       producer,
+		maxAttempts: maxAttempts,
+	}
+}
+
+func (s *outboxService) Relay(ctx context.Context, limit int) (*RelayResult, error) {
+	events, err := s.repo.ListPending(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &RelayResult{Read: len(events)}
+	if len(events) == 0 {
+		return result, nil
+	}
+
+	messages := make([]kafka.Message, len(events))
+	for i := range events {
+		messages[i] = outboxMessage(&events[i])
+	}
+	if err := s.kafka.PublishMessages(ctx, messages); err == nil {
+		return result, s.markSent(ctx, events, result)
+	} else if kafka.Unavailable(err) {
+		// Nothing can get through, so the whole batch waits; any part of
+		// it that did reach Kafka is dropped by consumers on its event ID
+		s.repo.MarkFailed(ctx, eventIDs(events), err.Error())
+		return result, fmt.Errorf("publish outbox events: %w", err)
+	}
+
+	// Something in the batch can't be published. Send the events one at a
+	// time to find it, holding back the rest of its key to keep their order,
+	// so other aggregates' events still go out.
+	var sent []model.OutboxEvent
+	var relayErr error
+	held := make(map[string]bool)
+	for i := range events {
+		e := &events[i]
+		if held[e.Key] {
+			continue
+		}
+		err := s.kafka.PublishMessages(ctx, []kafka.Message{outboxMessage(e)})
+		if err == nil {
+			sent = append(sent, *e)
+			continue
+		}
+
+		held[e.Key] = true
+		if relayErr == nil {
+			relayErr = fmt.Errorf("publish outbox event %d: %w", e.ID, err)
+		}
+		if kafka.Unavailable(err) {
+			s.repo.MarkFailed(ctx, []int64{e.ID}, err.Error())
+			break
+		}
+		if e.Attempts+1 < s.maxAttempts {
+			s.repo.MarkFailed(ctx, []int64{e.ID}, err.Error())
+			continue
+		}
+		if err := s.repo.Park(ctx, e.ID, err.Error(), time.Now()); err != nil {
+			return result, err
+		}
+		e.Attempts++
+		e.LastError = err.Error()
+		result.Parked = append(result.Parked, *e)
+	}
+
+	if err := s.markSent(ctx, sent, result); err != nil {
+		return result, err
+	}
+	return result, relayErr
+}
+
+func (s *outboxService) markSent(ctx context.Context, events []model.OutboxEvent, result *RelayResult) error {
+	if err := s.repo.MarkSent(ctx, eventIDs(events), time.Now()); err != nil {
+		return err
+	}
+	result.Sent = len(events)
+	return nil
+}
+
+func outboxMessage(e *model.OutboxEvent) kafka.Message {
+	msg := kafka.Message{
+		Topic:     e.Topic,
+		EventType: e.EventType,
+		Key:       e.Key,
+		Value:     []byte(e.Payload),
+	}
+	if e.TenantID != nil {
+		msg.TenantID = e.TenantID.String()
+	}
+	return msg
+}
+
+func eventIDs(events []model.OutboxEvent) []int64 {
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func (s *outboxService) Prune(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.DeleteSentBefore(ctx, time.Now().Add(-retention))
+}
+
+// enqueueEvent records an event in the outbox through tx, so it is published
+// if and only if the transaction commits. key is the aggregate ID; events
+// sharing it are delivered in the order they were enqueued.
+func enqueueEvent(ctx context.Context, tx *gorm.DB, topic, eventType, key string, data interface{}) error {
+	event := kafka.NewEvent(ctx, eventType, data)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	outboxEvent := &model.OutboxEvent{
+		EventID:   uuid.MustParse(event.EventID),
+		Topic:     topic,
+		EventType: eventType,
+		Key:       key,
+		Payload:   string(payload),
+	}
+	if id, ok := tenant.FromContext(ctx); ok {
+		outboxEvent.TenantID = &id
+	}
+	return repository.NewOutboxRepository(tx).Create(ctx, outboxEvent)
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/tenant"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// memOutbox is an OutboxRepository over a slice, in ID order
+type memOutbox struct {
+	events []*model.OutboxEvent
+}
+
+func (r *memOutbox) add(key string, attempts int) *model.OutboxEvent {
+	e := &model.OutboxEvent{
+		ID:        int64(len(r.events) + 1),
+		EventID:   uuid.New(),
+		Topic:     kafka.TopicArticles,
+		EventType: kafka.EventArticleUpdated,
+		Key:       key,
+		Payload:   `{}`,
+		Attempts:  attempts,
+	}
+	r.events = append(r.events, e)
+	return e
+}
+
+func (r *memOutbox) find(id int64) *model.OutboxEvent {
+	for _, e := range r.events {
+		if e.ID == id {
+			return e
+		}
+	}
+	return nil
+}
+
+func (r *memOutbox) Create(ctx context.Context, events ...*model.OutboxEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *memOutbox) ListPending(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
+	var pending []model.OutboxEvent
+	for _, e := range r.events {
+		if e.SentAt == nil && e.FailedAt == nil && len(pending) < limit {
+			pending = append(pending, *e)
+		}
+	}
+	return pending, nil
+}
+
+func (r *memOutbox) MarkSent(ctx context.Context, ids []int64, sentAt time.Time) error {
+	for _, id := range ids {
+		r.find(id).SentAt = &sentAt
+	}
+	return nil
+}
+
+func (r *memOutbox) MarkFailed(ctx context.Context, ids []int64, reason string) error {
+	for _, id := range ids {
+		e := r.find(id)
+		e.Attempts++
+		e.LastError = reason
+	}
+	return nil
+}
+
+func (r *memOutbox) Park(ctx context.Context, id int64, reason string, failedAt time.Time) error {
+	e := r.find(id)
+	e.Attempts++
+	e.LastError = reason
+	e.FailedAt = &failedAt
+	return nil
+}
+
+func (r *memOutbox) DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+// stubKafka fails any write that includes a message whose key is listed in
+// reject
+type stubKafka struct {
+	reject map[string]error
+	writes int
+}
+
+func (k *stubKafka) PublishMessages(ctx context.Context, messages []kafka.Message) error {
+	k.writes++
+	for _, m := range messages {
+		if err := k.reject[m.Key]; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newRelay(repo *memOutbox, reject map[string]error) (*outboxService, *stubKafka) {
+	k := &stubKafka{reject: reject}
+	return &outboxService{repo: repo, kafka: k, maxAttempts: 3}, k
+}
+
+func sentKeys(repo *memOutbox) []string {
+	var keys []string
+	for _, e := range repo.events {
+		if e.SentAt != nil {
+			keys = append(keys, e.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestRelaySendsBatch(t *testing.T) {
+	repo := &memOutbox{}
+	for _, key := range []string{"a", "b", "a", "c"} {
+		repo.add(key, 0)
+	}
+	s, k := newRelay(repo, nil)
+
+	result, err := s.Relay(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+	if result.Read != 4 || result.Sent != 4 || len(result.Parked) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if k.writes != 1 {
+		t.Errorf("%d writes, want the batch in one", k.writes)
+	}
+	if got := sentKeys(repo); len(got) != 4 {
+		t.Errorf("sent %v", got)
+	}
+
+	result, err = s.Relay(context.Background(), 10)
+	if err != nil || result.Read != 0 {
+		t.Errorf("second Relay = %+v, %v; want nothing left", result, err)
+	}
+}
+
+func TestRelayKafkaUnavailable(t *testing.T) {
+	for name, cause := range map[string]error{
+		"network":   &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+		"timeout":   context.DeadlineExceeded,
+		"retriable": kafkago.WriteErrors{kafkago.LeaderNotAvailable, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &memOutbox{}
+			repo.add("a", 0)
+			repo.add("b", 2)
+			s, k := newRelay(repo, map[string]error{"a": cause, "b": cause})
+
+			result, err := s.Relay(context.Background(), 10)
+			if err == nil || !kafka.Unavailable(err) {
+				t.Fatalf("err = %v, want %v", err, cause)
+			}
+			if result.Sent != 0 || len(result.Parked) != 0 {
+				t.Errorf("result = %+v", result)
+			}
+			// An outage doesn't single out events, however often it fails them
+			if k.writes != 1 {
+				t.Errorf("%d writes, want just the batch", k.writes)
+			}
+			for _, e := range repo.events {
+				if e.FailedAt != nil {
+					t.Errorf("event %s parked during an outage", e.Key)
+				}
+			}
+			if a, b := repo.find(1).Attempts, repo.find(2).Attempts; a != 1 || b != 3 {
+				t.Errorf("attempts = %d, %d; want 1, 3", a, b)
+			}
+		})
+	}
+}
+
+// One event Kafka will never take holds back only the events behind it
+// with the same key
+func TestRelayIsolatesFailingEvent(t *testing.T) {
+	repo := &memOutbox{}
+	repo.add("a", 0)
+	repo.add("b", 0)
+	heldBack := repo.add("poison", 0)
+	repo.add("a", 0)
+	behind := repo.add("poison", 0)
+	repo.add("c", 0)
+
+	tooLarge := kafkago.MessageTooLargeError{}
+	s, _ := newRelay(repo, map[string]error{"poison": tooLarge})
+
+	result, err := s.Relay(context.Background(), 10)
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("err = %v, want the publish error", err)
+	}
+	if result.Read != 6 || result.Sent != 4 || len(result.Parked) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if got, want := sentKeys(repo), []string{"a", "a", "b", "c"}; !equalStrings(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+	if heldBack.Attempts != 1 || heldBack.LastError == "" || heldBack.FailedAt != nil {
+		t.Errorf("failing event = %+v", heldBack)
+	}
+	// Never tried, so nothing counts against it
+	if behind.Attempts != 0 || behind.SentAt != nil {
+		t.Errorf("event behind it = %+v", behind)
+	}
+}
+
+func TestRelayParksAfterMaxAttempts(t *testing.T) {
+	repo := &memOutbox{}
+	poison := repo.add("poison", 0)
+	behind := repo.add("poison", 0)
+	repo.add("a", 0)
+
+	s, k := newRelay(repo, map[string]error{"poison": errors.New("kafka: invalid message")})
+
+	for round := 1; round <= 3; round++ {
+		result, err := s.Relay(context.Background(), 10)
+		if err == nil {
+			t.Fatalf("round %d: no error", round)
+		}
+		if round < 3 && len(result.Parked) != 0 {
+			t.Fatalf("round %d parked %+v", round, result.Parked)
+		}
+		if round == 3 {
+			if len(result.Parked) != 1 || result.Parked[0].ID != poison.ID || result.Parked[0].Attempts != 3 {
+				t.Fatalf("round 3 parked %+v", result.Parked)
+			}
+		}
+	}
+	if poison.FailedAt == nil || poison.SentAt != nil || poison.Attempts != 3 {
+		t.Errorf("parked event = %+v", poison)
+	}
+
+	// The key's later events were tried in their own right once it was parked
+	k.reject = nil
+	result, err := s.Relay(context.Background(), 10)
+	if err != nil || result.Read != 1 || result.Sent != 1 {
+		t.Fatalf("Relay after parking = %+v, %v", result, err)
+	}
+	if behind.SentAt == nil {
+		t.Error("event behind the parked one was not sent")
+	}
+	if poison.SentAt != nil {
+		t.Error("parked event was sent")
+	}
+}
+
+// An outage that starts while events are sent one by one stops the round
+func TestRelayStopsWhenKafkaGoesDown(t *testing.T) {
+	repo := &memOutbox{}
+	repo.add("poison", 0)
+	repo.add("a", 0)
+	repo.add("down", 0)
+	last := repo.add("b", 2)
+
+	s, _ := newRelay(repo, map[string]error{
+		"poison": errors.New("kafka: invalid message"),
+		"down":   &net.OpError{Op: "write", Err: errors.New("broken pipe")},
+	})
+
+	result, err := s.Relay(context.Background(), 10)
+	if err == nil {
+		t.Fatal("no error")
+	}
+	if result.Sent != 1 || sentKeys(repo)[0] != "a" {
+		t.Errorf("result = %+v, sent %v", result, sentKeys(repo))
+	}
+	if last.Attempts != 2 || last.SentAt != nil || last.FailedAt != nil {
+		t.Errorf("event after the outage = %+v", last)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func openTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: is a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestEnqueueEvent(t *testing.T) {
+	db := openTestDB(t, &model.OutboxEvent{})
+	tenantID := uuid.New()
+	ctx := tenant.NewContext(context.Background(), tenantID)
+	repo := repository.NewArticleRepository(db)
+
+	err := repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		return enqueueEvent(ctx, tx, kafka.TopicTags, kafka.EventTagCreated, "tag-1", map[string]string{"slug": "go"})
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction: %v", err)
+	}
+
+	var events []model.OutboxEvent
+	if err := db.Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("%d events, want 1", len(events))
+	}
+	e := events[0]
+	if e.Topic != kafka.TopicTags || e.EventType != kafka.EventTagCreated || e.Key != "tag-1" {
+		t.Errorf("event = %+v", e)
+	}
+	if e.TenantID == nil || *e.TenantID != tenantID {
+		t.Errorf("TenantID = %v, want %v", e.TenantID, tenantID)
+	}
+	if e.SentAt != nil || e.FailedAt != nil || e.Attempts != 0 {
+		t.Errorf("new event isn't pending: %+v", e)
+	}
+
+	var envelope struct {
+		EventID   string            `json:"event_id"`
+		EventType string            `json:"event_type"`
+		TenantID  string            `json:"tenant_id"`
+		Data      map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(e.Payload), &envelope); err != nil {
+		t.Fatalf("payload %q: %v", e.Payload, err)
+	}
+	if envelope.EventID != e.EventID.String() || envelope.EventType != kafka.EventTagCreated ||
+		envelope.TenantID != tenantID.String() || envelope.Data["slug"] != "go" {
+		t.Errorf("payload = %+v", envelope)
+	}
+}
+
+// An event is only recorded if the change it describes commits
+func TestEnqueueEventRollsBack(t *testing.T) {
+	db := openTestDB(t, &model.OutboxEvent{})
+	repo := repository.NewArticleRepository(db)
+	ctx := context.Background()
+
+	failed := errors.New("update failed")
+	err := repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := enqueueEvent(ctx, tx, kafka.TopicTags, kafka.EventTagDeleted, "tag-1", nil); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("err = %v", err)
+	}
+
+	var count int64
+	db.Model(&model.OutboxEvent{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d events left after rollback", count)
+	}
+
+	// Events raised for no tenant carry none
+	if err := repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		return enqueueEvent(ctx, tx, kafka.TopicTags, kafka.EventTagDeleted, "tag-1", nil)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var e model.OutboxEvent
+	if err := db.First(&e).Error; err != nil {
+		t.Fatal(err)
+	}
+	if e.TenantID != nil {
+		t.Errorf("TenantID = %v, want nil", e.TenantID)
+	}
+}
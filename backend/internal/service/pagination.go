@@ -0,0 +1,72 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorToken is the JSON inside an encoded cursor
+type cursorToken struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+	Before    bool      `json:"b,omitempty"`
+}
+
+func encodeCursor(cursor model.Cursor) string {
+	data, _ := json.Marshal(cursorToken(cursor))
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor from a client; an empty one means the first
+// page and decodes to nil
+func decodeCursor(s string) (*model.Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil || token.ID == uuid.Nil || token.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	cursor := model.Cursor(token)
+	return &cursor, nil
+}
+
+// cursorPage works out the cursors either side of items, a page read next to
+// cursor; more reports whether the read stopped short of the end
+func cursorPage[T any](items []T, position func(T) (time.Time, uuid.UUID), cursor *model.Cursor, more bool, limit int) *model.CursorPage {
+	page := &model.CursorPage{Limit: limit}
+	if len(items) == 0 {
+		return page
+	}
+
+	// Reading backwards means the reader came from the next page, and
+	// reading forwards from a cursor that they came from the previous one
+	backward := cursor != nil && cursor.Before
+	if more && !backward || backward {
+		createdAt, id := position(items[len(items)-1])
+		page.NextCursor = encodeCursor(model.Cursor{CreatedAt: createdAt, ID: id})
+	}
+	if more && backward || cursor != nil && !backward {
+		createdAt, id := position(items[0])
+		page.PrevCursor = encodeCursor(model.Cursor{CreatedAt: createdAt, ID: id, Before: true})
+	}
+	return page
+}
+
+// cursorLimit clamps a requested page size like the page/limit mode does
+func cursorLimit(limit int) int {
+	if limit < 1 || limit > 100 {
+		return 10
+	}
+	return limit
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 9, 14, 30, 15, 123456789, time.UTC)
+	id := uuid.MustParse("8f14e45f-ceea-467f-a8f7-5d6e0b1c2a3b")
+
+	for _, want := range []model.Cursor{
+		{CreatedAt: createdAt, ID: id},
+		{CreatedAt: createdAt, ID: id, Before: true},
+		{CreatedAt: createdAt.In(time.FixedZone("CEST", 2*60*60)), ID: id},
+	} {
+		encoded := encodeCursor(want)
+		got, err := decodeCursor(encoded)
+		if err != nil {
+			t.Fatalf("decodeCursor(%q): %v", encoded, err)
+		}
+		if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID || got.Before != want.Before {
+			t.Errorf("round trip of %+v gave %+v", want, *got)
+		}
+	}
+}
+
+func TestDecodeCursorEmpty(t *testing.T) {
+	got, err := decodeCursor("")
+	if got != nil || err != nil {
+		t.Errorf("decodeCursor(\"\") = %+v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	valid := encodeCursor(model.Cursor{CreatedAt: time.Now(), ID: uuid.New()})
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := map[string]string{
+		"not base64":         "%%%",
+		"standard alphabet":  "+/" + valid,
+		"truncated":          valid[:len(valid)/2],
+		"not JSON":           encode("page=2"),
+		"JSON array":         encode(`["2024-01-01T00:00:00Z"]`),
+		"empty object":       encode(`{}`),
+		"missing id":         encode(`{"t":"2024-01-01T00:00:00Z"}`),
+		"nil id":             encode(`{"t":"2024-01-01T00:00:00Z","id":"00000000-0000-0000-0000-000000000000"}`),
+		"malformed id":       encode(`{"t":"2024-01-01T00:00:00Z","id":"1 OR 1=1"}`),
+		"missing time":       encode(`{"id":"8f14e45f-ceea-467f-a8f7-5d6e0b1c2a3b"}`),
+		"malformed time":     encode(`{"t":"yesterday","id":"8f14e45f-ceea-467f-a8f7-5d6e0b1c2a3b"}`),
+		"wrong type for dir": encode(`{"t":"2024-01-01T00:00:00Z","id":"8f14e45f-ceea-467f-a8f7-5d6e0b1c2a3b","b":"yes"}`),
+	}
+	for name, cursor := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got, err := decodeCursor(cursor); err != ErrInvalidCursor {
+				t.Errorf("decodeCursor(%q) = %+v, %v; want ErrInvalidCursor", cursor, got, err)
+			}
+		})
+	}
+}
+
+func TestCursorLimit(t *testing.T) {
+	for limit, want := range map[int]int{-5: 10, 0: 10, 1: 1, 25: 25, 100: 100, 101: 10} {
+		if got := cursorLimit(limit); got != want {
+			t.Errorf("cursorLimit(%d) = %d, want %d", limit, got, want)
+		}
+	}
+}
@@ -2,41 +2,219 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/kafka"
 	"github.com/portfolio/backend/internal/model"
 	"github.com/portfolio/backend/internal/repository"
+	"gorm.io/gorm"
 )
 
+var ErrInvalidInclude = errors.New("invalid include")
+
+// resumeSections are the sections ?include= may name
+var resumeSections = map[string]bool{
+	model.ResumeExperience:     true,
+	model.ResumeEducation:      true,
+	model.ResumeSkills:         true,
+	model.ResumeCertifications: true,
+	model.ResumeAwards:         true,
+}
+
+// ResumeRepositories holds the repository of each resume section
+type ResumeRepositories struct {
+	Experience     repository.ResumeRepository[model.Experience]
+	Education      repository.ResumeRepository[model.Education]
+	Skills         repository.ResumeRepository[model.Skill]
+	Certifications repository.ResumeRepository[model.Certification]
+	Awards         repository.ResumeRepository[model.Award]
+}
+
 type PortfolioService interface {
-	GetPortfolio(ctx context.Context) (*model.Portfolio, error)
+	// GetPortfolio returns the portfolio with the named resume sections,
+	// its title and bio in the locale when translated into it
+	GetPortfolio(ctx context.Context, include []string, locale string) (*model.Portfolio, error)
 	CreateOrUpdatePortfolio(ctx context.Context, portfolio *model.Portfolio) error
 	UpdatePortfolio(ctx context.Context, portfolio *model.Portfolio) error
 }
 
 type portfolioService struct {
-	repo repository.PortfolioRepository
+	repo            repository.PortfolioRepository
+	resume          ResumeRepositories
+	translationRepo repository.TranslationRepository[model.PortfolioTranslation]
+	cache           *cache.RedisCache
+	defaultLocale   string
+}
+
+func NewPortfolioService(repo repository.PortfolioRepository, resume ResumeRepositories, translationRepo repository.TranslationRepository[model.PortfolioTranslation], cache *cache.RedisCache, defaultLocale string) PortfolioService {
+	return &portfolioService{
+		repo:            repo,
+		resume:          resume,
+		translationRepo: translationRepo,
+		cache:           cache,
+		defaultLocale:   defaultLocale,
+	}
+}
+
+func (s *portfolioService) GetPortfolio(ctx context.Context, include []string, locale string) (*model.Portfolio, error) {
+	portfolio, err := s.get(ctx, include)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cached under portfolio:*, which every portfolio write drops
+	translations, err := cachedTranslations[model.PortfolioTranslation](ctx, s.cache, "portfolio:translations", s.translationRepo, portfolio.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio.Locale = s.defaultLocale
+	portfolio.Alternates = []model.Alternate{{Locale: s.defaultLocale}}
+	for _, t := range translations {
+		portfolio.Alternates = append(portfolio.Alternates, model.Alternate{Locale: t.Locale})
+		if t.Locale != locale {
+			continue
+		}
+		portfolio.Locale = t.Locale
+		// Translations may leave either field to the default locale
+		if t.Title != "" {
+			portfolio.Title = t.Title
+		}
+		if t.Bio != "" {
+			portfolio.Bio = t.Bio
+		}
+	}
+	return portfolio, nil
+}
+
+// get returns the portfolio in the default locale
+func (s *portfolioService) get(ctx context.Context, include []string) (*model.Portfolio, error) {
+	sections, err := parseInclude(include)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try cache first
+	key := "portfolio:" + strings.Join(sections, ",")
+	if cached, err := s.cache.Get(ctx, key); err == nil {
+		var portfolio model.Portfolio
+		if err := json.Unmarshal(cached, &portfolio); err == nil {
+			return &portfolio, nil
+		}
+	}
+
+	portfolio, err := s.repo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachSections(ctx, portfolio, sections); err != nil {
+		return nil, err
+	}
+
+	// Cache result
+	if data, err := json.Marshal(portfolio); err == nil {
+		s.cache.Set(ctx, key, data, 10*time.Minute)
+	}
+
+	return portfolio, nil
+}
+
+// attachSections loads the named resume sections into the portfolio
+func (s *portfolioService) attachSections(ctx context.Context, portfolio *model.Portfolio, sections []string) error {
+	var err error
+	for _, section := range sections {
+		switch section {
+		case model.ResumeExperience:
+			portfolio.Experience, err = listSection(ctx, s.resume.Experience)
+		case model.ResumeEducation:
+			portfolio.Education, err = listSection(ctx, s.resume.Education)
+		case model.ResumeSkills:
+			portfolio.Skills, err = listSection(ctx, s.resume.Skills)
+		case model.ResumeCertifications:
+			portfolio.Certifications, err = listSection(ctx, s.resume.Certifications)
+		case model.ResumeAwards:
+			portfolio.Awards, err = listSection(ctx, s.resume.Awards)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func NewPortfolioService(repo repository.PortfolioRepository) PortfolioService {
-	return &portfolioService{repo: repo}
+func listSection[T any](ctx context.Context, repo repository.ResumeRepository[T]) (*[]T, error) {
+	entries, err := repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &entries, nil
 }
 
-func (s *portfolioService) GetPortfolio(ctx context.Context) (*model.Portfolio, error) {
-	return s.repo.Get(ctx)
+// parseInclude checks the named sections and returns them sorted without
+// repeats, so equal requests share a cache key
+func parseInclude(include []string) ([]string, error) {
+	seen := make(map[string]bool, len(include))
+	sections := make([]string, 0, len(include))
+	for _, section := range include {
+		section = strings.ToLower(strings.TrimSpace(section))
+		if section == "" || seen[section] {
+			continue
+		}
+		if !resumeSections[section] {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidInclude, section)
+		}
+		seen[section] = true
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	return sections, nil
 }
 
 func (s *portfolioService) CreateOrUpdatePortfolio(ctx context.Context, portfolio *model.Portfolio) error {
-	return s.repo.CreateOrUpdate(ctx, portfolio)
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewPortfolioRepository(tx).CreateOrUpdate(ctx, portfolio); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicResume, kafka.EventPortfolioUpdated, portfolio.ID.String(), portfolio)
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidatePortfolio(ctx, s.cache)
+	return nil
 }
 
 func (s *portfolioService) UpdatePortfolio(ctx context.Context, portfolio *model.Portfolio) error {
-	if err := s.repo.Update(ctx, portfolio); err != nil {
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewPortfolioRepository(tx).Update(ctx, portfolio); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicResume, kafka.EventPortfolioUpdated, portfolio.ID.String(), portfolio)
+	})
+	if err != nil {
 		if errors.Is(err, repository.ErrPortfolioNotFound) {
 			// If not found, create it
-			return s.repo.CreateOrUpdate(ctx, portfolio)
+			return s.CreateOrUpdatePortfolio(ctx, portfolio)
 		}
 		return err
 	}
+
+	invalidatePortfolio(ctx, s.cache)
 	return nil
 }
 
+// invalidatePortfolio drops the cached portfolio and everything rendered
+// from it, including the feeds, which name its owner as their author, and
+// the sitemap, which dates the portfolio page by its last update
+func invalidatePortfolio(ctx context.Context, cache *cache.RedisCache) {
+	cache.DeletePattern(ctx, "portfolio:*")
+	cache.DeletePattern(ctx, "resume:*")
+	cache.DeletePattern(ctx, "articles:feed:*")
+	cache.DeletePattern(ctx, "articles:sitemap:*")
+}
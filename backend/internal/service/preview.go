@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+)
+
+var (
+	ErrInvalidPreviewToken = errors.New("invalid preview token")
+	ErrPreviewTokenExpired = errors.New("preview token expired or revoked")
+	ErrInvalidPreviewTTL   = errors.New("invalid preview token lifetime")
+)
+
+type PreviewService interface {
+	CreatePreviewToken(ctx context.Context, articleID string, createdBy uuid.UUID, ttl time.Duration) (*model.PreviewToken, string, error)
+	ListPreviewTokens(ctx context.Context, articleID string) ([]model.PreviewToken, error)
+	RevokePreviewToken(ctx context.Context, articleID, tokenID string) error
+	GetPreview(ctx context.Context, token string) (*model.Article, *model.PreviewToken, error)
+}
+
+type previewService struct {
+	repo        repository.PreviewTokenRepository
+	articleRepo repository.ArticleRepository
+	secret      []byte
+	defaultTTL  time.Duration
+	maxTTL      time.Duration
+}
+
+func NewPreviewService(repo repository.PreviewTokenRepository, articleRepo repository.ArticleRepository, secret string, defaultTTL, maxTTL time.Duration) PreviewService {
+	return &previewService{
+		repo:        repo,
+		articleRepo: articleRepo,
+		secret:      []byte(secret),
+		defaultTTL:  defaultTTL,
+		maxTTL:      maxTTL,
+	}
+}
+
+// CreatePreviewToken mints a signed token for the article. The returned
+// string is the only copy of the token; just its ID is persisted.
+func (s *previewService) CreatePreviewToken(ctx context.Context, articleID string, createdBy uuid.UUID, ttl time.Duration) (*model.PreviewToken, string, error) {
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+	if ttl < 0 || ttl > s.maxTTL {
+		return nil, "", ErrInvalidPreviewTTL
+	}
+
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &model.PreviewToken{
+		ID:        uuid.New(),
+		ArticleID: article.ID,
+		CreatedBy: createdBy,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, s.sign(token.ID, token.ExpiresAt), nil
+}
+
+func (s *previewService) ListPreviewTokens(ctx context.Context, articleID string) ([]model.PreviewToken, error) {
+	if _, err := s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByArticle(ctx, articleID)
+}
+
+// RevokePreviewToken revokes a token of an article in the request's tenant.
+// Tokens of other tenants' articles are reported as not found.
+func (s *previewService) RevokePreviewToken(ctx context.Context, articleID, tokenID string) error {
+	if _, err := s.articleRepo.GetByID(ctx, articleID); err != nil {
+		if errors.Is(err, repository.ErrArticleNotFound) {
+			return repository.ErrPreviewTokenNotFound
+		}
+		return err
+	}
+	return s.repo.Revoke(ctx, articleID, tokenID)
+}
+
+// GetPreview resolves a token to its article regardless of publication state.
+// Forged tokens are rejected before touching the database.
+func (s *previewService) GetPreview(ctx context.Context, token string) (*model.Article, *model.PreviewToken, error) {
+	id, expiresAt, err := s.verify(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	if !now.Before(expiresAt) {
+		return nil, nil, ErrPreviewTokenExpired
+	}
+
+	stored, err := s.repo.GetByID(ctx, id.String())
+	if err != nil {
+		if errors.Is(err, repository.ErrPreviewTokenNotFound) {
+			return nil, nil, ErrInvalidPreviewToken
+		}
+		return nil, nil, err
+	}
+	if !stored.Active(now) {
+		return nil, nil, ErrPreviewTokenExpired
+	}
+
+	article, err := s.articleRepo.GetByID(ctx, stored.ArticleID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return article, stored, nil
+}
+
+// sign encodes the token ID and expiry and appends an HMAC-SHA256 signature:
+// base64url(id || expiry) "." base64url(mac)
+func (s *previewService) sign(id uuid.UUID, expiresAt time.Time) string {
+	payload := make([]byte, 0, 24)
+	payload = append(payload, id[:]...)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(expiresAt.Unix()))
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded))
+}
+
+func (s *previewService) verify(token string) (uuid.UUID, time.Time, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, time.Time{}, ErrInvalidPreviewToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(encoded)) {
+		return uuid.Nil, time.Time{}, ErrInvalidPreviewToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(payload) != 24 {
+		return uuid.Nil, time.Time{}, ErrInvalidPreviewToken
+	}
+
+	id, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.Nil, time.Time{}, ErrInvalidPreviewToken
+	}
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0).UTC()
+	return id, expiresAt, nil
+}
+
+func (s *previewService) mac(payload string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
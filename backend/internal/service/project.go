@@ -2,59 +2,251 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+	"github.com/google/uuid"
 	"github.com/portfolio/backend/internal/cache"
 	"github.com/portfolio/backend/internal/kafka"
 	"github.com/portfolio/backend/internal/model"
 	"github.com/portfolio/backend/internal/repository"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrDuplicateGalleryMedia = errors.New("gallery lists the same media more than once")
+	ErrInvalidProjectSort    = errors.New("invalid sort")
+	ErrSortWithCursor        = errors.New("sort is not supported with cursor pagination")
+	ErrTooManyTechnologies   = errors.New("too many technologies in filter")
+	ErrDuplicateID           = errors.New("the same id is listed more than once")
+)
+
+// projectSortColumns are the columns a project list may be sorted by
+var projectSortColumns = map[string]bool{
+	"name":       true,
+	"created_at": true,
+	"updated_at": true,
+	"featured":   true,
+	"position":   true,
+	"view_count": true,
+	"like_count": true,
+	"clap_count": true,
+}
+
+// Bound on technologies per filter, each of which is a separate condition
+const maxFilterTechnologies = 20
+
+// Reads take the locale to serve, like ArticleService's
 type ProjectService interface {
-	GetProjects(ctx context.Context, page, limit int, featured *bool) ([]model.Project, int64, error)
-	GetProjectByID(ctx context.Context, id string) (*model.Project, error)
+	// GetProjects lists a page of projects; sort is a comma-separated list
+	// of columns, each prefixed with - to sort descending
+	GetProjects(ctx context.Context, page, limit int, filter model.ProjectFilter, sort, locale string) ([]model.Project, int64, error)
+	// GetProjectsByCursor pages through projects by cursor; the total is
+	// only counted when asked for
+	GetProjectsByCursor(ctx context.Context, cursor string, limit int, filter model.ProjectFilter, sort, locale string, withTotal bool) ([]model.Project, *model.CursorPage, error)
+	GetTechnologyFacets(ctx context.Context, filter model.ProjectFilter) ([]model.TechnologyFacet, error)
+	GetProjectByID(ctx context.Context, id, locale string) (*model.Project, error)
 	CreateProject(ctx context.Context, project *model.Project) error
 	UpdateProject(ctx context.Context, id string, project *model.Project) error
 	DeleteProject(ctx context.Context, id string) error
+	SetGallery(ctx context.Context, id string, items []model.ProjectMedia) (*model.Project, error)
+	// ReorderProjects curates the project order: the listed projects come
+	// first, in that order, and the rest follow unordered
+	ReorderProjects(ctx context.Context, ids []uuid.UUID) error
+	// LinkArticles relates the project to exactly the given articles
+	LinkArticles(ctx context.Context, id string, articleIDs []uuid.UUID) (*model.Project, error)
 }
 
 type projectService struct {
-	repo  repository.ProjectRepository
-	kafka *kafka.Producer
-	cache *cache.RedisCache
+	repo            repository.ProjectRepository
+	mediaRepo       repository.MediaRepository
+	translationRepo repository.TranslationRepository[model.ProjectTranslation]
+	cache           *cache.RedisCache
+	defaultLocale   string
 }
 
-func NewProjectService(repo repository.ProjectRepository, kafka *kafka.Producer, cache *cache.RedisCache) ProjectService {
+func NewProjectService(repo repository.ProjectRepository, mediaRepo repository.MediaRepository, translationRepo repository.TranslationRepository[model.ProjectTranslation], cache *cache.RedisCache, defaultLocale string) ProjectService {
 	return &projectService{
-		repo:  repo,
-		kafka: kafka,
-		cache: cache,
+		repo:            repo,
+		mediaRepo:       mediaRepo,
+		translationRepo: translationRepo,
+		cache:           cache,
+		defaultLocale:   defaultLocale,
 	}
 }
 
-func (s *projectService) GetProjects(ctx context.Context, page, limit int, featured *bool) ([]model.Project, int64, error) {
+func (s *projectService) GetProjects(ctx context.Context, page, limit int, filter model.ProjectFilter, sort, locale string) ([]model.Project, int64, error) {
 	if page < 1 {
 		page = 1
 	}
 	if limit < 1 || limit > 100 {
 		limit = 10
 	}
-	return s.repo.List(ctx, page, limit, featured)
+	if len(filter.Technologies) > maxFilterTechnologies {
+		return nil, 0, ErrTooManyTechnologies
+	}
+
+	fields, err := parseProjectSort(sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter.Sort = fields
+
+	projects, total, err := s.repo.List(ctx, page, limit, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.translateList(ctx, projects, locale); err != nil {
+		return nil, 0, err
+	}
+	return projects, total, nil
+}
+
+func (s *projectService) GetProjectsByCursor(ctx context.Context, cursor string, limit int, filter model.ProjectFilter, sort, locale string, withTotal bool) ([]model.Project, *model.CursorPage, error) {
+	// Cursors are positions in the newest-first order only
+	if sort != "" {
+		return nil, nil, ErrSortWithCursor
+	}
+	if len(filter.Technologies) > maxFilterTechnologies {
+		return nil, nil, ErrTooManyTechnologies
+	}
+	position, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	limit = cursorLimit(limit)
+
+	projects, more, err := s.repo.ListByCursor(ctx, position, limit, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := cursorPage(projects, func(p model.Project) (time.Time, uuid.UUID) {
+		return p.CreatedAt, p.ID
+	}, position, more, limit)
+	if withTotal {
+		total, err := s.repo.Count(ctx, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		page.Total = &total
+	}
+	if err := s.translateList(ctx, projects, locale); err != nil {
+		return nil, nil, err
+	}
+	return projects, page, nil
 }
 
-func (s *projectService) GetProjectByID(ctx context.Context, id string) (*model.Project, error) {
-	return s.repo.GetByID(ctx, id)
+func (s *projectService) GetTechnologyFacets(ctx context.Context, filter model.ProjectFilter) ([]model.TechnologyFacet, error) {
+	if len(filter.Technologies) > maxFilterTechnologies {
+		return nil, ErrTooManyTechnologies
+	}
+	return s.repo.TechnologyFacets(ctx, filter)
+}
+
+// parseProjectSort reads a sort such as "-updated_at,name", checking every
+// column against the whitelist
+func parseProjectSort(sort string) ([]model.SortField, error) {
+	if sort == "" {
+		return nil, nil
+	}
+
+	var fields []model.SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		field := model.SortField{Column: strings.TrimPrefix(part, "-")}
+		field.Desc = field.Column != part
+		if !projectSortColumns[field.Column] || seen[field.Column] {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidProjectSort, part)
+		}
+		seen[field.Column] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (s *projectService) GetProjectByID(ctx context.Context, id, locale string) (*model.Project, error) {
+	project, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cached under projects:*, which every project write drops
+	key := fmt.Sprintf("projects:translations:%s", project.ID)
+	translations, err := cachedTranslations[model.ProjectTranslation](ctx, s.cache, key, s.translationRepo, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	project.Locale = s.defaultLocale
+	project.Alternates = []model.Alternate{{Locale: s.defaultLocale}}
+	for i, t := range translations {
+		project.Alternates = append(project.Alternates, model.Alternate{Locale: t.Locale})
+		if t.Locale == locale {
+			translateProject(project, &translations[i])
+		}
+	}
+	return project, nil
+}
+
+// translateList overlays the locale's translations on a page of projects
+func (s *projectService) translateList(ctx context.Context, projects []model.Project, locale string) error {
+	for i := range projects {
+		projects[i].Locale = s.defaultLocale
+	}
+	if locale == "" || locale == s.defaultLocale || len(projects) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(projects))
+	for i, project := range projects {
+		ids[i] = project.ID
+	}
+	translations, err := s.translationRepo.ListByOwners(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byProject := make(map[uuid.UUID]*model.ProjectTranslation)
+	for i, t := range translations {
+		if t.Locale == locale {
+			byProject[t.ProjectID] = &translations[i]
+		}
+	}
+	for i := range projects {
+		if t, ok := byProject[projects[i].ID]; ok {
+			translateProject(&projects[i], t)
+		}
+	}
+	return nil
 }
 
 func (s *projectService) CreateProject(ctx context.Context, project *model.Project) error {
-	if err := s.repo.Create(ctx, project); err != nil {
+	// New projects join the unordered tail until the next reorder, are
+	// linked to articles separately and get GitHub metadata from the syncer
+	project.Position = nil
+	project.Articles = nil
+	project.Github = nil
+	if err := s.resolveCoverImage(ctx, project); err != nil {
 		return err
 	}
 
-	// Publish Kafka event
-	s.kafka.PublishProjectCreated(ctx, project)
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewProjectRepository(tx).Create(ctx, project); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicProjects, kafka.EventProjectCreated, project.ID.String(), project)
+	})
+	if err != nil {
+		return err
+	}
 
 	// Invalidate cache
 	s.cache.DeletePattern(ctx, "projects:*")
+	s.cache.DeletePattern(ctx, "articles:sitemap:*")
+	s.cache.DeletePattern(ctx, "resume:*")
 
 	return nil
 }
@@ -66,32 +258,183 @@ func (s *projectService) UpdateProject(ctx context.Context, id string, project *
 	}
 
 	project.ID = existing.ID
-	if err := s.repo.Update(ctx, project); err != nil {
+	project.Position = existing.Position
+	if err := s.resolveCoverImage(ctx, project); err != nil {
 		return err
 	}
+	err = s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		repo := repository.NewProjectRepository(tx)
+		if err := repo.Update(ctx, project); err != nil {
+			return err
+		}
+		project.Gallery = existing.Gallery
 
-	// Publish Kafka event
-	s.kafka.PublishProjectUpdated(ctx, project)
+		// Metadata synced from another repository no longer applies
+		project.Github = existing.Github
+		if project.GithubURL != existing.GithubURL {
+			project.Github = nil
+			if err := repo.DeleteGithub(ctx, project.ID); err != nil {
+				return err
+			}
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicProjects, kafka.EventProjectUpdated, id, project)
+	})
+	if err != nil {
+		return err
+	}
 
 	// Invalidate cache
 	s.cache.DeletePattern(ctx, "projects:*")
+	s.cache.DeletePattern(ctx, "articles:sitemap:*")
+	s.cache.DeletePattern(ctx, "resume:*")
 	s.cache.Delete(ctx, fmt.Sprintf("project:detail:%s", id))
 
 	return nil
 }
 
 func (s *projectService) DeleteProject(ctx context.Context, id string) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewProjectRepository(tx).Delete(ctx, id); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicProjects, kafka.EventProjectDeleted, id, map[string]string{"id": id})
+	})
+	if err != nil {
 		return err
 	}
 
-	// Publish Kafka event
-	s.kafka.PublishProjectDeleted(ctx, id)
+	// Invalidate cache
+	s.cache.DeletePattern(ctx, "projects:*")
+	s.cache.DeletePattern(ctx, "articles:sitemap:*")
+	s.cache.DeletePattern(ctx, "resume:*")
+	s.cache.Delete(ctx, fmt.Sprintf("project:detail:%s", id))
+
+	return nil
+}
+
+// SetGallery replaces the project's gallery with the given media, in order
+func (s *projectService) SetGallery(ctx context.Context, id string, items []model.ProjectMedia) (*model.Project, error) {
+	project, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		ids[i] = item.MediaID.String()
+		if seen[ids[i]] {
+			return nil, ErrDuplicateGalleryMedia
+		}
+		seen[ids[i]] = true
+	}
+	if _, err := s.mediaRepo.GetByIDs(ctx, ids); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		repo := repository.NewProjectRepository(tx)
+		if err := repo.ReplaceGallery(ctx, project.ID, items); err != nil {
+			return err
+		}
+
+		project, err = repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicProjects, kafka.EventProjectUpdated, id, project)
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// Invalidate cache
 	s.cache.DeletePattern(ctx, "projects:*")
 	s.cache.Delete(ctx, fmt.Sprintf("project:detail:%s", id))
 
+	return project, nil
+}
+
+func (s *projectService) ReorderProjects(ctx context.Context, ids []uuid.UUID) error {
+	if err := checkUniqueIDs(ids); err != nil {
+		return err
+	}
+
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewProjectRepository(tx).Reorder(ctx, ids); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicProjects, kafka.EventProjectsReordered, "", map[string][]uuid.UUID{"ids": ids})
+	})
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache (lists, details and the resume carry positions)
+	s.cache.DeletePattern(ctx, "projects:*")
+	s.cache.DeletePattern(ctx, "project:detail:*")
+	s.cache.DeletePattern(ctx, "resume:*")
+
+	return nil
+}
+
+func (s *projectService) LinkArticles(ctx context.Context, id string, articleIDs []uuid.UUID) (*model.Project, error) {
+	project, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUniqueIDs(articleIDs); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		repo := repository.NewProjectRepository(tx)
+		if err := repo.ReplaceArticles(ctx, project.ID, articleIDs); err != nil {
+			return err
+		}
+
+		project, err = repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicProjects, kafka.EventProjectUpdated, id, project)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache (article details list their projects, and related
+	// suggestions follow project technologies)
+	s.cache.DeletePattern(ctx, "projects:*")
+	s.cache.Delete(ctx, fmt.Sprintf("project:detail:%s", id))
+	s.cache.InvalidateArticles(ctx)
+	s.cache.DeletePattern(ctx, "article:detail:*")
+
+	return project, nil
+}
+
+// checkUniqueIDs rejects a list of ids that repeats one
+func checkUniqueIDs(ids []uuid.UUID) error {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return ErrDuplicateID
+		}
+		seen[id] = true
+	}
 	return nil
 }
 
+// resolveCoverImage checks the cover exists and attaches it for the response
+func (s *projectService) resolveCoverImage(ctx context.Context, project *model.Project) error {
+	if project.CoverImageID == nil {
+		project.CoverImage = nil
+		return nil
+	}
+	media, err := s.mediaRepo.GetByID(ctx, project.CoverImageID.String())
+	if err != nil {
+		return err // ErrMediaNotFound for unknown ids
+	}
+	project.CoverImage = media
+	return nil
+}
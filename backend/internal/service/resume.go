@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrResumeFieldRequired = errors.New("missing required field")
+	ErrInvalidDateRange    = errors.New("end date is before start date")
+	ErrInvalidSkillLevel   = errors.New("level must be beginner, intermediate, advanced or expert")
+)
+
+var skillLevels = map[string]bool{
+	model.SkillLevelBeginner:     true,
+	model.SkillLevelIntermediate: true,
+	model.SkillLevelAdvanced:     true,
+	model.SkillLevelExpert:       true,
+}
+
+// ResumeSectionService manages the entries of one resume section. Every
+// write publishes a resume.updated event and invalidates the cached
+// portfolio and resume exports.
+type ResumeSectionService[T any] interface {
+	List(ctx context.Context) ([]T, error)
+	Create(ctx context.Context, entry *T) error
+	// Update replaces the entry's fields and returns it as stored
+	Update(ctx context.Context, id string, entry *T) (*T, error)
+	Delete(ctx context.Context, id string) error
+	// Reorder curates the section's order: the listed entries come first,
+	// in that order, and the rest follow by date
+	Reorder(ctx context.Context, ids []uuid.UUID) error
+}
+
+// resumeEntry lets a section's service reach the common columns of *T
+type resumeEntry[T any] interface {
+	*T
+	model.ResumeEntry
+}
+
+type resumeSectionService[T any, P resumeEntry[T]] struct {
+	section  string // Named in events
+	repo     repository.ResumeRepository[T]
+	cache    *cache.RedisCache
+	validate func(*T) error
+}
+
+func NewExperienceService(repo repository.ResumeRepository[model.Experience], cache *cache.RedisCache) ResumeSectionService[model.Experience] {
+	return &resumeSectionService[model.Experience, *model.Experience]{
+		section:  model.ResumeExperience,
+		repo:     repo,
+		cache:    cache,
+		validate: validateExperience,
+	}
+}
+
+func NewEducationService(repo repository.ResumeRepository[model.Education], cache *cache.RedisCache) ResumeSectionService[model.Education] {
+	return &resumeSectionService[model.Education, *model.Education]{
+		section:  model.ResumeEducation,
+		repo:     repo,
+		cache:    cache,
+		validate: validateEducation,
+	}
+}
+
+func NewSkillService(repo repository.ResumeRepository[model.Skill], cache *cache.RedisCache) ResumeSectionService[model.Skill] {
+	return &resumeSectionService[model.Skill, *model.Skill]{
+		section:  model.ResumeSkills,
+		repo:     repo,
+		cache:    cache,
+		validate: validateSkill,
+	}
+}
+
+func NewCertificationService(repo repository.ResumeRepository[model.Certification], cache *cache.RedisCache) ResumeSectionService[model.Certification] {
+	return &resumeSectionService[model.Certification, *model.Certification]{
+		section:  model.ResumeCertifications,
+		repo:     repo,
+		cache:    cache,
+		validate: validateCertification,
+	}
+}
+
+func NewAwardService(repo repository.ResumeRepository[model.Award], cache *cache.RedisCache) ResumeSectionService[model.Award] {
+	return &resumeSectionService[model.Award, *model.Award]{
+		section:  model.ResumeAwards,
+		repo:     repo,
+		cache:    cache,
+		validate: validateAward,
+	}
+}
+
+func validateExperience(e *model.Experience) error {
+	return requireFields(map[string]bool{"company": filled(e.Company), "role": filled(e.Role), "start_date": !e.StartDate.IsZero()})
+}
+
+func validateEducation(e *model.Education) error {
+	return requireFields(map[string]bool{"institution": filled(e.Institution), "start_date": !e.StartDate.IsZero()})
+}
+
+func validateSkill(s *model.Skill) error {
+	if err := requireFields(map[string]bool{"name": filled(s.Name), "level": filled(s.Level)}); err != nil {
+		return err
+	}
+	s.Level = strings.ToLower(strings.TrimSpace(s.Level))
+	if !skillLevels[s.Level] {
+		return ErrInvalidSkillLevel
+	}
+	return nil
+}
+
+func validateCertification(c *model.Certification) error {
+	return requireFields(map[string]bool{"name": filled(c.Name), "issued_on": !c.IssuedOn.IsZero()})
+}
+
+func validateAward(a *model.Award) error {
+	return requireFields(map[string]bool{"title": filled(a.Title), "awarded_on": !a.AwardedOn.IsZero()})
+}
+
+func (s *resumeSectionService[T, P]) List(ctx context.Context) ([]T, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *resumeSectionService[T, P]) Create(ctx context.Context, entry *T) error {
+	if err := s.check(entry); err != nil {
+		return err
+	}
+
+	// New entries join the unordered tail until the next reorder
+	item := P(entry).Item()
+	item.ID = uuid.Nil
+	item.Position = nil
+
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.repo.WithTx(tx).Create(ctx, entry); err != nil {
+			return err
+		}
+		return s.enqueueChange(ctx, tx, item.ID.String())
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidatePortfolio(ctx, s.cache)
+	return nil
+}
+
+func (s *resumeSectionService[T, P]) Update(ctx context.Context, id string, entry *T) (*T, error) {
+	if err := s.check(entry); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.repo.WithTx(tx).Update(ctx, id, entry); err != nil {
+			return err
+		}
+		return s.enqueueChange(ctx, tx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidatePortfolio(ctx, s.cache)
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *resumeSectionService[T, P]) Delete(ctx context.Context, id string) error {
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.repo.WithTx(tx).Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.enqueueChange(ctx, tx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidatePortfolio(ctx, s.cache)
+	return nil
+}
+
+func (s *resumeSectionService[T, P]) Reorder(ctx context.Context, ids []uuid.UUID) error {
+	if err := checkUniqueIDs(ids); err != nil {
+		return err
+	}
+
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.repo.WithTx(tx).Reorder(ctx, ids); err != nil {
+			return err
+		}
+		return s.enqueueChange(ctx, tx, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidatePortfolio(ctx, s.cache)
+	return nil
+}
+
+// enqueueChange records a resume.updated event for the section, naming the
+// entry changed; a reorder names none
+func (s *resumeSectionService[T, P]) enqueueChange(ctx context.Context, tx *gorm.DB, id string) error {
+	data := map[string]string{"section": s.section}
+	if id != "" {
+		data["id"] = id
+	}
+	return enqueueEvent(ctx, tx, kafka.TopicResume, kafka.EventResumeUpdated, s.section, data)
+}
+
+// check validates the section's own fields, then the date range
+func (s *resumeSectionService[T, P]) check(entry *T) error {
+	return checkResumeEntry[T, P](entry, s.validate)
+}
+
+func checkResumeEntry[T any, P resumeEntry[T]](entry *T, validate func(*T) error) error {
+	if err := validate(entry); err != nil {
+		return err
+	}
+	start, end := P(entry).Period()
+	if start != nil && end != nil && end.Before(*start) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// requireFields names every field that isn't set, given whether each is
+func requireFields(fields map[string]bool) error {
+	var missing []string
+	for name, set := range fields {
+		if !set {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("%w: %s", ErrResumeFieldRequired, strings.Join(missing, ", "))
+}
+
+func filled(s string) bool {
+	return strings.TrimSpace(s) != ""
+}
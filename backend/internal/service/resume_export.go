@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/pkg/resume"
+)
+
+var ErrUnknownTheme = errors.New("unknown resume theme")
+
+// Most featured projects a resume lists
+const resumeProjectLimit = 20
+
+// Exports are dropped by every portfolio, resume and project write, so the
+// TTL only bounds how long an unused theme stays in Redis
+const resumeCacheTTL = 24 * time.Hour
+
+// RenderedResume is an encoded resume plus the validators for conditional GETs
+type RenderedResume struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+type ResumeExportService interface {
+	// GetJSONResume returns the resume in the JSON Resume schema
+	GetJSONResume(ctx context.Context) (*RenderedResume, error)
+	// GetPDF returns the resume laid out by the named theme, or by the
+	// default theme if theme is empty
+	GetPDF(ctx context.Context, theme string) (*RenderedResume, error)
+	// Themes lists the theme names GetPDF accepts
+	Themes() []string
+}
+
+type resumeExportService struct {
+	portfolio    PortfolioService
+	projectRepo  repository.ProjectRepository
+	cache        *cache.RedisCache
+	tenants      TenantService
+	defaultTheme string
+}
+
+func NewResumeExportService(portfolio PortfolioService, projectRepo repository.ProjectRepository, cache *cache.RedisCache, tenants TenantService, defaultTheme string) ResumeExportService {
+	return &resumeExportService{
+		portfolio:    portfolio,
+		projectRepo:  projectRepo,
+		cache:        cache,
+		tenants:      tenants,
+		defaultTheme: defaultTheme,
+	}
+}
+
+func (s *resumeExportService) Themes() []string {
+	names := make([]string, 0, len(resume.Themes))
+	for name := range resume.Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *resumeExportService) GetJSONResume(ctx context.Context) (*RenderedResume, error) {
+	return s.rendered(ctx, "resume:json", func(r *resume.Resume) ([]byte, string, error) {
+		body, err := r.Encode()
+		return body, "application/json; charset=utf-8", err
+	})
+}
+
+func (s *resumeExportService) GetPDF(ctx context.Context, theme string) (*RenderedResume, error) {
+	if theme == "" {
+		theme = s.defaultTheme
+	}
+	t, ok := resume.Themes[strings.ToLower(theme)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTheme, theme)
+	}
+
+	return s.rendered(ctx, "resume:pdf:"+t.Name, func(r *resume.Resume) ([]byte, string, error) {
+		body, err := resume.RenderPDF(r, t)
+		return body, resume.ContentTypePDF, err
+	})
+}
+
+// rendered returns the cached export under key, building and encoding the
+// resume on a miss
+func (s *resumeExportService) rendered(ctx context.Context, key string, encode func(*resume.Resume) ([]byte, string, error)) (*RenderedResume, error) {
+	if cached, err := s.cache.Get(ctx, key); err == nil {
+		var rendered RenderedResume
+		if err := json.Unmarshal(cached, &rendered); err == nil {
+			return &rendered, nil
+		}
+	}
+
+	r, modified, err := s.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, contentType, err := encode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	rendered := &RenderedResume{
+		Body:         body,
+		ContentType:  contentType,
+		ETag:         fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16])),
+		LastModified: modified,
+	}
+
+	if data, err := json.Marshal(rendered); err == nil {
+		s.cache.Set(ctx, key, data, resumeCacheTTL)
+	}
+
+	return rendered, nil
+}
+
+// build assembles the resume from the portfolio, its resume sections and the
+// featured projects. It also returns when any of them last changed.
+func (s *resumeExportService) build(ctx context.Context) (*resume.Resume, time.Time, error) {
+	portfolio, err := s.portfolio.GetPortfolio(ctx, []string{
+		model.ResumeExperience, model.ResumeEducation, model.ResumeSkills, model.ResumeCertifications, model.ResumeAwards,
+	}, "")
+	if err != nil {
+		return nil, time.Time{}, err // ErrPortfolioNotFound until one is saved
+	}
+	urls, err := s.tenants.URLs(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	featured := true
+	projects, _, err := s.projectRepo.List(ctx, 1, resumeProjectLimit, model.ProjectFilter{
+		Featured: &featured,
+		Sort:     []model.SortField{{Column: "position"}, {Column: "created_at", Desc: true}},
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	modified := portfolio.UpdatedAt
+	touch := func(t time.Time) {
+		if t.After(modified) {
+			modified = t
+		}
+	}
+
+	r := &resume.Resume{
+		Schema: resume.SchemaURL,
+		Basics: s.basics(portfolio, urls.Site),
+	}
+
+	for _, e := range deref(portfolio.Experience) {
+		touch(e.UpdatedAt)
+		r.Work = append(r.Work, resume.Work{
+			Name:       e.Company,
+			Position:   e.Role,
+			Location:   e.Location,
+			URL:        e.URL,
+			StartDate:  resumeDate(&e.StartDate),
+			EndDate:    resumeDate(e.EndDate),
+			Summary:    e.Description,
+			Highlights: e.Highlights,
+		})
+	}
+	for _, e := range deref(portfolio.Education) {
+		touch(e.UpdatedAt)
+		r.Education = append(r.Education, resume.Education{
+			Institution: e.Institution,
+			URL:         e.URL,
+			Area:        e.Field,
+			StudyType:   e.Degree,
+			StartDate:   resumeDate(&e.StartDate),
+			EndDate:     resumeDate(e.EndDate),
+			Score:       e.Grade,
+		})
+	}
+	for _, skill := range deref(portfolio.Skills) {
+		touch(skill.UpdatedAt)
+		entry := resume.Skill{Name: skill.Name, Level: titleCase(skill.Level)}
+		if skill.Category != "" {
+			entry.Keywords = []string{skill.Category}
+		}
+		r.Skills = append(r.Skills, entry)
+	}
+	for _, c := range deref(portfolio.Certifications) {
+		touch(c.UpdatedAt)
+		r.Certificates = append(r.Certificates, resume.Certificate{
+			Name:   c.Name,
+			Date:   resumeDate(&c.IssuedOn),
+			Issuer: c.Issuer,
+			URL:    c.URL,
+		})
+	}
+	for _, a := range deref(portfolio.Awards) {
+		touch(a.UpdatedAt)
+		r.Awards = append(r.Awards, resume.Award{
+			Title:   a.Title,
+			Date:    resumeDate(&a.AwardedOn),
+			Awarder: a.Issuer,
+			Summary: a.Description,
+		})
+	}
+	for _, project := range projects {
+		touch(project.UpdatedAt)
+		link := project.LiveURL
+		if link == "" {
+			link = project.GithubURL
+		}
+		r.Projects = append(r.Projects, resume.Project{
+			Name:        project.Name,
+			Description: project.Description,
+			Keywords:    project.Technologies,
+			URL:         link,
+		})
+	}
+
+	// HTTP dates have second precision
+	modified = modified.UTC().Truncate(time.Second)
+	r.Meta = &resume.Meta{
+		Canonical:    urls.API + "/api/v1/portfolio/resume.json",
+		Version:      resume.SchemaVersion,
+		LastModified: modified.Format(time.RFC3339),
+	}
+	return r, modified, nil
+}
+
+// basics maps the portfolio's profile. Social links are a JSON object of
+// network to URL; "phone", "website" and "url" fill the matching fields.
+func (s *resumeExportService) basics(portfolio *model.Portfolio, siteURL string) resume.Basics {
+	basics := resume.Basics{
+		Name:    portfolio.Name,
+		Label:   portfolio.Title,
+		Email:   portfolio.Email,
+		URL:     siteURL,
+		Summary: portfolio.Bio,
+	}
+
+	links := map[string]string{}
+	if len(portfolio.SocialLinks) > 0 {
+		json.Unmarshal(portfolio.SocialLinks, &links)
+	}
+	networks := make([]string, 0, len(links))
+	for network := range links {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+
+	for _, network := range networks {
+		link := strings.TrimSpace(links[network])
+		if link == "" {
+			continue
+		}
+		switch strings.ToLower(network) {
+		case "phone":
+			basics.Phone = link
+		case "website", "url":
+			basics.URL = link
+		case "email":
+			if basics.Email == "" {
+				basics.Email = strings.TrimPrefix(link, "mailto:")
+			}
+		default:
+			basics.Profiles = append(basics.Profiles, resume.Profile{
+				Network:  titleCase(network),
+				Username: profileUsername(link),
+				URL:      link,
+			})
+		}
+	}
+	return basics
+}
+
+// profileUsername takes the last path segment of a profile URL, so
+// "https://github.com/jane" gives "jane"; a bare handle is kept as is
+func profileUsername(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return strings.TrimPrefix(link, "@")
+	}
+	name := path.Base(strings.TrimSuffix(u.Path, "/"))
+	if name == "/" || name == "." {
+		return ""
+	}
+	return strings.TrimPrefix(name, "@")
+}
+
+func resumeDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(resume.DateFormat)
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func deref[T any](entries *[]T) []T {
+	if entries == nil {
+		return nil
+	}
+	return *entries
+}
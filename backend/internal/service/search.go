@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+)
+
+var (
+	ErrEmptySearchQuery  = errors.New("search query is required")
+	ErrInvalidSearchType = errors.New("invalid search type")
+)
+
+// Longest query we pass to Postgres; anything beyond is noise for ranking
+const maxSearchQueryLength = 200
+
+type SearchService interface {
+	Search(ctx context.Context, query repository.SearchQuery) (*model.SearchResult, error)
+}
+
+type searchService struct {
+	repo repository.SearchRepository
+}
+
+func NewSearchService(repo repository.SearchRepository) SearchService {
+	return &searchService{repo: repo}
+}
+
+func (s *searchService) Search(ctx context.Context, query repository.SearchQuery) (*model.SearchResult, error) {
+	query.Query = strings.TrimSpace(query.Query)
+	if query.Query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	if runes := []rune(query.Query); len(runes) > maxSearchQueryLength {
+		query.Query = string(runes[:maxSearchQueryLength])
+	}
+
+	for _, t := range query.Types {
+		if t != model.SearchTypeArticle && t != model.SearchTypeProject {
+			return nil, ErrInvalidSearchType
+		}
+	}
+
+	return s.repo.Search(ctx, query)
+}
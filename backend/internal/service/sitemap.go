@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/pkg/sitemap"
+)
+
+var ErrSitemapPageNotFound = errors.New("sitemap page not found")
+
+type SitemapService interface {
+	// GetSitemap returns the single sitemap, or an index of numbered pages
+	// once the site outgrows one file
+	GetSitemap(ctx context.Context) ([]byte, error)
+	GetSitemapPage(ctx context.Context, page int) ([]byte, error)
+	GetRobots(ctx context.Context) (string, error)
+}
+
+type sitemapService struct {
+	repo          repository.SitemapRepository
+	portfolioRepo repository.PortfolioRepository
+	cache         *cache.RedisCache
+	tenants       TenantService
+	defaultLocale string
+	perPage       int
+}
+
+func NewSitemapService(repo repository.SitemapRepository, portfolioRepo repository.PortfolioRepository, cache *cache.RedisCache, tenants TenantService, defaultLocale string) SitemapService {
+	return &sitemapService{
+		repo:          repo,
+		portfolioRepo: portfolioRepo,
+		cache:         cache,
+		tenants:       tenants,
+		defaultLocale: defaultLocale,
+		perPage:       sitemap.MaxURLs,
+	}
+}
+
+func (s *sitemapService) GetSitemap(ctx context.Context) ([]byte, error) {
+	return s.cached(ctx, "articles:sitemap:index", func() ([]byte, error) {
+		urls, err := s.tenants.URLs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		total, err := s.total(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if total <= s.perPage {
+			return s.page(ctx, urls.Site, 1, total)
+		}
+
+		pages := (total + s.perPage - 1) / s.perPage
+		sitemaps := make([]sitemap.URL, pages)
+		for i := range sitemaps {
+			sitemaps[i] = sitemap.URL{Loc: fmt.Sprintf("%s/sitemaps/%d.xml", urls.API, i+1)}
+		}
+		return sitemap.EncodeIndex(sitemaps)
+	})
+}
+
+func (s *sitemapService) GetSitemapPage(ctx context.Context, page int) ([]byte, error) {
+	return s.cached(ctx, fmt.Sprintf("articles:sitemap:%d", page), func() ([]byte, error) {
+		urls, err := s.tenants.URLs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		total, err := s.total(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if page < 1 || (page-1)*s.perPage >= total {
+			return nil, ErrSitemapPageNotFound
+		}
+		return s.page(ctx, urls.Site, page, total)
+	})
+}
+
+func (s *sitemapService) GetRobots(ctx context.Context) (string, error) {
+	urls, err := s.tenants.URLs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	b.WriteString("Allow: /\n")
+	b.WriteString("Disallow: /api/v1/admin/\n")
+	b.WriteString("Disallow: /api/v1/preview/\n")
+	fmt.Fprintf(&b, "\nSitemap: %s/sitemap.xml\n", urls.API)
+	return b.String(), nil
+}
+
+// total counts sitemap URLs: the portfolio home page plus every entry
+func (s *sitemapService) total(ctx context.Context) (int, error) {
+	count, err := s.repo.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int(count) + 1, nil
+}
+
+// page renders URLs [(page-1)*perPage, page*perPage); the home page is the
+// first URL of page 1, so database offsets are shifted by one
+func (s *sitemapService) page(ctx context.Context, siteURL string, page, total int) ([]byte, error) {
+	start := (page - 1) * s.perPage
+	end := start + s.perPage
+	if end > total {
+		end = total
+	}
+
+	var urls []sitemap.URL
+	offset, limit := start-1, end-start
+	if start == 0 {
+		home, err := s.home(ctx, siteURL)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, home)
+		offset, limit = 0, end-1
+	}
+
+	entries, err := s.repo.List(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		urls = append(urls, sitemap.URL{
+			Loc:        s.location(siteURL, entry.Type, entry.Locale, entry.Key),
+			LastMod:    entry.UpdatedAt,
+			Alternates: s.alternates(siteURL, entry),
+		})
+	}
+
+	return sitemap.EncodeURLSet(urls)
+}
+
+func (s *sitemapService) home(ctx context.Context, siteURL string) (sitemap.URL, error) {
+	home := sitemap.URL{Loc: siteURL + "/"}
+	portfolio, err := s.portfolioRepo.Get(ctx)
+	if err != nil && !errors.Is(err, repository.ErrPortfolioNotFound) {
+		return home, err
+	}
+	if portfolio != nil {
+		home.LastMod = portfolio.UpdatedAt
+	}
+	return home, nil
+}
+
+// location is the page's URL in the locale, "" being the default
+func (s *sitemapService) location(siteURL, entryType, locale, key string) string {
+	switch entryType {
+	case model.SitemapTypeProject:
+		return localizedURL(siteURL, locale, s.defaultLocale, "/projects/"+url.PathEscape(key))
+	default:
+		return localizedURL(siteURL, locale, s.defaultLocale, "/articles/"+url.PathEscape(key))
+	}
+}
+
+// alternates links a translated page to itself in every locale, the default
+// page standing in for locales it has no translation into
+func (s *sitemapService) alternates(siteURL string, entry model.SitemapEntry) []sitemap.Alternate {
+	if len(entry.Translations) == 0 {
+		return nil
+	}
+
+	defaultLoc := s.location(siteURL, entry.Type, "", entry.DefaultKey)
+	alternates := []sitemap.Alternate{
+		{Language: s.defaultLocale, Loc: defaultLoc},
+		{Language: sitemap.XDefault, Loc: defaultLoc},
+	}
+	for _, t := range entry.Translations {
+		alternates = append(alternates, sitemap.Alternate{Language: t.Locale, Loc: s.location(siteURL, entry.Type, t.Locale, t.Key)})
+	}
+	return alternates
+}
+
+// cached serves a rendered file from Redis. Keys live under articles:* so
+// article writes drop them; project writes clear articles:sitemap:* too.
+func (s *sitemapService) cached(ctx context.Context, key string, build func() ([]byte, error)) ([]byte, error) {
+	if data, err := s.cache.Get(ctx, key); err == nil {
+		return data, nil
+	}
+
+	data, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Set(ctx, key, data, time.Hour)
+	return data, nil
+}
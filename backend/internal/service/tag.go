@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTagSlugExists  = errors.New("tag slug already exists")
+	ErrInvalidTagKind = errors.New("invalid tag kind")
+	ErrInvalidTagSlug = errors.New("tag slug must contain letters or digits")
+)
+
+type TagService interface {
+	GetTags(ctx context.Context, kind string) ([]model.Tag, error)
+	GetTagBySlug(ctx context.Context, slug string) (*model.Tag, error)
+	CreateTag(ctx context.Context, tag *model.Tag) error
+	UpdateTag(ctx context.Context, id string, tag *model.Tag) error
+	DeleteTag(ctx context.Context, id string) error
+}
+
+type tagService struct {
+	repo  repository.TagRepository
+	cache *cache.RedisCache
+}
+
+func NewTagService(repo repository.TagRepository, cache *cache.RedisCache) TagService {
+	return &tagService{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+func (s *tagService) GetTags(ctx context.Context, kind string) ([]model.Tag, error) {
+	// Article counts change with every article write, so the list lives under
+	// articles:* and is dropped by InvalidateArticles
+	key := fmt.Sprintf("articles:tags:%s", kind)
+	if cached, err := s.cache.Get(ctx, key); err == nil {
+		var tags []model.Tag
+		if err := json.Unmarshal(cached, &tags); err == nil {
+			return tags, nil
+		}
+	}
+
+	tags, err := s.repo.List(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(tags); err == nil {
+		s.cache.Set(ctx, key, data, 10*time.Minute)
+	}
+
+	return tags, nil
+}
+
+func (s *tagService) GetTagBySlug(ctx context.Context, slug string) (*model.Tag, error) {
+	return s.repo.GetBySlug(ctx, slug)
+}
+
+func (s *tagService) CreateTag(ctx context.Context, tag *model.Tag) error {
+	if err := s.normalize(tag); err != nil {
+		return err
+	}
+	if existing, err := s.repo.GetBySlug(ctx, tag.Slug); err == nil && existing != nil {
+		return ErrTagSlugExists
+	}
+
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewTagRepository(tx).Create(ctx, tag); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicTags, kafka.EventTagCreated, tag.ID.String(), tag)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	s.cache.InvalidateArticles(ctx)
+
+	return nil
+}
+
+func (s *tagService) UpdateTag(ctx context.Context, id string, tag *model.Tag) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err // Repository already returns ErrTagNotFound
+	}
+
+	tag.ID = existing.ID
+	if err := s.normalize(tag); err != nil {
+		return err
+	}
+	if other, err := s.repo.GetBySlug(ctx, tag.Slug); err == nil && other.ID != existing.ID {
+		return ErrTagSlugExists
+	}
+
+	err = s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewTagRepository(tx).Update(ctx, tag); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicTags, kafka.EventTagUpdated, id, tag)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache (article details embed their tags)
+	s.cache.InvalidateArticles(ctx)
+	s.cache.DeletePattern(ctx, "article:detail:*")
+
+	return nil
+}
+
+func (s *tagService) DeleteTag(ctx context.Context, id string) error {
+	err := s.repo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := repository.NewTagRepository(tx).Delete(ctx, id); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, kafka.TopicTags, kafka.EventTagDeleted, id, map[string]string{"id": id})
+	})
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache (article details embed their tags)
+	s.cache.InvalidateArticles(ctx)
+	s.cache.DeletePattern(ctx, "article:detail:*")
+
+	return nil
+}
+
+// normalize fills in the slug and kind defaults and validates the kind
+func (s *tagService) normalize(tag *model.Tag) error {
+	if tag.Slug == "" {
+		tag.Slug = tag.Name
+	}
+	tag.Slug = slugify(tag.Slug)
+	if tag.Slug == "" {
+		return ErrInvalidTagSlug
+	}
+
+	if tag.Kind == "" {
+		tag.Kind = model.TagKindTag
+	}
+	if tag.Kind != model.TagKindTag && tag.Kind != model.TagKindCategory {
+		return ErrInvalidTagKind
+	}
+	return nil
+}
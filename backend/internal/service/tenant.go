@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/internal/tenant"
+)
+
+var (
+	ErrTenantSlugExists  = errors.New("tenant slug already exists")
+	ErrInvalidTenantSlug = errors.New("tenant slug must be 1-63 lower-case letters, digits or inner hyphens")
+	ErrTenantHostTaken   = errors.New("host already belongs to another tenant")
+)
+
+// Tenants change rarely and are looked up on every request
+const tenantCacheTTL = 5 * time.Minute
+
+var tenantSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// SiteURLs are the absolute URLs a tenant's links are built from
+type SiteURLs struct {
+	Site string // Public website, where articles are read
+	API  string // This API as the tenant reaches it
+}
+
+type TenantService interface {
+	GetBySlug(ctx context.Context, slug string) (*model.Tenant, error)
+	// GetByHost returns the tenant serving a Host header, port or not
+	GetByHost(ctx context.Context, host string) (*model.Tenant, error)
+	// URLs returns the URLs of the tenant ctx is scoped to. What a tenant
+	// doesn't set comes from SITE_URL and API_URL, the API of tenants other
+	// than the fallback one under its path prefix.
+	URLs(ctx context.Context) (SiteURLs, error)
+	CreateTenant(ctx context.Context, t *model.Tenant) error
+}
+
+type tenantService struct {
+	repo       repository.TenantRepository
+	cache      *cache.RedisCache
+	siteURL    string
+	apiURL     string
+	pathPrefix string
+	fallback   string
+}
+
+func NewTenantService(repo repository.TenantRepository, cache *cache.RedisCache, siteURL, apiURL, pathPrefix, fallback string) TenantService {
+	return &tenantService{
+		repo:       repo,
+		cache:      cache,
+		siteURL:    siteURL,
+		apiURL:     apiURL,
+		pathPrefix: pathPrefix,
+		fallback:   fallback,
+	}
+}
+
+func (s *tenantService) GetBySlug(ctx context.Context, slug string) (*model.Tenant, error) {
+	return s.cached(ctx, "tenants:slug:"+slug, func() (*model.Tenant, error) {
+		return s.repo.GetBySlug(ctx, slug)
+	})
+}
+
+func (s *tenantService) GetByHost(ctx context.Context, host string) (*model.Tenant, error) {
+	host = normalizeHost(host)
+	return s.cached(ctx, "tenants:host:"+host, func() (*model.Tenant, error) {
+		return s.repo.GetByHost(ctx, host)
+	})
+}
+
+func (s *tenantService) URLs(ctx context.Context) (SiteURLs, error) {
+	urls := SiteURLs{Site: s.siteURL, API: s.apiURL}
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return urls, nil
+	}
+
+	t, err := s.cached(ctx, fmt.Sprintf("tenants:id:%s", id), func() (*model.Tenant, error) {
+		return s.repo.GetByID(ctx, id)
+	})
+	if err != nil {
+		return urls, err
+	}
+	if t.SiteURL != "" {
+		urls.Site = t.SiteURL
+	}
+	switch {
+	case t.APIURL != "":
+		urls.API = t.APIURL
+	case t.Slug != s.fallback && s.pathPrefix != "":
+		urls.API = s.apiURL + s.pathPrefix + "/" + t.Slug
+	}
+	return urls, nil
+}
+
+func (s *tenantService) CreateTenant(ctx context.Context, t *model.Tenant) error {
+	t.Slug = strings.TrimSpace(t.Slug)
+	if !tenantSlugPattern.MatchString(t.Slug) {
+		return ErrInvalidTenantSlug
+	}
+	if existing, err := s.repo.GetBySlug(ctx, t.Slug); err == nil && existing != nil {
+		return ErrTenantSlugExists
+	}
+
+	hosts := make(model.StringArray, 0, len(t.Hosts))
+	for _, host := range t.Hosts {
+		host = normalizeHost(host)
+		if host == "" {
+			continue
+		}
+		if _, err := s.repo.GetByHost(ctx, host); err == nil {
+			return fmt.Errorf("%w: %s", ErrTenantHostTaken, host)
+		}
+		hosts = append(hosts, host)
+	}
+	t.Hosts = hosts
+	t.SiteURL = strings.TrimSuffix(strings.TrimSpace(t.SiteURL), "/")
+	t.APIURL = strings.TrimSuffix(strings.TrimSpace(t.APIURL), "/")
+
+	return s.repo.Create(ctx, t)
+}
+
+// cached looks a tenant up through Redis. Misses aren't cached, so a tenant
+// is found as soon as it is created.
+func (s *tenantService) cached(ctx context.Context, key string, load func() (*model.Tenant, error)) (*model.Tenant, error) {
+	if data, err := s.cache.Get(ctx, key); err == nil {
+		var t model.Tenant
+		if err := json.Unmarshal(data, &t); err == nil {
+			return &t, nil
+		}
+	}
+
+	t, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(t); err == nil {
+		s.cache.Set(ctx, key, data, tenantCacheTTL)
+	}
+	return t, nil
+}
+
+// normalizeHost lower-cases a host name and drops any port
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/markdown"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/repository"
+	"github.com/portfolio/backend/pkg/locale"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnsupportedLocale     = errors.New("locale is not supported")
+	ErrDefaultLocale         = errors.New("the default locale is edited on the content itself, not as a translation")
+	ErrTranslationSlugExists = errors.New("slug is already used in this locale")
+	ErrTranslationIncomplete = errors.New("translation is incomplete")
+)
+
+// TranslationRepositories holds the repository of each translated kind
+type TranslationRepositories struct {
+	Articles  repository.ArticleTranslationRepository
+	Projects  repository.TranslationRepository[model.ProjectTranslation]
+	Portfolio repository.TranslationRepository[model.PortfolioTranslation]
+}
+
+// TranslationService manages the translations of articles, projects and
+// the portfolio into the supported locales other than the default. Saving
+// a translation replaces the one for that locale.
+type TranslationService interface {
+	ListArticleTranslations(ctx context.Context, articleID string) ([]model.ArticleTranslation, error)
+	SaveArticleTranslation(ctx context.Context, articleID, locale string, translation *model.ArticleTranslation) error
+	DeleteArticleTranslation(ctx context.Context, articleID, locale string) error
+	ListProjectTranslations(ctx context.Context, projectID string) ([]model.ProjectTranslation, error)
+	SaveProjectTranslation(ctx context.Context, projectID, locale string, translation *model.ProjectTranslation) error
+	DeleteProjectTranslation(ctx context.Context, projectID, locale string) error
+	ListPortfolioTranslations(ctx context.Context) ([]model.PortfolioTranslation, error)
+	SavePortfolioTranslation(ctx context.Context, locale string, translation *model.PortfolioTranslation) error
+	DeletePortfolioTranslation(ctx context.Context, locale string) error
+}
+
+type translationService struct {
+	repos         TranslationRepositories
+	articleRepo   repository.ArticleRepository
+	projectRepo   repository.ProjectRepository
+	portfolioRepo repository.PortfolioRepository
+	renderer      *markdown.Renderer
+	cache         *cache.RedisCache
+	defaultLocale string
+	locales       []string
+}
+
+func NewTranslationService(repos TranslationRepositories, articleRepo repository.ArticleRepository, projectRepo repository.ProjectRepository, portfolioRepo repository.PortfolioRepository, renderer *markdown.Renderer, cache *cache.RedisCache, defaultLocale string, locales []string) TranslationService {
+	return &translationService{
+		repos:         repos,
+		articleRepo:   articleRepo,
+		projectRepo:   projectRepo,
+		portfolioRepo: portfolioRepo,
+		renderer:      renderer,
+		cache:         cache,
+		defaultLocale: defaultLocale,
+		locales:       locales,
+	}
+}
+
+// checkLocale canonicalizes a locale from the URL and checks it can hold
+// a translation
+func (s *translationService) checkLocale(tag string) (string, error) {
+	canonical := locale.Canonical(tag)
+	if canonical == s.defaultLocale {
+		return "", ErrDefaultLocale
+	}
+	for _, supported := range s.locales {
+		if canonical != "" && supported == canonical {
+			return canonical, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q; supported: %s", ErrUnsupportedLocale, tag, strings.Join(s.locales, ", "))
+}
+
+func (s *translationService) ListArticleTranslations(ctx context.Context, articleID string) ([]model.ArticleTranslation, error) {
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repos.Articles.List(ctx, article.ID)
+}
+
+func (s *translationService) SaveArticleTranslation(ctx context.Context, articleID, tag string, translation *model.ArticleTranslation) error {
+	loc, err := s.checkLocale(tag)
+	if err != nil {
+		return err
+	}
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return err
+	}
+
+	translation.Title = strings.TrimSpace(translation.Title)
+	if translation.Title == "" || strings.TrimSpace(translation.Content) == "" {
+		return fmt.Errorf("%w: title and content are required", ErrTranslationIncomplete)
+	}
+	translation.Slug = slugify(translation.Slug)
+	if translation.Slug == "" {
+		translation.Slug = slugify(translation.Title)
+	}
+	if other, err := s.repos.Articles.GetBySlug(ctx, loc, translation.Slug); err == nil && other.ArticleID != article.ID {
+		return ErrTranslationSlugExists
+	}
+
+	// Rendered like the article itself, excerpt included
+	result, err := s.renderer.Render(translation.Content)
+	if err != nil {
+		return err
+	}
+	translation.ContentHTML = result.HTML
+	translation.TOC = make(model.TableOfContents, len(result.TOC))
+	for i, entry := range result.TOC {
+		translation.TOC[i] = model.TOCEntry{Level: entry.Level, Text: entry.Text, ID: entry.ID}
+	}
+	translation.WordCount = result.WordCount
+	translation.ReadingTimeMinutes = result.ReadingTimeMinutes
+	if translation.Excerpt == "" {
+		translation.Excerpt = result.Excerpt
+	}
+
+	translation.ArticleID = article.ID
+	translation.Locale = loc
+	err = s.saveTranslation(ctx, kafka.TopicArticles, article.ID.String(), translation, func(tx *gorm.DB) error {
+		repo := s.repos.Articles.WithTx(tx)
+		if existing, err := repo.Get(ctx, article.ID, loc); err == nil {
+			translation.ID, translation.CreatedAt = existing.ID, existing.CreatedAt
+		} else if !errors.Is(err, repository.ErrTranslationNotFound) {
+			return err
+		}
+		return repo.Save(ctx, translation)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Feeds, the sitemap and the translation lists live under articles:*
+	s.cache.InvalidateArticles(ctx)
+	return nil
+}
+
+func (s *translationService) DeleteArticleTranslation(ctx context.Context, articleID, tag string) error {
+	loc, err := s.checkLocale(tag)
+	if err != nil {
+		return err
+	}
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return err
+	}
+
+	err = s.deleteTranslation(ctx, kafka.TopicArticles, article.ID, loc, func(tx *gorm.DB) error {
+		return s.repos.Articles.WithTx(tx).Delete(ctx, article.ID, loc)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cache.InvalidateArticles(ctx)
+	return nil
+}
+
+func (s *translationService) ListProjectTranslations(ctx context.Context, projectID string) ([]model.ProjectTranslation, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repos.Projects.List(ctx, project.ID)
+}
+
+func (s *translationService) SaveProjectTranslation(ctx context.Context, projectID, tag string, translation *model.ProjectTranslation) error {
+	loc, err := s.checkLocale(tag)
+	if err != nil {
+		return err
+	}
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	translation.Name = strings.TrimSpace(translation.Name)
+	if translation.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrTranslationIncomplete)
+	}
+
+	translation.ProjectID = project.ID
+	translation.Locale = loc
+	err = s.saveTranslation(ctx, kafka.TopicProjects, project.ID.String(), translation, func(tx *gorm.DB) error {
+		repo := s.repos.Projects.WithTx(tx)
+		if existing, err := repo.Get(ctx, project.ID, loc); err == nil {
+			translation.ID, translation.CreatedAt = existing.ID, existing.CreatedAt
+		} else if !errors.Is(err, repository.ErrTranslationNotFound) {
+			return err
+		}
+		return repo.Save(ctx, translation)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateProject(ctx)
+	return nil
+}
+
+func (s *translationService) DeleteProjectTranslation(ctx context.Context, projectID, tag string) error {
+	loc, err := s.checkLocale(tag)
+	if err != nil {
+		return err
+	}
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	err = s.deleteTranslation(ctx, kafka.TopicProjects, project.ID, loc, func(tx *gorm.DB) error {
+		return s.repos.Projects.WithTx(tx).Delete(ctx, project.ID, loc)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateProject(ctx)
+	return nil
+}
+
+// invalidateProject drops the project lists, details and translation lists,
+// and the sitemap that links every translated project page
+func (s *translationService) invalidateProject(ctx context.Context) {
+	s.cache.DeletePattern(ctx, "projects:*")
+	s.cache.DeletePattern(ctx, "project:detail:*")
+	s.cache.DeletePattern(ctx, "articles:sitemap:*")
+}
+
+func (s *translationService) ListPortfolioTranslations(ctx context.Context) ([]model.PortfolioTranslation, error) {
+	portfolio, err := s.portfolioRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repos.Portfolio.List(ctx, portfolio.ID)
+}
+
+func (s *translationService) SavePortfolioTranslation(ctx context.Context, tag string, translation *model.PortfolioTranslation) error {
+	loc, err := s.checkLocale(tag)
+	if err != nil {
+		return err
+	}
+	portfolio, err := s.portfolioRepo.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	translation.PortfolioID = portfolio.ID
+	translation.Locale = loc
+	err = s.saveTranslation(ctx, kafka.TopicResume, portfolio.ID.String(), translation, func(tx *gorm.DB) error {
+		repo := s.repos.Portfolio.WithTx(tx)
+		if existing, err := repo.Get(ctx, portfolio.ID, loc); err == nil {
+			translation.ID, translation.CreatedAt = existing.ID, existing.CreatedAt
+		} else if !errors.Is(err, repository.ErrTranslationNotFound) {
+			return err
+		}
+		return repo.Save(ctx, translation)
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidatePortfolio(ctx, s.cache)
+	return nil
+}
+
+func (s *translationService) DeletePortfolioTranslation(ctx context.Context, tag string) error {
+	loc, err := s.checkLocale(tag)
+	if err != nil {
+		return err
+	}
+	portfolio, err := s.portfolioRepo.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.deleteTranslation(ctx, kafka.TopicResume, portfolio.ID, loc, func(tx *gorm.DB) error {
+		return s.repos.Portfolio.WithTx(tx).Delete(ctx, portfolio.ID, loc)
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidatePortfolio(ctx, s.cache)
+	return nil
+}
+
+// saveTranslation writes a translation and its translation.updated event
+// together
+func (s *translationService) saveTranslation(ctx context.Context, topic, key string, translation interface{}, write func(tx *gorm.DB) error) error {
+	return s.articleRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, topic, kafka.EventTranslationUpdated, key, translation)
+	})
+}
+
+// deleteTranslation removes a translation and records a translation.deleted
+// event naming its owner and locale
+func (s *translationService) deleteTranslation(ctx context.Context, topic string, ownerID uuid.UUID, loc string, remove func(tx *gorm.DB) error) error {
+	return s.articleRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := remove(tx); err != nil {
+			return err
+		}
+		return enqueueEvent(ctx, tx, topic, kafka.EventTranslationDeleted, ownerID.String(), map[string]string{
+			"id":     ownerID.String(),
+			"locale": loc,
+		})
+	})
+}
+
+// cachedTranslations returns every translation of an owner, cached under
+// key until the next write that drops it
+func cachedTranslations[T any](ctx context.Context, c *cache.RedisCache, key string, repo repository.TranslationRepository[T], ownerID uuid.UUID) ([]T, error) {
+	if cached, err := c.Get(ctx, key); err == nil {
+		var translations []T
+		if err := json.Unmarshal(cached, &translations); err == nil {
+			return translations, nil
+		}
+	}
+
+	translations, err := repo.List(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(translations); err == nil {
+		c.Set(ctx, key, data, time.Hour)
+	}
+	return translations, nil
+}
+
+// translateArticle overlays a translation on the article. Lists leave out
+// the body, so it is only replaced where the article has one.
+func translateArticle(article *model.Article, t *model.ArticleTranslation) {
+	article.Locale = t.Locale
+	article.Title = t.Title
+	article.Slug = t.Slug
+	article.Excerpt = t.Excerpt
+	article.WordCount = t.WordCount
+	article.ReadingTimeMinutes = t.ReadingTimeMinutes
+	if article.Content != "" {
+		article.Content = t.Content
+		article.ContentHTML = t.ContentHTML
+		article.TOC = t.TOC
+	}
+}
+
+// translateArticles sets the locale of each article and overlays its
+// translation into loc where there is one; the rest stay in defaultLocale
+func translateArticles(ctx context.Context, repo repository.ArticleTranslationRepository, articles []model.Article, loc, defaultLocale string) error {
+	for i := range articles {
+		articles[i].Locale = defaultLocale
+	}
+	if loc == "" || loc == defaultLocale || len(articles) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	translations, err := repo.ListByOwners(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byArticle := make(map[uuid.UUID]*model.ArticleTranslation)
+	for i, t := range translations {
+		if t.Locale == loc {
+			byArticle[t.ArticleID] = &translations[i]
+		}
+	}
+	for i := range articles {
+		if t, ok := byArticle[articles[i].ID]; ok {
+			translateArticle(&articles[i], t)
+		}
+	}
+	return nil
+}
+
+func translateProject(project *model.Project, t *model.ProjectTranslation) {
+	project.Locale = t.Locale
+	project.Name = t.Name
+	project.Description = t.Description
+}
+
+// localizedURL is the address of a page of the site in a locale. Pages in
+// the default locale keep their plain path; others are under /<locale>.
+func localizedURL(siteURL, loc, defaultLocale, path string) string {
+	if loc == "" || loc == defaultLocale {
+		return siteURL + path
+	}
+	return siteURL + "/" + loc + path
+}
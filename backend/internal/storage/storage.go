@@ -0,0 +1,20 @@
+// Package storage abstracts where uploaded media bytes are kept.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage is implemented by every media backend. Keys are slash-separated
+// relative paths such as "2024/05/<uuid>.png".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL is the public address clients download the object from
+	URL(key string) string
+}
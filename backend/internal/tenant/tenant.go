@@ -0,0 +1,23 @@
+// Package tenant carries the tenant a request or event belongs to on its
+// context. Repositories, the cache and the Kafka producer read it to scope
+// what they touch; code running for no tenant, such as the background
+// workers, sees every tenant.
+package tenant
+
+import (
+	"context"
+	"github.com/google/uuid"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx scoped to the tenant
+func NewContext(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ctx is scoped to, if any
+func FromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(contextKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/kafka"
+	"github.com/portfolio/backend/internal/model"
+	"github.com/portfolio/backend/internal/service"
+	"go.uber.org/zap"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+const (
+	analyticsGroupID = "backend-analytics"
+	// A batch closes at this many events or after this long, whichever is first
+	analyticsBatchSize   = 500
+	analyticsBatchWindow = 5 * time.Second
+	// Retry delays while the database is unavailable
+	minRollupRetry = time.Second
+	maxRollupRetry = 30 * time.Second
+)
+
+// AnalyticsConsumer reads page view events and folds them into the daily
+// rollups. Offsets are committed only once a batch is written, so a crash
+// replays the batch and event ids keep it from being counted twice.
+type AnalyticsConsumer struct {
+	service service.AnalyticsService
+	reader  *kafkago.Reader
+	logger  *zap.Logger
+}
+
+func NewAnalyticsConsumer(service service.AnalyticsService, brokers []string, logger *zap.Logger) *AnalyticsConsumer {
+	return &AnalyticsConsumer{
+		service: service,
+		reader: kafkago.NewReader(kafkago.ReaderConfig{
+			Brokers:  brokers,
+			GroupID:  analyticsGroupID,
+			Topic:    kafka.TopicAnalytics,
+			MaxBytes: 10 << 20,
+		}),
+		logger: logger,
+	}
+}
+
+// Run blocks until ctx is cancelled and the current batch is written
+func (a *AnalyticsConsumer) Run(ctx context.Context) {
+	a.logger.Info("Analytics consumer started", zap.String("topic", kafka.TopicAnalytics))
+	defer func() {
+		if err := a.reader.Close(); err != nil {
+			a.logger.Error("Failed to close analytics reader", zap.Error(err))
+		}
+		a.logger.Info("Analytics consumer stopped")
+	}()
+
+	for ctx.Err() == nil {
+		messages, err := a.fetchBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			a.logger.Error("Failed to fetch analytics events", zap.Error(err))
+			time.Sleep(time.Second)
+		}
+		if len(messages) == 0 {
+			continue
+		}
+		a.process(messages)
+	}
+}
+
+// fetchBatch waits for one message, then collects more until the batch is
+// full or the window closes
+func (a *AnalyticsConsumer) fetchBatch(ctx context.Context) ([]kafkago.Message, error) {
+	first, err := a.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	messages := []kafkago.Message{first}
+
+	windowCtx, cancel := context.WithTimeout(ctx, analyticsBatchWindow)
+	defer cancel()
+	for len(messages) < analyticsBatchSize {
+		msg, err := a.reader.FetchMessage(windowCtx)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+				break
+			}
+			return messages, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// process writes a batch and commits it. It runs to completion during
+// shutdown, retrying while the database is down, because the batch's
+// events are already marked as counted.
+func (a *AnalyticsConsumer) process(messages []kafkago.Message) {
+	ctx := context.Background()
+
+	views := make([]model.PageView, 0, len(messages))
+	for _, msg := range messages {
+		var event struct {
+			EventType string         `json:"event_type"`
+			TenantID  uuid.UUID      `json:"tenant_id"`
+			Data      model.PageView `json:"data"`
+		}
+		if err := json.Unmarshal(msg.Value, &event); err != nil || event.EventType != kafka.EventPageView || event.Data.ID == "" || event.TenantID == uuid.Nil {
+			a.logger.Warn("Skipping malformed analytics event", zap.Int64("offset", msg.Offset), zap.Error(err))
+			continue
+		}
+		event.Data.TenantID = event.TenantID
+		views = append(views, event.Data)
+	}
+
+	var rollup *model.AnalyticsRollup
+	a.retry("aggregate page views", func() error {
+		var err error
+		rollup, err = a.service.Aggregate(ctx, views)
+		return err
+	})
+	a.retry("save analytics rollup", func() error {
+		return a.service.SaveRollup(ctx, rollup)
+	})
+	a.retry("commit analytics offsets", func() error {
+		return a.reader.CommitMessages(ctx, messages...)
+	})
+}
+
+func (a *AnalyticsConsumer) retry(action string, fn func() error) {
+	delay := minRollupRetry
+	for {
+		err := fn()
+		if err == nil {
+			return
+		}
+		a.logger.Error("Failed to "+action+", retrying", zap.Duration("delay", delay), zap.Error(err))
+		time.Sleep(delay)
+		if delay *= 2; delay > maxRollupRetry {
+			delay = maxRollupRetry
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"time"
+	"github.com/portfolio/backend/internal/service"
+	"go.uber.org/zap"
+)
+
+// Bound on the last flush made while shutting down
+const finalFlushTimeout = 10 * time.Second
+
+// CounterFlusher moves buffered view and reaction counts from Redis into
+// Postgres. Draining the buffer is atomic, so every replica can run one.
+type CounterFlusher struct {
+	service  service.CounterService
+	logger   *zap.Logger
+	interval time.Duration
+}
+
+func NewCounterFlusher(service service.CounterService, logger *zap.Logger, interval time.Duration) *CounterFlusher {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &CounterFlusher{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Run flushes every interval until ctx is cancelled, then once more so
+// counts buffered before shutdown aren't left waiting for another replica
+func (f *CounterFlusher) Run(ctx context.Context) {
+	f.logger.Info("Counter flusher started", zap.Duration("interval", f.interval))
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+			f.flush(flushCtx)
+			cancel()
+			f.logger.Info("Counter flusher stopped")
+			return
+		case <-ticker.C:
+			f.flush(ctx)
+		}
+	}
+}
+
+func (f *CounterFlusher) flush(ctx context.Context) {
+	updated, err := f.service.Flush(ctx)
+	if err != nil {
+		f.logger.Error("Failed to flush counters", zap.Error(err))
+		return
+	}
+	if updated > 0 {
+		f.logger.Debug("Flushed counters", zap.Int("updated", updated))
+	}
+}
@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/github"
+	"github.com/portfolio/backend/internal/service"
+	"go.uber.org/zap"
+)
+
+const (
+	githubSyncLockKey = "github-sync"
+
+	// A pass stops well before its lock expires
+	githubSyncLockTTL = 15 * time.Minute
+	githubPassLimit   = 10 * time.Minute
+)
+
+// GithubSyncer refreshes project metadata from GitHub. Every replica runs
+// one, but a Redis lock lets only one of them sync at a time so they don't
+// share out the rate limit. When the limit runs low the syncer waits for
+// it to reset.
+type GithubSyncer struct {
+	service  service.GithubSyncService
+	cache    *cache.RedisCache
+	logger   *zap.Logger
+	interval time.Duration
+	instance string
+}
+
+func NewGithubSyncer(service service.GithubSyncService, cache *cache.RedisCache, logger *zap.Logger, interval time.Duration) *GithubSyncer {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &GithubSyncer{
+		service:  service,
+		cache:    cache,
+		logger:   logger,
+		interval: interval,
+		instance: uuid.New().String(),
+	}
+}
+
+// Run syncs every interval until ctx is cancelled
+func (s *GithubSyncer) Run(ctx context.Context) {
+	s.logger.Info("GitHub syncer started", zap.Duration("interval", s.interval))
+
+	for {
+		delay := s.interval
+		if reset := s.sync(ctx); reset.After(time.Now().Add(delay)) {
+			delay = time.Until(reset)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.logger.Info("GitHub syncer stopped")
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// sync runs one pass. It returns when the rate limit resets if the pass
+// stopped for it, and the zero time otherwise.
+func (s *GithubSyncer) sync(ctx context.Context) time.Time {
+	acquired, err := s.cache.AcquireLock(ctx, githubSyncLockKey, s.instance, githubSyncLockTTL)
+	if err != nil {
+		s.logger.Error("Failed to acquire GitHub sync lock", zap.Error(err))
+		return time.Time{}
+	}
+	if !acquired {
+		return time.Time{}
+	}
+	defer s.cache.ReleaseLock(context.Background(), githubSyncLockKey, s.instance)
+
+	ctx, cancel := context.WithTimeout(ctx, githubPassLimit)
+	defer cancel()
+
+	synced, err := s.service.SyncAll(ctx)
+	var limited *github.RateLimitError
+	switch {
+	case errors.As(err, &limited):
+		s.logger.Warn("GitHub rate limit reached, pausing sync",
+			zap.Int("synced", synced),
+			zap.Time("until", limited.Reset),
+		)
+		return limited.Reset
+	case err != nil:
+		s.logger.Error("Failed to sync GitHub metadata", zap.Int("synced", synced), zap.Error(err))
+	default:
+		s.logger.Debug("Synced GitHub metadata", zap.Int("count", synced))
+	}
+	return time.Time{}
+}
@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/service"
+	"go.uber.org/zap"
+)
+
+const (
+	mediaSweepLockKey = "media-sweep"
+
+	// How long a worker blocks on the queue before checking for shutdown
+	dequeueTimeout = 5 * time.Second
+	// Uploads still pending after this long are assumed to have lost their job
+	staleAfter    = 10 * time.Minute
+	sweepInterval = 5 * time.Minute
+	// Upper bound on one job; the per-media lock expires after it
+	jobTimeout = 10 * time.Minute
+)
+
+// MediaProcessor renders derivatives for uploaded images. Jobs come off a
+// Redis queue, so any replica can pick up an upload made on another; a
+// per-media lock keeps duplicate jobs from running twice at once.
+type MediaProcessor struct {
+	service     service.MediaService
+	cache       *cache.RedisCache
+	logger      *zap.Logger
+	concurrency int
+	instance    string
+}
+
+func NewMediaProcessor(service service.MediaService, cache *cache.RedisCache, logger *zap.Logger, concurrency int) *MediaProcessor {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &MediaProcessor{
+		service:     service,
+		cache:       cache,
+		logger:      logger,
+		concurrency: concurrency,
+		instance:    uuid.New().String(),
+	}
+}
+
+// Run blocks until ctx is cancelled and in-flight jobs have finished
+func (p *MediaProcessor) Run(ctx context.Context) {
+	p.logger.Info("Media processor started", zap.Int("concurrency", p.concurrency))
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+	for {
+		p.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			p.logger.Info("Media processor stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *MediaProcessor) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		payload, err := p.cache.Dequeue(ctx, service.MediaQueue, dequeueTimeout)
+		if err != nil {
+			if !errors.Is(err, cache.ErrCacheMiss) && ctx.Err() == nil {
+				p.logger.Error("Failed to dequeue media job", zap.Error(err))
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+		p.process(string(payload))
+	}
+}
+
+// process runs one job to completion even during shutdown, so an upload is
+// never left with half its variants written
+func (p *MediaProcessor) process(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+	defer cancel()
+
+	lockKey := "media:" + id
+	acquired, err := p.cache.AcquireLock(ctx, lockKey, p.instance, jobTimeout)
+	if err != nil {
+		p.logger.Error("Failed to acquire media lock", zap.String("media_id", id), zap.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer p.cache.ReleaseLock(context.Background(), lockKey, p.instance)
+
+	started := time.Now()
+	if err := p.service.ProcessMedia(ctx, id); err != nil {
+		p.logger.Error("Failed to process media", zap.String("media_id", id), zap.Error(err))
+		return
+	}
+	p.logger.Info("Processed media", zap.String("media_id", id), zap.Duration("duration", time.Since(started)))
+}
+
+// sweep re-enqueues uploads whose job never ran, e.g. because Redis lost it
+// or the enqueue after upload failed
+func (p *MediaProcessor) sweep(ctx context.Context) {
+	// Not released: holding it for the interval means one sweep per interval
+	// across all replicas
+	acquired, err := p.cache.AcquireLock(ctx, mediaSweepLockKey, p.instance, sweepInterval)
+	if err != nil {
+		p.logger.Error("Failed to acquire media sweep lock", zap.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	requeued, err := p.service.RequeuePending(ctx, staleAfter)
+	if err != nil {
+		p.logger.Error("Failed to requeue pending media", zap.Error(err))
+	}
+	if requeued > 0 {
+		p.logger.Info("Requeued pending media", zap.Int("count", requeued))
+	}
+}
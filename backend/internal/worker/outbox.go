@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"context"
+	"time"
+	"github.com/google/uuid"
+	"github.com/portfolio/backend/internal/cache"
+	"github.com/portfolio/backend/internal/service"
+	"go.uber.org/zap"
+)
+
+const (
+	outboxRelayLockKey = "outbox-relay"
+
+	outboxBatchSize = 100
+	// The lock outlives a drain, which stops well before it expires, so no
+	// two replicas ever publish at once
+	outboxLockTTL    = 30 * time.Second
+	outboxDrainLimit = 10 * time.Second
+	outboxPruneEvery = time.Hour
+)
+
+// OutboxRelay publishes events recorded in the outbox. Every replica runs
+// one, but only the holder of a Redis lock publishes, which keeps events in
+// the order they were committed. A failed publish is retried with backoff,
+// holding back later events for the same key; an event that fails too often
+// is parked so the rest of the outbox keeps moving.
+type OutboxRelay struct {
+	service    service.OutboxService
+	cache      *cache.RedisCache
+	logger     *zap.Logger
+	interval   time.Duration
+	maxBackoff time.Duration
+	retention  time.Duration
+	instance   string
+}
+
+func NewOutboxRelay(service service.OutboxService, cache *cache.RedisCache, logger *zap.Logger, interval, maxBackoff, retention time.Duration) *OutboxRelay {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if maxBackoff < interval {
+		maxBackoff = interval
+	}
+	return &OutboxRelay{
+		service:    service,
+		cache:      cache,
+		logger:     logger,
+		interval:   interval,
+		maxBackoff: maxBackoff,
+		retention:  retention,
+		instance:   uuid.New().String(),
+	}
+}
+
+// Run polls the outbox until ctx is cancelled
+func (r *OutboxRelay) Run(ctx context.Context) {
+	r.logger.Info("Outbox relay started", zap.Duration("interval", r.interval))
+
+	delay := r.interval
+	var lastPrune time.Time
+	for {
+		if r.drain(ctx) {
+			delay = r.interval
+		} else if delay *= 2; delay > r.maxBackoff {
+			delay = r.maxBackoff
+		}
+
+		if r.retention > 0 && time.Since(lastPrune) >= outboxPruneEvery {
+			r.prune(ctx)
+			lastPrune = time.Now()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// drain publishes batches until the outbox is empty or the drain runs out of
+// time. It reports false if publishing failed.
+func (r *OutboxRelay) drain(ctx context.Context) bool {
+	acquired, err := r.cache.AcquireLock(ctx, outboxRelayLockKey, r.instance, outboxLockTTL)
+	if err != nil {
+		r.logger.Error("Failed to acquire outbox relay lock", zap.Error(err))
+		return false
+	}
+	if !acquired {
+		return true
+	}
+	defer r.cache.ReleaseLock(context.Background(), outboxRelayLockKey, r.instance)
+
+	ctx, cancel := context.WithTimeout(ctx, outboxDrainLimit)
+	defer cancel()
+
+	for ctx.Err() == nil {
+		result, err := r.service.Relay(ctx, outboxBatchSize)
+		if result != nil {
+			if result.Sent > 0 {
+				r.logger.Debug("Relayed outbox events", zap.Int("count", result.Sent))
+			}
+			for _, e := range result.Parked {
+				r.logger.Error("Parked outbox event after repeated failures",
+					zap.Int64("id", e.ID),
+					zap.String("event_id", e.EventID.String()),
+					zap.String("topic", e.Topic),
+					zap.String("event_type", e.EventType),
+					zap.String("key", e.Key),
+					zap.Int("attempts", e.Attempts),
+					zap.String("last_error", e.LastError),
+				)
+			}
+		}
+		if err != nil {
+			r.logger.Error("Failed to relay outbox events", zap.Error(err))
+			return false
+		}
+		if result.Read < outboxBatchSize {
+			break
+		}
+	}
+	return true
+}
+
+func (r *OutboxRelay) prune(ctx context.Context) {
+	acquired, err := r.cache.AcquireLock(ctx, outboxRelayLockKey, r.instance, outboxLockTTL)
+	if err != nil || !acquired {
+		return
+	}
+	defer r.cache.ReleaseLock(context.Background(), outboxRelayLockKey, r.instance)
+
+	deleted, err := r.service.Prune(ctx, r.retention)
+	if err != nil {
+		r.logger.Error("Failed to prune outbox", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		r.logger.Info("Pruned sent outbox events", zap.Int64("count", deleted))
+	}
+}
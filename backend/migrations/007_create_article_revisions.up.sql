@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    author_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    excerpt TEXT,
+    content TEXT NOT NULL,
+    published BOOLEAN DEFAULT false,
+    autosave BOOLEAN DEFAULT false,
+    restored_from_id UUID REFERENCES article_revisions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (article_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_revisions_autosave ON article_revisions(autosave);
+CREATE INDEX IF NOT EXISTS idx_article_revisions_created_at ON article_revisions(created_at);
+
+-- Give every existing article a baseline revision so history starts from today's content
+INSERT INTO article_revisions (article_id, number, author_id, title, slug, excerpt, content, published, created_at)
+SELECT id, 1, author_id, title, slug, excerpt, content, published, updated_at
+FROM articles
+WHERE deleted_at IS NULL
+ON CONFLICT (article_id, number) DO NOTHING;
@@ -0,0 +1,6 @@
+-- Rendered HTML and reading metadata are computed on save from the Markdown
+-- source; rows written earlier are rendered on read until their next edit
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS content_html TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS toc JSONB DEFAULT '[]'::jsonb;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS word_count INTEGER DEFAULT 0;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS reading_time_minutes INTEGER DEFAULT 0;
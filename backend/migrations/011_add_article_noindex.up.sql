@@ -0,0 +1,2 @@
+-- Articles flagged noindex are left out of the sitemap
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS noindex BOOLEAN DEFAULT false;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS media (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    key VARCHAR(500) NOT NULL,
+    url VARCHAR(1000) NOT NULL,
+    filename VARCHAR(255),
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    width INTEGER,
+    height INTEGER,
+    checksum VARCHAR(64),
+    alt VARCHAR(500),
+    uploaded_by UUID,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_media_key ON media(key);
+CREATE INDEX IF NOT EXISTS idx_media_content_type ON media(content_type);
+CREATE INDEX IF NOT EXISTS idx_media_checksum ON media(checksum);
+CREATE INDEX IF NOT EXISTS idx_media_created_at ON media(created_at DESC);
+
+-- Covers are cleared, not cascaded, when their media is deleted
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS cover_image_id UUID REFERENCES media(id) ON DELETE SET NULL;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS cover_image_id UUID REFERENCES media(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS project_media (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    media_id UUID NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    caption VARCHAR(500),
+    PRIMARY KEY (project_id, media_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_media_media_id ON project_media(media_id);
@@ -0,0 +1,22 @@
+ALTER TABLE media ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending';
+ALTER TABLE media ADD COLUMN IF NOT EXISTS blur_hash VARCHAR(100);
+ALTER TABLE media ADD COLUMN IF NOT EXISTS processed_at TIMESTAMP;
+
+-- Files uploaded before the pipeline existed only need processing if they are images
+UPDATE media SET status = 'ready' WHERE content_type NOT LIKE 'image/%';
+
+CREATE INDEX IF NOT EXISTS idx_media_status ON media(status);
+
+CREATE TABLE IF NOT EXISTS media_variants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    media_id UUID NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    key VARCHAR(500) NOT NULL,
+    url VARCHAR(1000) NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_media_variants_media_width_format ON media_variants(media_id, width, format);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS analytics_daily_pages (
+    day DATE NOT NULL,
+    path VARCHAR(500) NOT NULL,
+    referrer_domain VARCHAR(255) NOT NULL DEFAULT '',
+    device VARCHAR(20) NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    visitors BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, path, referrer_domain, device)
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_daily_pages_path ON analytics_daily_pages(path);
+
+CREATE TABLE IF NOT EXISTS analytics_daily_visitors (
+    day DATE PRIMARY KEY,
+    visitors BIGINT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL,
+    topic VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_event_id ON outbox_events(event_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_sent_at ON outbox_events(sent_at);
+
+-- The relay only ever scans unsent rows, in order
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(id) WHERE sent_at IS NULL;
@@ -0,0 +1,4 @@
+-- Cursor pagination walks lists by (created_at, id), newest first
+CREATE INDEX IF NOT EXISTS idx_articles_created_at_id ON articles(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_articles_published_created_at_id ON articles(created_at DESC, id DESC) WHERE published = true;
+CREATE INDEX IF NOT EXISTS idx_projects_created_at_id ON projects(created_at DESC, id DESC);
@@ -0,0 +1,2 @@
+-- Technology filters match with jsonb containment (@>), which this serves
+CREATE INDEX IF NOT EXISTS idx_projects_technologies ON projects USING GIN (technologies);
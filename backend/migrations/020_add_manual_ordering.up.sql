@@ -0,0 +1,6 @@
+-- Curated order of projects and pinned articles; NULL means unordered
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS position INTEGER;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS position INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_projects_position ON projects(position);
+CREATE INDEX IF NOT EXISTS idx_articles_position ON articles(position);
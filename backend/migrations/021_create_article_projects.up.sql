@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS article_projects (
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, project_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_projects_project_id ON article_projects(project_id);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS project_github (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    owner VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    stars INTEGER NOT NULL DEFAULT 0,
+    forks INTEGER NOT NULL DEFAULT 0,
+    languages JSONB,
+    license VARCHAR(100),
+    topics JSONB,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    pushed_at TIMESTAMP,
+    latest_release VARCHAR(255),
+    latest_release_url VARCHAR(500),
+    latest_release_at TIMESTAMP,
+    repo_etag VARCHAR(255),
+    languages_etag VARCHAR(255),
+    release_etag VARCHAR(255),
+    synced_at TIMESTAMP
+);
+
+-- The syncer visits the least recently synced repositories first
+CREATE INDEX IF NOT EXISTS idx_project_github_synced_at ON project_github(synced_at);
@@ -0,0 +1,75 @@
+CREATE TABLE IF NOT EXISTS resume_experience (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    position INTEGER,
+    company VARCHAR(255) NOT NULL,
+    role VARCHAR(255) NOT NULL,
+    location VARCHAR(255),
+    url VARCHAR(500),
+    description TEXT,
+    highlights JSONB,
+    technologies JSONB,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS resume_education (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    position INTEGER,
+    institution VARCHAR(255) NOT NULL,
+    degree VARCHAR(255),
+    field VARCHAR(255),
+    location VARCHAR(255),
+    grade VARCHAR(100),
+    url VARCHAR(500),
+    description TEXT,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS resume_skills (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    position INTEGER,
+    name VARCHAR(100) NOT NULL,
+    category VARCHAR(100),
+    level VARCHAR(20) NOT NULL,
+    since DATE,
+    last_used DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS resume_certifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    position INTEGER,
+    name VARCHAR(255) NOT NULL,
+    issuer VARCHAR(255),
+    credential_id VARCHAR(255),
+    url VARCHAR(500),
+    issued_on DATE NOT NULL,
+    expires_on DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS resume_awards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    position INTEGER,
+    title VARCHAR(255) NOT NULL,
+    issuer VARCHAR(255),
+    url VARCHAR(500),
+    description TEXT,
+    awarded_on DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_resume_experience_position ON resume_experience(position);
+CREATE INDEX IF NOT EXISTS idx_resume_education_position ON resume_education(position);
+CREATE INDEX IF NOT EXISTS idx_resume_skills_position ON resume_skills(position);
+CREATE INDEX IF NOT EXISTS idx_resume_skills_category ON resume_skills(category);
+CREATE INDEX IF NOT EXISTS idx_resume_certifications_position ON resume_certifications(position);
+CREATE INDEX IF NOT EXISTS idx_resume_awards_position ON resume_awards(position);
@@ -0,0 +1,45 @@
+-- Translations of the articles, projects and portfolio into locales other
+-- than the default, which the rows themselves are written in
+CREATE TABLE IF NOT EXISTS article_translations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    excerpt TEXT,
+    content TEXT NOT NULL,
+    content_html TEXT,
+    toc JSONB,
+    word_count INTEGER DEFAULT 0,
+    reading_time_minutes INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_translations_article_locale ON article_translations(article_id, locale);
+-- Slugs are unique per locale, and looked up by it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_translations_locale_slug ON article_translations(locale, slug);
+
+CREATE TABLE IF NOT EXISTS project_translations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_translations_project_locale ON project_translations(project_id, locale);
+
+CREATE TABLE IF NOT EXISTS portfolio_translations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    portfolio_id UUID NOT NULL REFERENCES portfolio(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    title VARCHAR(255),
+    bio TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_portfolio_translations_portfolio_locale ON portfolio_translations(portfolio_id, locale);
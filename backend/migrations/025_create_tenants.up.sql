@@ -0,0 +1,106 @@
+-- Tenants are the portfolios hosted by one deployment. Requests are matched
+-- to one by Host or by the /t/<slug> path prefix; the slug also names the
+-- tenant in auth-service's tenant_owners.
+CREATE TABLE IF NOT EXISTS tenants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(63) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    hosts JSONB,
+    site_url VARCHAR(500),
+    api_url VARCHAR(500),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenants_slug ON tenants(slug);
+CREATE INDEX IF NOT EXISTS idx_tenants_hosts ON tenants USING GIN (hosts);
+
+-- Everything written so far belongs to the default tenant, which serves
+-- hosts no other tenant claims
+INSERT INTO tenants (slug, name, hosts)
+SELECT 'default', COALESCE((SELECT name FROM portfolio ORDER BY created_at LIMIT 1), 'Default'), '[]'::jsonb
+WHERE NOT EXISTS (SELECT 1 FROM tenants WHERE slug = 'default');
+
+ALTER TABLE portfolio ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE media ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE resume_experience ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE resume_education ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE resume_skills ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE resume_certifications ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE resume_awards ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE article_translations ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE analytics_daily_pages ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE analytics_daily_visitors ADD COLUMN IF NOT EXISTS tenant_id UUID;
+-- Nullable: events raised outside any tenant carry none
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS tenant_id UUID;
+
+UPDATE portfolio SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE articles SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE projects SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE tags SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE media SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE comments SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE resume_experience SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE resume_education SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE resume_skills SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE resume_certifications SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE resume_awards SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE article_translations SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE analytics_daily_pages SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+UPDATE analytics_daily_visitors SET tenant_id = (SELECT id FROM tenants WHERE slug = 'default') WHERE tenant_id IS NULL;
+
+ALTER TABLE portfolio ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE articles ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE projects ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE tags ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE media ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE comments ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE resume_experience ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE resume_education ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE resume_skills ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE resume_certifications ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE resume_awards ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE article_translations ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE analytics_daily_pages ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE analytics_daily_visitors ALTER COLUMN tenant_id SET NOT NULL;
+
+ALTER TABLE portfolio ADD CONSTRAINT fk_portfolio_tenant FOREIGN KEY (tenant_id) REFERENCES tenants(id);
+ALTER TABLE articles ADD CONSTRAINT fk_articles_tenant FOREIGN KEY (tenant_id) REFERENCES tenants(id);
+ALTER TABLE projects ADD CONSTRAINT fk_projects_tenant FOREIGN KEY (tenant_id) REFERENCES tenants(id);
+ALTER TABLE tags ADD CONSTRAINT fk_tags_tenant FOREIGN KEY (tenant_id) REFERENCES tenants(id);
+ALTER TABLE media ADD CONSTRAINT fk_media_tenant FOREIGN KEY (tenant_id) REFERENCES tenants(id);
+ALTER TABLE comments ADD CONSTRAINT fk_comments_tenant FOREIGN KEY (tenant_id) REFERENCES tenants(id);
+
+-- One portfolio per tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_portfolio_tenant_id ON portfolio(tenant_id);
+
+-- Slugs only need to be unique within their tenant
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_slug_key;
+DROP INDEX IF EXISTS idx_articles_slug;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_tenant_slug ON articles(tenant_id, slug);
+
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_slug_key;
+DROP INDEX IF EXISTS idx_tags_slug;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_tenant_slug ON tags(tenant_id, slug);
+
+DROP INDEX IF EXISTS idx_article_translations_locale_slug;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_translations_tenant_locale_slug ON article_translations(tenant_id, locale, slug);
+
+CREATE INDEX IF NOT EXISTS idx_projects_tenant_id ON projects(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_media_tenant_id ON media(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_comments_tenant_id ON comments(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_resume_experience_tenant_id ON resume_experience(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_resume_education_tenant_id ON resume_education(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_resume_skills_tenant_id ON resume_skills(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_resume_certifications_tenant_id ON resume_certifications(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_resume_awards_tenant_id ON resume_awards(tenant_id);
+
+-- Each tenant's traffic is rolled up separately
+ALTER TABLE analytics_daily_pages DROP CONSTRAINT IF EXISTS analytics_daily_pages_pkey;
+ALTER TABLE analytics_daily_pages ADD PRIMARY KEY (tenant_id, day, path, referrer_domain, device);
+ALTER TABLE analytics_daily_visitors DROP CONSTRAINT IF EXISTS analytics_daily_visitors_pkey;
+ALTER TABLE analytics_daily_visitors ADD PRIMARY KEY (tenant_id, day);
@@ -0,0 +1,3 @@
+-- Why the last sync of a project's repository failed for good, if it did.
+-- Failed syncs also set synced_at, so they wait their turn like the rest.
+ALTER TABLE project_github ADD COLUMN IF NOT EXISTS sync_error VARCHAR(255);
@@ -0,0 +1,7 @@
+-- Events the relay gave up on after OUTBOX_MAX_ATTEMPTS failed publishes.
+-- They are kept for inspection; clearing failed_at queues one again.
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS failed_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(id) WHERE sent_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_failed_at ON outbox_events(failed_at) WHERE failed_at IS NOT NULL;
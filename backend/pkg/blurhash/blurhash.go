@@ -0,0 +1,122 @@
+// Package blurhash computes BlurHash placeholders (https://blurha.sh): a short
+// string clients decode into a blurred preview while the real image loads.
+package blurhash
+
+import (
+	"errors"
+	"image"
+	"math"
+	"strings"
+)
+
+var ErrInvalidComponents = errors.New("blurhash: components must be between 1 and 9")
+
+const characters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Encode hashes m with xComponents by yComponents cosine components. The
+// image should already be small; every component visits every pixel.
+func Encode(xComponents, yComponents int, m image.Image) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", ErrInvalidComponents
+	}
+
+	b := m.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width == 0 || height == 0 {
+		return "", errors.New("blurhash: empty image")
+	}
+
+	linear := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, bl, _ := m.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			linear[y*width+x] = [3]float64{srgbToLinear(r >> 8), srgbToLinear(g >> 8), srgbToLinear(bl >> 8)}
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			factors = append(factors, component(linear, width, height, i, j))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(base83((xComponents-1)+(yComponents-1)*9, 1))
+
+	maxValue := 1.0
+	if len(factors) > 1 {
+		actualMax := 0.0
+		for _, f := range factors[1:] {
+			actualMax = math.Max(actualMax, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maxValue = float64(quantisedMax+1) / 166
+		sb.WriteString(base83(quantisedMax, 1))
+	} else {
+		sb.WriteString(base83(0, 1))
+	}
+
+	dc := factors[0]
+	sb.WriteString(base83(linearToSRGB(dc[0])<<16|linearToSRGB(dc[1])<<8|linearToSRGB(dc[2]), 4))
+	for _, f := range factors[1:] {
+		sb.WriteString(base83(quantiseAC(f[0], maxValue)*19*19+quantiseAC(f[1], maxValue)*19+quantiseAC(f[2], maxValue), 2))
+	}
+
+	return sb.String(), nil
+}
+
+func component(linear [][3]float64, width, height, i, j int) [3]float64 {
+	normalisation := 2.0
+	if i == 0 && j == 0 {
+		normalisation = 1
+	}
+
+	var sum [3]float64
+	for y := 0; y < height; y++ {
+		basisY := math.Cos(math.Pi * float64(j) * float64(y) / float64(height))
+		for x := 0; x < width; x++ {
+			basis := basisY * math.Cos(math.Pi*float64(i)*float64(x)/float64(width))
+			p := linear[y*width+x]
+			sum[0] += basis * p[0]
+			sum[1] += basis * p[1]
+			sum[2] += basis * p[2]
+		}
+	}
+
+	scale := normalisation / float64(width*height)
+	return [3]float64{sum[0] * scale, sum[1] * scale, sum[2] * scale}
+}
+
+func quantiseAC(v, maxValue float64) int {
+	return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maxValue, 0.5)*9+9.5))))
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
+
+func srgbToLinear(v uint32) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) int {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func base83(value, length int) string {
+	out := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		out[i] = characters[value%83]
+		value /= 83
+	}
+	return string(out)
+}
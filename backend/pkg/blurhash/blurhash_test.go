@@ -0,0 +1,102 @@
+package blurhash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func fill(w, h int, at func(x, y int) color.NRGBA) *image.NRGBA {
+	m := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			m.SetNRGBA(x, y, at(x, y))
+		}
+	}
+	return m
+}
+
+func solid(c color.NRGBA) func(x, y int) color.NRGBA {
+	return func(int, int) color.NRGBA { return c }
+}
+
+// Expected hashes come from the reference algorithm at blurha.sh
+func TestEncodeKnownValues(t *testing.T) {
+	split := func(x, y int) color.NRGBA {
+		if x < 10 {
+			return color.NRGBA{200, 30, 30, 0xff}
+		}
+		return color.NRGBA{30, 30, 200, 0xff}
+	}
+
+	tests := []struct {
+		name   string
+		xc, yc int
+		img    image.Image
+		want   string
+	}{
+		{"white", 1, 1, fill(4, 4, solid(color.NRGBA{255, 255, 255, 255})), "00TSUA"},
+		{"red", 1, 1, fill(3, 2, solid(color.NRGBA{255, 0, 0, 255})), "00TI:j"},
+		{"black", 4, 3, fill(8, 8, solid(color.NRGBA{0, 0, 0, 255})), "L00000fQfQfQfQfQfQfQfQfQfQfQ"},
+		{"gradient", 4, 3, fill(32, 24, func(x, y int) color.NRGBA {
+			return color.NRGBA{uint8(x * 8), uint8(y * 10), 128, 255}
+		}), "LxH27k2swxX8mHWWjtf7gJfjfQfj"},
+		{"checkerboard", 3, 3, fill(16, 16, func(x, y int) color.NRGBA {
+			if (x/4+y/4)%2 == 0 {
+				return color.NRGBA{255, 255, 255, 255}
+			}
+			return color.NRGBA{20, 40, 60, 255}
+		}), "KBLqhJ_4fQ_4~qfQfQfQfQ"},
+		{"split 4x3", 4, 3, fill(20, 10, split), "L$G}6v|nsROIw~sRjsa~fQfQfQfQ"},
+		{"split 9x1", 9, 1, fill(20, 10, split), "8$G}6v|nsROIfQ$LsRW@fQ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(tt.xc, tt.yc, tt.img)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Encode = %q, want %q", got, tt.want)
+			}
+			// Size flag, max AC, DC, then two characters per AC component
+			if want := 1 + 1 + 4 + 2*(tt.xc*tt.yc-1); len(got) != want {
+				t.Errorf("length %d, want %d", len(got), want)
+			}
+		})
+	}
+}
+
+func TestEncodeIgnoresBoundsOrigin(t *testing.T) {
+	m := fill(40, 40, func(x, y int) color.NRGBA {
+		return color.NRGBA{uint8(x * 6), uint8(y * 6), 90, 255}
+	})
+	sub := m.SubImage(image.Rect(10, 10, 30, 30))
+	moved := fill(20, 20, func(x, y int) color.NRGBA {
+		return color.NRGBA{uint8((x + 10) * 6), uint8((y + 10) * 6), 90, 255}
+	})
+
+	a, err := Encode(4, 4, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Encode(4, 4, moved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("sub-image hashes to %q, the same pixels at the origin to %q", a, b)
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	m := fill(2, 2, solid(color.NRGBA{1, 2, 3, 255}))
+	for _, c := range [][2]int{{0, 1}, {1, 0}, {10, 1}, {1, 10}} {
+		if _, err := Encode(c[0], c[1], m); err != ErrInvalidComponents {
+			t.Errorf("Encode(%d, %d) err = %v, want ErrInvalidComponents", c[0], c[1], err)
+		}
+	}
+	if _, err := Encode(4, 3, image.NewNRGBA(image.Rect(0, 0, 0, 5))); err == nil {
+		t.Error("Encode of an empty image succeeded")
+	}
+}
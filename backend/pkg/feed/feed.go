@@ -0,0 +1,352 @@
+// Package feed encodes a list of entries as RSS 2.0, Atom 1.0 or JSON Feed 1.1.
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Supported output formats
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+// Content types served for each format
+var ContentTypes = map[string]string{
+	FormatRSS:  "application/rss+xml; charset=utf-8",
+	FormatAtom: "application/atom+xml; charset=utf-8",
+	FormatJSON: "application/feed+json; charset=utf-8",
+}
+
+type Author struct {
+	Name  string
+	Email string
+	URL   string
+}
+
+// Alternate is an HTML page in another language, linked with hreflang in
+// RSS and Atom. JSON Feed has no equivalent, so it leaves them out.
+type Alternate struct {
+	Language string
+	URL      string
+}
+
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Summary     string
+	ContentHTML string
+	Tags        []string
+	Published   time.Time
+	Updated     time.Time
+	Alternates  []Alternate // The item's page in every language, Link's included
+}
+
+// Feed is the format-independent description of a feed
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // HTML page the feed belongs to
+	FeedURL     string // URL the feed itself is served from
+	Language    string
+	Author      Author
+	Updated     time.Time
+	Alternates  []Alternate // Link in every language, Link's included
+	Items       []Item
+}
+
+// Encode renders the feed in the given format
+func (f *Feed) Encode(format string) ([]byte, error) {
+	switch format {
+	case FormatRSS:
+		return f.rss()
+	case FormatAtom:
+		return f.atom()
+	case FormatJSON:
+		return f.json()
+	}
+	return nil, fmt.Errorf("unsupported feed format %q", format)
+}
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title          string     `xml:"title"`
+	Link           string     `xml:"link"`
+	Description    string     `xml:"description"`
+	Language       string     `xml:"language,omitempty"`
+	ManagingEditor string     `xml:"managingEditor,omitempty"`
+	LastBuildDate  string     `xml:"lastBuildDate"`
+	AtomLinks      []atomLink `xml:"atom:link"` // Self, then alternates
+	Items          []rssItem  `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+type rssItem struct {
+	Title       string     `xml:"title"`
+	Link        string     `xml:"link"`
+	GUID        rssGUID    `xml:"guid"`
+	PubDate     string     `xml:"pubDate"`
+	Author      string     `xml:"author,omitempty"`
+	Description string     `xml:"description,omitempty"`
+	Content     *cdata     `xml:"content:encoded,omitempty"`
+	Categories  []string   `xml:"category"`
+	AtomLinks   []atomLink `xml:"atom:link"`
+}
+
+func (f *Feed) rss() ([]byte, error) {
+	channel := rssChannel{
+		Title:          f.Title,
+		Link:           f.Link,
+		Description:    f.Description,
+		Language:       f.Language,
+		ManagingEditor: rssPerson(f.Author),
+		LastBuildDate:  f.Updated.UTC().Format(time.RFC1123Z),
+		AtomLinks:      []atomLink{{Href: f.FeedURL, Rel: "self", Type: "application/rss+xml"}},
+		Items:          make([]rssItem, 0, len(f.Items)),
+	}
+	channel.AtomLinks = append(channel.AtomLinks, alternateLinks(f.Alternates)...)
+
+	for _, item := range f.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: false, Value: item.ID},
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Author:      rssPerson(f.Author),
+			Description: item.Summary,
+			Categories:  item.Tags,
+			AtomLinks:   alternateLinks(item.Alternates),
+		}
+		if item.ContentHTML != "" {
+			entry.Content = &cdata{Value: item.ContentHTML}
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+
+	return marshalXML(rssDocument{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		Channel:   channel,
+	})
+}
+
+// rssPerson formats an author the way RSS expects: "email (Name)"
+func rssPerson(a Author) string {
+	if a.Email == "" {
+		return ""
+	}
+	if a.Name == "" {
+		return a.Email
+	}
+	return fmt.Sprintf("%s (%s)", a.Email, a.Name)
+}
+
+type atomDocument struct {
+	XMLName  xml.Name    `xml:"feed"`
+	NS       string      `xml:"xmlns,attr"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   *atomPerson `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href     string `xml:"href,attr"`
+	Rel      string `xml:"rel,attr,omitempty"`
+	Type     string `xml:"type,attr,omitempty"`
+	HrefLang string `xml:"hreflang,attr,omitempty"`
+}
+
+// alternateLinks links the pages in other languages. A page in a single
+// language has no alternates worth listing.
+func alternateLinks(alternates []Alternate) []atomLink {
+	if len(alternates) < 2 {
+		return nil
+	}
+	links := make([]atomLink, len(alternates))
+	for i, a := range alternates {
+		links[i] = atomLink{Href: a.URL, Rel: "alternate", Type: "text/html", HrefLang: a.Language}
+	}
+	return links
+}
+
+type atomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+	URI   string `xml:"uri,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+func (f *Feed) atom() ([]byte, error) {
+	doc := atomDocument{
+		NS:       "http://www.w3.org/2005/Atom",
+		Lang:     f.Language,
+		ID:       f.FeedURL,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.FeedURL, Rel: "self"},
+			{Href: f.Link, Rel: "alternate", Type: "text/html", HrefLang: f.Language},
+		},
+		Entries: make([]atomEntry, 0, len(f.Items)),
+	}
+	doc.Links = append(doc.Links, otherLanguages(f.Link, f.Alternates)...)
+	if f.Author.Name != "" {
+		doc.Author = &atomPerson{Name: f.Author.Name, Email: f.Author.Email, URI: f.Author.URL}
+	}
+
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Links:     []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: item.Summary}
+		}
+		if item.ContentHTML != "" {
+			entry.Content = &atomText{Type: "html", Value: item.ContentHTML}
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		entry.Links = append(entry.Links, otherLanguages(item.Link, item.Alternates)...)
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshalXML(doc)
+}
+
+// otherLanguages links the alternates other than link itself, which Atom
+// already lists as the alternate without a language
+func otherLanguages(link string, alternates []Alternate) []atomLink {
+	var links []atomLink
+	for _, l := range alternateLinks(alternates) {
+		if l.Href != link {
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url,omitempty"`
+	FeedURL     string       `json:"feed_url,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Language    string       `json:"language,omitempty"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type jsonItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url,omitempty"`
+	Title         string   `json:"title,omitempty"`
+	ContentHTML   string   `json:"content_html,omitempty"`
+	Summary       string   `json:"summary,omitempty"`
+	DatePublished string   `json:"date_published,omitempty"`
+	DateModified  string   `json:"date_modified,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func (f *Feed) json() ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Language:    f.Language,
+		Items:       make([]jsonItem, 0, len(f.Items)),
+	}
+	if f.Author.Name != "" {
+		doc.Authors = []jsonAuthor{{Name: f.Author.Name, URL: f.Author.URL}}
+	}
+
+	for _, item := range f.Items {
+		entry := jsonItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentHTML:   item.ContentHTML,
+			Summary:       item.Summary,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+
+	// Item content is HTML; keep it readable instead of \u003c-escaped
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,304 @@
+// Package imagemeta removes embedded metadata (EXIF, XMP, IPTC, text chunks)
+// from JPEG, PNG and WebP files without re-encoding them, and applies EXIF
+// orientation to decoded images.
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/draw"
+)
+
+var ErrMalformed = errors.New("imagemeta: malformed image")
+
+const (
+	markerSOS  = 0xda
+	markerAPP0 = 0xe0
+	markerAPP1 = 0xe1
+	markerIPTC = 0xed
+	markerCOM  = 0xfe
+
+	tagOrientation = 0x0112
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Strip returns data with metadata removed. Formats it doesn't know are
+// returned unchanged. A JPEG's orientation survives in a minimal EXIF block
+// holding only that tag, so the original still displays upright.
+func Strip(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/webp":
+		return stripWebP(data)
+	}
+	return data, nil
+}
+
+// Orientation reads the EXIF orientation (1-8) of a JPEG, defaulting to 1
+func Orientation(data []byte) int {
+	orientation := 1
+	walkJPEG(data, func(marker byte, segment []byte) bool {
+		if marker != markerAPP1 {
+			return true
+		}
+		if o := exifOrientation(segment[4:]); o != 0 {
+			orientation = o
+			return false
+		}
+		return true
+	})
+	return orientation
+}
+
+// walkJPEG calls fn with each marker segment (marker and length included)
+// before the image data. It stops early when fn returns false.
+func walkJPEG(data []byte, fn func(marker byte, segment []byte) bool) (int, error) {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return 0, ErrMalformed
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xff {
+			return 0, ErrMalformed
+		}
+		marker := data[pos+1]
+		if marker == 0xff {
+			// Fill byte
+			pos++
+			continue
+		}
+		if marker == markerSOS {
+			return pos, nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 0, ErrMalformed
+		}
+		if !fn(marker, data[pos:pos+2+length]) {
+			return pos, nil
+		}
+		pos += 2 + length
+	}
+	return 0, ErrMalformed
+}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	orientation := Orientation(data)
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xff, 0xd8)
+	wroteExif := false
+	writeExif := func() {
+		if orientation != 1 && !wroteExif {
+			out = append(out, orientationSegment(orientation)...)
+		}
+		wroteExif = true
+	}
+
+	sos, err := walkJPEG(data, func(marker byte, segment []byte) bool {
+		switch marker {
+		case markerAPP1, markerIPTC, markerCOM:
+			return true
+		case markerAPP0:
+			// JFIF must stay the first segment
+			out = append(out, segment...)
+			writeExif()
+			return true
+		}
+		writeExif()
+		out = append(out, segment...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	writeExif()
+
+	return append(out, data[sos:]...), nil
+}
+
+// exifOrientation reads the orientation tag from an APP1 payload, or 0
+func exifOrientation(payload []byte) int {
+	if !bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+		return 0
+	}
+	tiff := payload[6:]
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == tagOrientation {
+			o := int(order.Uint16(tiff[entry+8:]))
+			if o < 1 || o > 8 {
+				return 0
+			}
+			return o
+		}
+	}
+	return 0
+}
+
+// orientationSegment builds an APP1 segment whose EXIF holds only the
+// orientation tag
+func orientationSegment(orientation int) []byte {
+	payload := []byte("Exif\x00\x00")
+	payload = append(payload, 'M', 'M', 0, 42, 0, 0, 0, 8) // big-endian TIFF, IFD0 at 8
+	payload = append(payload, 0, 1)                        // one entry
+	payload = append(payload, 0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, byte(orientation), 0, 0)
+	payload = append(payload, 0, 0, 0, 0) // no next IFD
+
+	segment := []byte{0xff, markerAPP1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// PNG chunks that only carry metadata
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, ErrMalformed
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	for pos := len(pngSignature); pos < len(data); {
+		if pos+12 > len(data) {
+			return nil, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, ErrMalformed
+		}
+
+		chunk := data[pos:end]
+		if !pngMetadataChunks[string(chunk[4:8])] {
+			if crc32.ChecksumIEEE(chunk[4:8+length]) != binary.BigEndian.Uint32(chunk[8+length:]) {
+				return nil, ErrMalformed
+			}
+			out = append(out, chunk...)
+		}
+		pos = end
+	}
+	return out, nil
+}
+
+const (
+	vp8xFlagXMP  = 0x04
+	vp8xFlagEXIF = 0x08
+)
+
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrMalformed
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	for pos := 12; pos < len(data); {
+		if pos+8 > len(data) {
+			return nil, ErrMalformed
+		}
+		fourCC := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + size + size&1
+		if size < 0 || end > len(data) {
+			return nil, ErrMalformed
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			start := len(out)
+			out = append(out, data[pos:end]...)
+			if size > 0 {
+				out[start+8] &^= vp8xFlagXMP | vp8xFlagEXIF
+			}
+		default:
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
+
+// Orient returns m transformed so it displays upright for the given EXIF
+// orientation
+func Orient(m image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return m
+	}
+
+	b := m.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Rect, m, b.Min, draw.Src)
+
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = w-1-x, y
+			case 3: // rotated 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90 counter-clockwise
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,406 @@
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/portfolio/backend/pkg/webp"
+	xwebp "golang.org/x/image/webp"
+)
+
+func testImage() *image.NRGBA {
+	m := image.NewNRGBA(image.Rect(0, 0, 24, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 24; x++ {
+			m.SetNRGBA(x, y, color.NRGBA{uint8(x * 10), uint8(y * 15), 0x80, 0xff})
+		}
+	}
+	return m
+}
+
+// exifPayload builds an APP1/EXIF payload with a camera make, the
+// orientation and a GPS pointer, in the given byte order
+func exifPayload(order binary.ByteOrder, orientation int) []byte {
+	tiff := make([]byte, 8)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+
+	entry := func(tag, typ uint16, count, value uint32) []byte {
+		e := make([]byte, 12)
+		order.PutUint16(e, tag)
+		order.PutUint16(e[2:], typ)
+		order.PutUint32(e[4:], count)
+		if typ == 3 {
+			order.PutUint16(e[8:], uint16(value))
+		} else {
+			order.PutUint32(e[8:], value)
+		}
+		return e
+	}
+	ifd := make([]byte, 2)
+	order.PutUint16(ifd, 3)
+	ifd = append(ifd, entry(0x010f, 2, 4, 0x41434d45)...) // Make "ACME"
+	ifd = append(ifd, entry(tagOrientation, 3, 1, uint32(orientation))...)
+	ifd = append(ifd, entry(0x8825, 4, 1, 0)...) // GPS IFD pointer
+	ifd = append(ifd, 0, 0, 0, 0)
+
+	return append(append([]byte("Exif\x00\x00"), tiff...), ifd...)
+}
+
+func jpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xff, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// jpegWithMetadata encodes a JPEG and inserts segments after its JFIF header
+func jpegWithMetadata(t *testing.T, segments ...[]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// Go's encoder writes SOI then straight into the tables; insert after SOI
+	out := append([]byte{}, data[:2]...)
+	for _, s := range segments {
+		out = append(out, s...)
+	}
+	return append(out, data[2:]...)
+}
+
+func jpegMarkers(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var markers []byte
+	if _, err := walkJPEG(data, func(marker byte, _ []byte) bool {
+		markers = append(markers, marker)
+		return true
+	}); err != nil {
+		t.Fatalf("walkJPEG: %v", err)
+	}
+	return markers
+}
+
+func TestOrientation(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		for o := 1; o <= 8; o++ {
+			data := jpegWithMetadata(t, jpegSegment(markerAPP1, exifPayload(order, o)))
+			if got := Orientation(data); got != o {
+				t.Errorf("%v orientation %d: got %d", order, o, got)
+			}
+		}
+	}
+
+	// Missing, out of range or unreadable EXIF means upright
+	for name, data := range map[string][]byte{
+		"no exif":      jpegWithMetadata(t),
+		"out of range": jpegWithMetadata(t, jpegSegment(markerAPP1, exifPayload(binary.BigEndian, 9))),
+		"xmp only":     jpegWithMetadata(t, jpegSegment(markerAPP1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta/>"))),
+		"truncated":    jpegWithMetadata(t, jpegSegment(markerAPP1, []byte("Exif\x00\x00MM\x00"))),
+		"not a jpeg":   []byte("GIF89a"),
+	} {
+		if got := Orientation(data); got != 1 {
+			t.Errorf("%s: got %d, want 1", name, got)
+		}
+	}
+}
+
+func TestStripJPEG(t *testing.T) {
+	data := jpegWithMetadata(t,
+		jpegSegment(markerAPP0, []byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00")),
+		jpegSegment(markerAPP1, exifPayload(binary.LittleEndian, 6)),
+		jpegSegment(markerAPP1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>secret</x:xmpmeta>")),
+		jpegSegment(markerIPTC, []byte("Photoshop 3.0\x008BIM")),
+		jpegSegment(markerCOM, []byte("taken at home")),
+	)
+
+	stripped, err := Strip("image/jpeg", data)
+	if err != nil {
+		t.Fatalf("Strip: %v", err)
+	}
+
+	for _, secret := range []string{"ACME", "secret", "8BIM", "taken at home"} {
+		if bytes.Contains(stripped, []byte(secret)) {
+			t.Errorf("stripped JPEG still contains %q", secret)
+		}
+	}
+	markers := jpegMarkers(t, stripped)
+	if markers[0] != markerAPP0 || markers[1] != markerAPP1 {
+		t.Errorf("markers = %x, want JFIF then the orientation EXIF first", markers)
+	}
+	if n := bytes.Count(markers, []byte{markerAPP1}); n != 1 {
+		t.Errorf("%d APP1 segments, want 1", n)
+	}
+	if bytes.IndexByte(markers, markerCOM) >= 0 || bytes.IndexByte(markers, markerIPTC) >= 0 {
+		t.Errorf("markers = %x still hold a comment or IPTC", markers)
+	}
+	if got := Orientation(stripped); got != 6 {
+		t.Errorf("orientation after strip = %d, want 6", got)
+	}
+
+	// The image data is untouched
+	want, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := jpeg.Decode(bytes.NewReader(stripped))
+	if err != nil {
+		t.Fatalf("decode stripped: %v", err)
+	}
+	assertSamePixels(t, want, got)
+}
+
+func TestStripJPEGUpright(t *testing.T) {
+	data := jpegWithMetadata(t, jpegSegment(markerAPP1, exifPayload(binary.BigEndian, 1)))
+	stripped, err := Strip("image/jpeg", data)
+	if err != nil {
+		t.Fatalf("Strip: %v", err)
+	}
+	if bytes.IndexByte(jpegMarkers(t, stripped), markerAPP1) >= 0 {
+		t.Error("an upright JPEG kept an EXIF segment")
+	}
+}
+
+func TestStripJPEGMalformed(t *testing.T) {
+	data := jpegWithMetadata(t, jpegSegment(markerAPP1, exifPayload(binary.BigEndian, 3)))
+	for name, bad := range map[string][]byte{
+		"empty":          nil,
+		"no SOI":         data[2:],
+		"truncated":      data[:30],
+		"bad length":     append([]byte{0xff, 0xd8, 0xff, 0xe1, 0xff, 0xff}, data[2:]...),
+		"garbage marker": append([]byte{0xff, 0xd8, 0x00, 0xe1}, data[2:]...),
+	} {
+		if _, err := Strip("image/jpeg", bad); err != ErrMalformed {
+			t.Errorf("%s: err = %v, want ErrMalformed", name, err)
+		}
+	}
+}
+
+func pngChunk(typ string, payload []byte) []byte {
+	chunk := make([]byte, 8, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	copy(chunk[4:], typ)
+	chunk = append(chunk, payload...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// pngWithMetadata encodes a PNG and inserts chunks after IHDR
+func pngWithMetadata(t *testing.T, chunks ...[]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	ihdrEnd := len(pngSignature) + 12 + 13
+
+	out := append([]byte{}, data[:ihdrEnd]...)
+	for _, c := range chunks {
+		out = append(out, c...)
+	}
+	return append(out, data[ihdrEnd:]...)
+}
+
+func TestStripPNG(t *testing.T) {
+	data := pngWithMetadata(t,
+		pngChunk("tEXt", []byte("Author\x00Jane")),
+		pngChunk("zTXt", []byte("Comment\x00\x00x")),
+		pngChunk("iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00<x:xmpmeta/>")),
+		pngChunk("eXIf", exifPayload(binary.BigEndian, 6)[6:]),
+		pngChunk("tIME", []byte{0x07, 0xe8, 1, 2, 3, 4, 5}),
+		pngChunk("gAMA", []byte{0, 0, 0xb1, 0x8f}),
+	)
+
+	stripped, err := Strip("image/png", data)
+	if err != nil {
+		t.Fatalf("Strip: %v", err)
+	}
+	for _, typ := range []string{"tEXt", "zTXt", "iTXt", "eXIf", "tIME"} {
+		if bytes.Contains(stripped, []byte(typ)) {
+			t.Errorf("stripped PNG still has a %s chunk", typ)
+		}
+	}
+	// Chunks that affect rendering stay
+	if !bytes.Contains(stripped, []byte("gAMA")) {
+		t.Error("stripped PNG lost its gAMA chunk")
+	}
+
+	got, err := png.Decode(bytes.NewReader(stripped))
+	if err != nil {
+		t.Fatalf("decode stripped: %v", err)
+	}
+	assertSamePixels(t, testImage(), got)
+}
+
+func TestStripPNGMalformed(t *testing.T) {
+	data := pngWithMetadata(t)
+	corrupt := append([]byte{}, data...)
+	corrupt[len(pngSignature)+10] ^= 0xff // inside IHDR, so its CRC no longer matches
+
+	for name, bad := range map[string][]byte{
+		"no signature": data[1:],
+		"truncated":    data[:len(data)-3],
+		"bad crc":      corrupt,
+	} {
+		if _, err := Strip("image/png", bad); err != ErrMalformed {
+			t.Errorf("%s: err = %v, want ErrMalformed", name, err)
+		}
+	}
+}
+
+func riffChunk(fourCC string, payload []byte) []byte {
+	chunk := make([]byte, 8, 8+len(payload)+1)
+	copy(chunk, fourCC)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(payload)))
+	chunk = append(chunk, payload...)
+	if len(payload)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+// webpWithMetadata wraps a lossless WebP in the extended format with EXIF
+// and XMP chunks
+func webpWithMetadata(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := webp.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	simple := buf.Bytes()
+
+	vp8x := make([]byte, 10)
+	vp8x[0] = vp8xFlagEXIF | vp8xFlagXMP
+	w, h := testImage().Rect.Dx()-1, testImage().Rect.Dy()-1
+	vp8x[4], vp8x[5], vp8x[6] = byte(w), byte(w>>8), byte(w>>16)
+	vp8x[7], vp8x[8], vp8x[9] = byte(h), byte(h>>8), byte(h>>16)
+
+	body := []byte("WEBP")
+	body = append(body, riffChunk("VP8X", vp8x)...)
+	body = append(body, simple[12:]...) // the VP8L chunk
+	body = append(body, riffChunk("EXIF", exifPayload(binary.LittleEndian, 6)[6:])...)
+	body = append(body, riffChunk("XMP ", []byte("<x:xmpmeta>secret</x:xmpmeta>"))...)
+
+	out := []byte("RIFF\x00\x00\x00\x00")
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(body)))
+	return append(out, body...)
+}
+
+func TestStripWebP(t *testing.T) {
+	data := webpWithMetadata(t)
+	if _, err := xwebp.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatalf("fixture doesn't decode: %v", err)
+	}
+
+	stripped, err := Strip("image/webp", data)
+	if err != nil {
+		t.Fatalf("Strip: %v", err)
+	}
+	for _, s := range []string{"EXIF", "XMP ", "ACME", "secret"} {
+		if bytes.Contains(stripped, []byte(s)) {
+			t.Errorf("stripped WebP still contains %q", s)
+		}
+	}
+	if size := binary.LittleEndian.Uint32(stripped[4:]); int(size) != len(stripped)-8 {
+		t.Errorf("RIFF size = %d, want %d", size, len(stripped)-8)
+	}
+	if flags := stripped[20]; flags&(vp8xFlagEXIF|vp8xFlagXMP) != 0 {
+		t.Errorf("VP8X flags = %08b still announce metadata", flags)
+	}
+
+	got, err := xwebp.Decode(bytes.NewReader(stripped))
+	if err != nil {
+		t.Fatalf("decode stripped: %v", err)
+	}
+	assertSamePixels(t, testImage(), got)
+}
+
+func TestStripWebPMalformed(t *testing.T) {
+	data := webpWithMetadata(t)
+	for name, bad := range map[string][]byte{
+		"not riff":  append([]byte("RIFX"), data[4:]...),
+		"short":     data[:10],
+		"truncated": data[:len(data)-5],
+	} {
+		if _, err := Strip("image/webp", bad); err != ErrMalformed {
+			t.Errorf("%s: err = %v, want ErrMalformed", name, err)
+		}
+	}
+}
+
+func TestStripUnknownFormat(t *testing.T) {
+	data := []byte("GIF89a...")
+	got, err := Strip("image/gif", data)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("Strip(gif) = %q, %v; want the input unchanged", got, err)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 3x2 image whose pixels are numbered by their red channel:
+	//   1 2 3
+	//   4 5 6
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for i := 0; i < 6; i++ {
+		src.SetNRGBA(i%3, i/3, color.NRGBA{uint8(i + 1), 0, 0, 0xff})
+	}
+
+	tests := []struct {
+		orientation int
+		want        [][]uint8
+	}{
+		{1, [][]uint8{{1, 2, 3}, {4, 5, 6}}},
+		{2, [][]uint8{{3, 2, 1}, {6, 5, 4}}},
+		{3, [][]uint8{{6, 5, 4}, {3, 2, 1}}},
+		{4, [][]uint8{{4, 5, 6}, {1, 2, 3}}},
+		{5, [][]uint8{{1, 4}, {2, 5}, {3, 6}}},
+		{6, [][]uint8{{4, 1}, {5, 2}, {6, 3}}},
+		{7, [][]uint8{{6, 3}, {5, 2}, {4, 1}}},
+		{8, [][]uint8{{3, 6}, {2, 5}, {1, 4}}},
+		{9, [][]uint8{{1, 2, 3}, {4, 5, 6}}},
+	}
+	for _, tt := range tests {
+		got := Orient(src, tt.orientation)
+		b := got.Bounds()
+		if b.Dy() != len(tt.want) || b.Dx() != len(tt.want[0]) {
+			t.Errorf("orientation %d: %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), len(tt.want[0]), len(tt.want))
+			continue
+		}
+		for y, row := range tt.want {
+			for x, want := range row {
+				if r := color.NRGBAModel.Convert(got.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA).R; r != want {
+					t.Errorf("orientation %d: pixel (%d, %d) = %d, want %d", tt.orientation, x, y, r, want)
+				}
+			}
+		}
+	}
+}
+
+func assertSamePixels(t *testing.T, want, got image.Image) {
+	t.Helper()
+	wb, gb := want.Bounds(), got.Bounds()
+	if wb.Size() != gb.Size() {
+		t.Fatalf("size %v, want %v", gb.Size(), wb.Size())
+	}
+	for y := 0; y < wb.Dy(); y++ {
+		for x := 0; x < wb.Dx(); x++ {
+			w := color.NRGBAModel.Convert(want.At(wb.Min.X+x, wb.Min.Y+y))
+			g := color.NRGBAModel.Convert(got.At(gb.Min.X+x, gb.Min.Y+y))
+			if w != g {
+				t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
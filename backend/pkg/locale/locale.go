@@ -0,0 +1,113 @@
+// Package locale normalizes BCP 47 language tags and negotiates them
+// against an Accept-Language header.
+package locale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Canonical returns tag with a lower-case language and an upper-case region,
+// so "pt_br" and "PT-BR" both give "pt-BR". Tags that aren't a language of
+// two or three letters, optionally followed by subtags, give "".
+func Canonical(tag string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	if !isAlpha(parts[0]) || len(parts[0]) < 2 || len(parts[0]) > 3 {
+		return ""
+	}
+
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		part := parts[i]
+		switch {
+		case part == "" || len(part) > 8:
+			return ""
+		case len(part) == 2 && isAlpha(part):
+			parts[i] = strings.ToUpper(part) // Region
+		case len(part) == 4 && isAlpha(part):
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:]) // Script
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// Language returns the language subtag of a canonical tag, "pt" for "pt-BR"
+func Language(tag string) string {
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// Match picks the supported locale that best fits an Accept-Language header
+// (or a single tag), or "" if none does. Ranges are tried by quality; each
+// matches a supported locale exactly, then by its language, so "de-AT"
+// falls back to "de" and "de" takes "de-DE".
+func Match(acceptLanguage string, supported []string) string {
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		for _, s := range supported {
+			if s == tag {
+				return s
+			}
+		}
+		for _, s := range supported {
+			if Language(s) == Language(tag) {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+type weightedTag struct {
+	tag string
+	q   float64
+}
+
+// parseAcceptLanguage returns the canonical tags of the header, highest
+// quality first. "*" and ranges with q=0 are dropped.
+func parseAcceptLanguage(header string) []string {
+	var weighted []weightedTag
+	for _, field := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(field, ";")
+		tag = Canonical(tag)
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.ToLower(name) != "q" {
+				continue
+			}
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				q = v
+			}
+		}
+		if q > 0 {
+			weighted = append(weighted, weightedTag{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return weighted[i].q > weighted[j].q
+	})
+	tags := make([]string, len(weighted))
+	for i, w := range weighted {
+		tags[i] = w.tag
+	}
+	return tags
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
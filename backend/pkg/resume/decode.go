@@ -0,0 +1,39 @@
+package resume
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Formats Decode recognises
+const (
+	FormatJSONResume = "json_resume"
+	FormatLinkedIn   = "linkedin"
+)
+
+var ErrUnknownFormat = errors.New("not a JSON Resume document or LinkedIn data export")
+
+// Decode reads a JSON Resume document or a LinkedIn data export ZIP and
+// reports which of the two it was
+func Decode(data []byte) (*Resume, string, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		r, err := ParseLinkedIn(data)
+		return r, FormatLinkedIn, err
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, "", ErrUnknownFormat
+	}
+
+	var r Resume
+	if err := json.Unmarshal(trimmed, &r); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+	}
+	if r.Basics.Name == "" && len(r.Work) == 0 && len(r.Education) == 0 && len(r.Skills) == 0 {
+		return nil, "", fmt.Errorf("%w: no basics, work, education or skills", ErrUnknownFormat)
+	}
+	return &r, FormatJSONResume, nil
+}
@@ -0,0 +1,294 @@
+package resume
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Largest CSV read from an export, which bounds what a crafted ZIP can make
+// us decompress
+const maxLinkedInCSV = 8 << 20
+
+// linkedInExport is a LinkedIn data export, its CSVs by lower-case base name
+type linkedInExport map[string]*zip.File
+
+// ParseLinkedIn reads the profile, positions, education, skills,
+// certifications, honors and projects of a LinkedIn data export ZIP
+// ("Settings > Data privacy > Get a copy of your data"). Files missing from
+// the export leave their section empty.
+func ParseLinkedIn(data []byte) (*Resume, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+	}
+
+	export := linkedInExport{}
+	for _, f := range zr.File {
+		export[strings.ToLower(path.Base(f.Name))] = f
+	}
+	if export["profile.csv"] == nil && export["positions.csv"] == nil {
+		return nil, fmt.Errorf("%w: ZIP has no Profile.csv or Positions.csv", ErrUnknownFormat)
+	}
+
+	r := &Resume{Schema: SchemaURL}
+	steps := []func(*Resume) error{
+		export.profile,
+		export.contacts,
+		export.positions,
+		export.education,
+		export.skills,
+		export.certifications,
+		export.honors,
+		export.projects,
+	}
+	for _, step := range steps {
+		if err := step(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (e linkedInExport) profile(r *Resume) error {
+	rows, err := e.rows("Profile.csv", "First Name")
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	row := rows[0]
+
+	r.Basics.Name = joinNonEmpty(" ", row["First Name"], row["Last Name"])
+	r.Basics.Label = row["Headline"]
+	r.Basics.Summary = row["Summary"]
+
+	// Websites look like "[PERSONAL:https://example.com,BLOG:https://...]"
+	for _, site := range linkedInList(row["Websites"]) {
+		if i := strings.Index(site, ":"); i > 0 && !strings.HasPrefix(site[i:], "://") {
+			site = site[i+1:]
+		}
+		if r.Basics.URL == "" {
+			r.Basics.URL = site
+		}
+	}
+	for _, handle := range linkedInList(row["Twitter Handles"]) {
+		handle = strings.TrimPrefix(handle, "@")
+		r.Basics.Profiles = append(r.Basics.Profiles, Profile{
+			Network:  "Twitter",
+			Username: handle,
+			URL:      "https://twitter.com/" + handle,
+		})
+	}
+	return nil
+}
+
+// contacts takes the primary email address and the first phone number
+func (e linkedInExport) contacts(r *Resume) error {
+	emails, err := e.rows("Email Addresses.csv", "Email Address")
+	if err != nil {
+		return err
+	}
+	for _, row := range emails {
+		if r.Basics.Email == "" || strings.EqualFold(row["Primary"], "yes") {
+			r.Basics.Email = row["Email Address"]
+		}
+	}
+
+	phones, err := e.rows("PhoneNumbers.csv", "Number")
+	if err != nil {
+		return err
+	}
+	for _, row := range phones {
+		if row["Number"] != "" {
+			r.Basics.Phone = row["Number"]
+			break
+		}
+	}
+	return nil
+}
+
+func (e linkedInExport) positions(r *Resume) error {
+	rows, err := e.rows("Positions.csv", "Company Name")
+	for _, row := range rows {
+		summary, highlights := splitHighlights(row["Description"])
+		r.Work = append(r.Work, Work{
+			Name:       row["Company Name"],
+			Position:   row["Title"],
+			Location:   row["Location"],
+			StartDate:  linkedInDate(row["Started On"]),
+			EndDate:    linkedInDate(row["Finished On"]),
+			Summary:    summary,
+			Highlights: highlights,
+		})
+	}
+	return err
+}
+
+func (e linkedInExport) education(r *Resume) error {
+	rows, err := e.rows("Education.csv", "School Name")
+	for _, row := range rows {
+		r.Education = append(r.Education, Education{
+			Institution: row["School Name"],
+			StudyType:   row["Degree Name"],
+			StartDate:   linkedInDate(row["Start Date"]),
+			EndDate:     linkedInDate(row["End Date"]),
+		})
+	}
+	return err
+}
+
+func (e linkedInExport) skills(r *Resume) error {
+	rows, err := e.rows("Skills.csv", "Name")
+	for _, row := range rows {
+		r.Skills = append(r.Skills, Skill{Name: row["Name"]})
+	}
+	return err
+}
+
+func (e linkedInExport) certifications(r *Resume) error {
+	rows, err := e.rows("Certifications.csv", "Name")
+	for _, row := range rows {
+		r.Certificates = append(r.Certificates, Certificate{
+			Name:   row["Name"],
+			Date:   linkedInDate(row["Started On"]),
+			Issuer: row["Authority"],
+			URL:    row["Url"],
+		})
+	}
+	return err
+}
+
+func (e linkedInExport) honors(r *Resume) error {
+	rows, err := e.rows("Honors.csv", "Title")
+	for _, row := range rows {
+		r.Awards = append(r.Awards, Award{
+			Title:   row["Title"],
+			Date:    linkedInDate(row["Issued On"]),
+			Summary: row["Description"],
+		})
+	}
+	return err
+}
+
+func (e linkedInExport) projects(r *Resume) error {
+	rows, err := e.rows("Projects.csv", "Title")
+	for _, row := range rows {
+		summary, highlights := splitHighlights(row["Description"])
+		r.Projects = append(r.Projects, Project{
+			Name:        row["Title"],
+			Description: summary,
+			Highlights:  highlights,
+			URL:         row["Url"],
+			StartDate:   linkedInDate(row["Started On"]),
+			EndDate:     linkedInDate(row["Finished On"]),
+		})
+	}
+	return err
+}
+
+// rows reads a CSV of the export as maps of column to trimmed value. Some
+// exports start with a notes preamble, so the header is the first record
+// naming the key column. Rows with an empty key column are dropped, and a
+// missing file has no rows.
+func (e linkedInExport) rows(name, key string) ([]map[string]string, error) {
+	f := e[strings.ToLower(name)]
+	if f == nil {
+		return nil, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	cr := csv.NewReader(io.LimitReader(rc, maxLinkedInCSV))
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	var header []string
+	var rows []map[string]string
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+
+		if header == nil {
+			for i := range record {
+				record[i] = strings.TrimSpace(strings.TrimPrefix(record[i], "\ufeff"))
+			}
+			for _, column := range record {
+				if column == key {
+					header = record
+					break
+				}
+			}
+			continue
+		}
+
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				row[column] = strings.TrimSpace(record[i])
+			}
+		}
+		if row[key] != "" {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// linkedInDate turns the export's "Mar 2021", "2021" or "Mar 5, 2021" into
+// a JSON Resume date, or "" if it doesn't parse
+func linkedInDate(date string) string {
+	layouts := []struct{ in, out string }{
+		{"Jan 2006", "2006-01"},
+		{"January 2006", "2006-01"},
+		{"Jan 2, 2006", DateFormat},
+		{"2006", "2006"},
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout.in, date); err == nil {
+			return t.Format(layout.out)
+		}
+	}
+	return ""
+}
+
+// linkedInList splits the export's bracketed lists, "[a,b]"
+func linkedInList(value string) []string {
+	value = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "["), "]")
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitHighlights separates the bulleted lines of a description from the
+// rest, which stays the summary
+func splitHighlights(description string) (string, []string) {
+	var summary, highlights []string
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if bullet := strings.TrimLeft(line, "•-*·▪ "); bullet != line && bullet != "" {
+			highlights = append(highlights, bullet)
+			continue
+		}
+		summary = append(summary, line)
+	}
+	return strings.Join(summary, "\n"), highlights
+}
@@ -0,0 +1,454 @@
+package resume
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonobolditalic"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const ContentTypePDF = "application/pdf"
+
+// Millimetres per point, and the line height as a multiple of font size
+const (
+	ptToMM  = 0.3528
+	leading = 1.4
+)
+
+// Fractions of a full level meter, by the skill levels the backend uses
+var skillLevels = map[string]float64{
+	"beginner":     0.25,
+	"intermediate": 0.5,
+	"advanced":     0.75,
+	"expert":       1,
+	"master":       1,
+}
+
+var sectionTitles = map[string]string{
+	SectionSummary:      "Summary",
+	SectionWork:         "Experience",
+	SectionEducation:    "Education",
+	SectionSkills:       "Skills",
+	SectionProjects:     "Projects",
+	SectionCertificates: "Certifications",
+	SectionAwards:       "Awards",
+}
+
+// RenderPDF lays the resume out on A4 pages with the theme. The document
+// dates come from Meta.LastModified rather than the clock.
+func RenderPDF(r *Resume, theme Theme) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	registerFonts(pdf)
+	pdf.SetCompression(true)
+	pdf.SetCatalogSort(true)
+	modified := ParseDate(metaModified(r))
+	if modified.IsZero() {
+		modified = time.Unix(0, 0).UTC()
+	}
+	pdf.SetCreationDate(modified)
+	pdf.SetModificationDate(modified)
+	pdf.SetTitle(strings.TrimSpace(r.Basics.Name+" Resume"), true)
+	pdf.SetAuthor(r.Basics.Name, true)
+	pdf.SetCreator("portfolio-backend", true)
+
+	pdf.SetMargins(theme.Margin, theme.Margin, theme.Margin)
+	pdf.SetAutoPageBreak(true, theme.Margin)
+	pdf.AliasNbPages("")
+
+	p := &renderer{pdf: pdf, theme: theme}
+	pdf.SetFooterFunc(p.footer)
+	pdf.AddPage()
+
+	p.header(&r.Basics)
+	for _, section := range theme.Sections {
+		p.section(r, section)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render resume pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func registerFonts(pdf *gofpdf.Fpdf) {
+	pdf.AddUTF8FontFromBytes(FontSans, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(FontSans, "B", gobold.TTF)
+	pdf.AddUTF8FontFromBytes(FontSans, "I", goitalic.TTF)
+	pdf.AddUTF8FontFromBytes(FontSans, "BI", gobolditalic.TTF)
+	pdf.AddUTF8FontFromBytes(FontMono, "", gomono.TTF)
+	pdf.AddUTF8FontFromBytes(FontMono, "B", gomonobold.TTF)
+	pdf.AddUTF8FontFromBytes(FontMono, "I", gomonoitalic.TTF)
+	pdf.AddUTF8FontFromBytes(FontMono, "BI", gomonobolditalic.TTF)
+}
+
+type renderer struct {
+	pdf   *gofpdf.Fpdf
+	theme Theme
+}
+
+// width is the printable width of the page
+func (p *renderer) width() float64 {
+	pageWidth, _ := p.pdf.GetPageSize()
+	left, _, right, _ := p.pdf.GetMargins()
+	return pageWidth - left - right
+}
+
+func (p *renderer) font(style string, size float64, c Color) {
+	p.pdf.SetFont(p.theme.Font, style, size)
+	p.pdf.SetTextColor(c.R, c.G, c.B)
+}
+
+func lineHeight(size float64) float64 {
+	return size * ptToMM * leading
+}
+
+// ensureSpace starts a new page unless h mm are left on this one, so an
+// entry's heading isn't stranded at the bottom
+func (p *renderer) ensureSpace(h float64) {
+	_, pageHeight := p.pdf.GetPageSize()
+	_, _, _, bottom := p.pdf.GetMargins()
+	if p.pdf.GetY()+h > pageHeight-bottom {
+		p.pdf.AddPage()
+	}
+}
+
+func (p *renderer) header(b *Basics) {
+	t := p.theme
+	left, top, _, _ := p.pdf.GetMargins()
+	contacts := contactLinks(b)
+
+	align := "C"
+	if t.Header == HeaderBanner {
+		align = "L"
+		// The band runs to the page edges behind the header text
+		pageWidth, _ := p.pdf.GetPageSize()
+		height := top + lineHeight(t.NameSize) + lineHeight(t.BodySize)*2 + 8
+		p.pdf.SetFillColor(t.Accent.R, t.Accent.G, t.Accent.B)
+		p.pdf.Rect(0, 0, pageWidth, height, "F")
+		p.pdf.SetY(top + 2)
+	}
+
+	text, muted := t.Text, t.Muted
+	if t.Header == HeaderBanner {
+		text, muted = Color{255, 255, 255}, Color{230, 236, 255}
+	}
+
+	p.font("B", t.NameSize, text)
+	p.pdf.CellFormat(0, lineHeight(t.NameSize), b.Name, "", 1, align, false, 0, "")
+	if b.Label != "" {
+		p.font("", t.BodySize+2, muted)
+		p.pdf.CellFormat(0, lineHeight(t.BodySize+2), b.Label, "", 1, align, false, 0, "")
+	}
+
+	if len(contacts) > 0 {
+		p.font("", t.BodySize, muted)
+		separator := "  ·  "
+		total := 0.0
+		for i, c := range contacts {
+			if i > 0 {
+				total += p.pdf.GetStringWidth(separator)
+			}
+			total += p.pdf.GetStringWidth(c.text)
+		}
+		x := left
+		if align == "C" && total < p.width() {
+			x = left + (p.width()-total)/2
+		}
+		p.pdf.SetX(x)
+		h := lineHeight(t.BodySize)
+		for i, c := range contacts {
+			if i > 0 {
+				p.pdf.Write(h, separator)
+			}
+			if c.url != "" {
+				p.pdf.WriteLinkString(h, c.text, c.url)
+			} else {
+				p.pdf.Write(h, c.text)
+			}
+		}
+		p.pdf.Ln(h)
+	}
+
+	if t.Header == HeaderBanner {
+		p.pdf.Ln(8)
+	} else {
+		p.pdf.Ln(2)
+		if t.HeadingRule {
+			p.rule(t.Text)
+		}
+		p.pdf.Ln(2)
+	}
+}
+
+type contact struct {
+	text string
+	url  string
+}
+
+// contactLinks lists the ways to reach the person, shown without schemes
+func contactLinks(b *Basics) []contact {
+	var contacts []contact
+	if b.Email != "" {
+		contacts = append(contacts, contact{b.Email, "mailto:" + b.Email})
+	}
+	if b.Phone != "" {
+		contacts = append(contacts, contact{b.Phone, "tel:" + strings.ReplaceAll(b.Phone, " ", "")})
+	}
+	if b.Location != nil && b.Location.City != "" {
+		place := b.Location.City
+		if b.Location.CountryCode != "" {
+			place += ", " + b.Location.CountryCode
+		}
+		contacts = append(contacts, contact{text: place})
+	}
+	if b.URL != "" {
+		contacts = append(contacts, contact{displayURL(b.URL), b.URL})
+	}
+	for _, profile := range b.Profiles {
+		if profile.URL != "" {
+			contacts = append(contacts, contact{displayURL(profile.URL), profile.URL})
+		}
+	}
+	return contacts
+}
+
+func displayURL(u string) string {
+	u = strings.TrimPrefix(strings.TrimPrefix(u, "https://"), "http://")
+	return strings.TrimSuffix(strings.TrimPrefix(u, "www."), "/")
+}
+
+func (p *renderer) rule(c Color) {
+	left, _, right, _ := p.pdf.GetMargins()
+	pageWidth, _ := p.pdf.GetPageSize()
+	y := p.pdf.GetY()
+	p.pdf.SetDrawColor(c.R, c.G, c.B)
+	p.pdf.SetLineWidth(0.3)
+	p.pdf.Line(left, y, pageWidth-right, y)
+}
+
+func (p *renderer) heading(section string) {
+	t := p.theme
+	title := sectionTitles[section]
+	if t.HeadingCaps {
+		title = strings.ToUpper(title)
+	}
+
+	p.ensureSpace(lineHeight(t.HeadingSize) + lineHeight(t.BodySize)*3)
+	p.pdf.Ln(3)
+	p.font("B", t.HeadingSize, t.Accent)
+	p.pdf.CellFormat(0, lineHeight(t.HeadingSize), title, "", 1, "L", false, 0, "")
+	if t.HeadingRule {
+		p.rule(t.Accent)
+		p.pdf.Ln(1.5)
+	}
+}
+
+func (p *renderer) section(r *Resume, section string) {
+	switch section {
+	case SectionSummary:
+		if r.Basics.Summary != "" {
+			p.heading(section)
+			p.paragraph(r.Basics.Summary)
+		}
+	case SectionWork:
+		if len(r.Work) > 0 {
+			p.heading(section)
+			for _, w := range r.Work {
+				subtitle := joinNonEmpty(", ", w.Name, w.Location)
+				p.entry(w.Position, w.URL, subtitle, dateRange(w.StartDate, w.EndDate), w.Summary, w.Highlights)
+			}
+		}
+	case SectionEducation:
+		if len(r.Education) > 0 {
+			p.heading(section)
+			for _, e := range r.Education {
+				title := joinNonEmpty(", ", e.StudyType, e.Area)
+				if title == "" {
+					title = e.Institution
+				}
+				subtitle := e.Institution
+				if subtitle == title {
+					subtitle = ""
+				}
+				if e.Score != "" {
+					subtitle = joinNonEmpty(" · ", subtitle, e.Score)
+				}
+				p.entry(title, e.URL, subtitle, dateRange(e.StartDate, e.EndDate), "", e.Courses)
+			}
+		}
+	case SectionSkills:
+		if len(r.Skills) > 0 {
+			p.heading(section)
+			p.skills(r.Skills)
+		}
+	case SectionProjects:
+		if len(r.Projects) > 0 {
+			p.heading(section)
+			for _, project := range r.Projects {
+				p.entry(project.Name, project.URL, strings.Join(project.Keywords, ", "), dateRange(project.StartDate, project.EndDate), project.Description, project.Highlights)
+			}
+		}
+	case SectionCertificates:
+		if len(r.Certificates) > 0 {
+			p.heading(section)
+			for _, c := range r.Certificates {
+				p.entry(c.Name, c.URL, c.Issuer, formatDate(c.Date), "", nil)
+			}
+		}
+	case SectionAwards:
+		if len(r.Awards) > 0 {
+			p.heading(section)
+			for _, a := range r.Awards {
+				p.entry(a.Title, "", a.Awarder, formatDate(a.Date), a.Summary, nil)
+			}
+		}
+	}
+}
+
+// entry prints a titled item: title and dates on one line, a muted
+// subtitle under them, then a summary and bullet points
+func (p *renderer) entry(title, link, subtitle, dates, summary string, bullets []string) {
+	t := p.theme
+	h := lineHeight(t.BodySize)
+	p.ensureSpace(h * 3)
+	p.pdf.Ln(1)
+
+	datesWidth := 0.0
+	if dates != "" {
+		p.font("", t.BodySize, t.Muted)
+		datesWidth = p.pdf.GetStringWidth(dates) + 2
+	}
+	p.font("B", t.BodySize+1, t.Text)
+	p.pdf.CellFormat(p.width()-datesWidth, lineHeight(t.BodySize+1), title, "", 0, "L", false, 0, link)
+	p.font("", t.BodySize, t.Muted)
+	p.pdf.CellFormat(datesWidth, lineHeight(t.BodySize+1), dates, "", 1, "R", false, 0, "")
+
+	if subtitle != "" {
+		p.font("I", t.BodySize, t.Muted)
+		p.pdf.MultiCell(0, h, subtitle, "", "L", false)
+	}
+	if summary != "" {
+		p.paragraph(summary)
+	}
+	for _, bullet := range bullets {
+		p.bullet(bullet)
+	}
+}
+
+func (p *renderer) paragraph(text string) {
+	p.font("", p.theme.BodySize, p.theme.Text)
+	p.pdf.MultiCell(0, lineHeight(p.theme.BodySize), text, "", "L", false)
+}
+
+func (p *renderer) bullet(text string) {
+	left, _, _, _ := p.pdf.GetMargins()
+	h := lineHeight(p.theme.BodySize)
+	p.font("", p.theme.BodySize, p.theme.Accent)
+	p.pdf.SetX(left + 2)
+	p.pdf.CellFormat(4, h, "•", "", 0, "L", false, 0, "")
+	p.font("", p.theme.BodySize, p.theme.Text)
+	p.pdf.MultiCell(p.width()-6, h, text, "", "L", false)
+}
+
+func (p *renderer) skills(skills []Skill) {
+	t := p.theme
+	if t.Skills != SkillsBars {
+		parts := make([]string, len(skills))
+		for i, s := range skills {
+			parts[i] = s.Name
+			if s.Level != "" {
+				parts[i] += " (" + s.Level + ")"
+			}
+		}
+		p.paragraph(strings.Join(parts, "  ·  "))
+		return
+	}
+
+	// Two columns of name, level meter
+	h := lineHeight(t.BodySize) + 0.5
+	left, _, _, _ := p.pdf.GetMargins()
+	column := p.width() / 2
+	meter := 24.0
+	for i, s := range skills {
+		x := left + float64(i%2)*column
+		if i%2 == 0 {
+			p.ensureSpace(h)
+		}
+		y := p.pdf.GetY()
+		p.pdf.SetXY(x, y)
+		p.font("", t.BodySize, t.Text)
+		p.pdf.CellFormat(column-meter-4, h, s.Name, "", 0, "L", false, 0, "")
+
+		if level, ok := skillLevels[strings.ToLower(s.Level)]; ok {
+			mx, my := x+column-meter-4, y+h/2-1
+			p.pdf.SetFillColor(225, 228, 232)
+			p.pdf.Rect(mx, my, meter, 2, "F")
+			p.pdf.SetFillColor(t.Accent.R, t.Accent.G, t.Accent.B)
+			p.pdf.Rect(mx, my, meter*level, 2, "F")
+		}
+		if i%2 == 1 || i == len(skills)-1 {
+			p.pdf.SetXY(left, y+h)
+		}
+	}
+}
+
+func (p *renderer) footer() {
+	t := p.theme
+	_, pageHeight := p.pdf.GetPageSize()
+	p.pdf.SetY(pageHeight - t.Margin + 4)
+	p.font("", t.BodySize-1.5, t.Muted)
+	p.pdf.CellFormat(0, lineHeight(t.BodySize-1.5), fmt.Sprintf("%d / {nb}", p.pdf.PageNo()), "", 0, "R", false, 0, "")
+}
+
+// dateRange formats a period such as "Mar 2021 – Present"
+func dateRange(start, end string) string {
+	if start == "" {
+		return formatDate(end)
+	}
+	if end == "" {
+		return formatDate(start) + " – Present"
+	}
+	if formatDate(start) == formatDate(end) {
+		return formatDate(start)
+	}
+	return formatDate(start) + " – " + formatDate(end)
+}
+
+// formatDate shows an ISO 8601 date as month and year, or as given if it
+// doesn't parse
+func formatDate(date string) string {
+	if t, err := time.Parse("2006", date); err == nil {
+		return t.Format("2006")
+	}
+	if t := ParseDate(date); !t.IsZero() {
+		return t.Format("Jan 2006")
+	}
+	return date
+}
+
+func metaModified(r *Resume) string {
+	if r.Meta == nil {
+		return ""
+	}
+	return r.Meta.LastModified
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := parts[:0:0]
+	for _, part := range parts {
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
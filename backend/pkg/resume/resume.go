@@ -0,0 +1,131 @@
+// Package resume describes a CV in the JSON Resume schema
+// (https://jsonresume.org/schema) and renders it as PDF.
+package resume
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	SchemaURL     = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+	SchemaVersion = "v1.0.0"
+)
+
+// DateFormat is the ISO 8601 layout JSON Resume dates are written in. The
+// schema also allows "2006-01" and "2006".
+const DateFormat = "2006-01-02"
+
+// ParseDate reads the date layouts JSON Resume allows, returning the zero
+// time for anything else
+func ParseDate(date string) time.Time {
+	for _, layout := range []string{DateFormat, "2006-01", "2006", time.RFC3339} {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Resume is a JSON Resume document. Dates are strings in DateFormat and an
+// empty end date means ongoing.
+type Resume struct {
+	Schema       string        `json:"$schema,omitempty"`
+	Basics       Basics        `json:"basics"`
+	Work         []Work        `json:"work,omitempty"`
+	Education    []Education   `json:"education,omitempty"`
+	Awards       []Award       `json:"awards,omitempty"`
+	Certificates []Certificate `json:"certificates,omitempty"`
+	Skills       []Skill       `json:"skills,omitempty"`
+	Projects     []Project     `json:"projects,omitempty"`
+	Meta         *Meta         `json:"meta,omitempty"`
+}
+
+type Basics struct {
+	Name     string    `json:"name"`
+	Label    string    `json:"label,omitempty"`
+	Image    string    `json:"image,omitempty"`
+	Email    string    `json:"email,omitempty"`
+	Phone    string    `json:"phone,omitempty"`
+	URL      string    `json:"url,omitempty"`
+	Summary  string    `json:"summary,omitempty"`
+	Location *Location `json:"location,omitempty"`
+	Profiles []Profile `json:"profiles,omitempty"`
+}
+
+type Location struct {
+	Address     string `json:"address,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+	Region      string `json:"region,omitempty"`
+}
+
+type Profile struct {
+	Network  string `json:"network"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type Work struct {
+	Name       string   `json:"name"`
+	Position   string   `json:"position,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+type Education struct {
+	Institution string   `json:"institution"`
+	URL         string   `json:"url,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+}
+
+type Award struct {
+	Title   string `json:"title"`
+	Date    string `json:"date,omitempty"`
+	Awarder string `json:"awarder,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type Certificate struct {
+	Name   string `json:"name"`
+	Date   string `json:"date,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+type Skill struct {
+	Name     string   `json:"name"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+type Project struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+}
+
+type Meta struct {
+	Canonical    string `json:"canonical,omitempty"`
+	Version      string `json:"version,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// Encode renders the resume as indented JSON
+func (r *Resume) Encode() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
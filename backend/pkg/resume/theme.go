@@ -0,0 +1,109 @@
+package resume
+
+// Sections a theme can lay out
+const (
+	SectionSummary      = "summary"
+	SectionWork         = "work"
+	SectionEducation    = "education"
+	SectionSkills       = "skills"
+	SectionProjects     = "projects"
+	SectionCertificates = "certificates"
+	SectionAwards       = "awards"
+)
+
+// Header layouts
+const (
+	HeaderCentered = "centered" // Name and contacts centered above a rule
+	HeaderBanner   = "banner"   // Name and contacts on a full-width accent band
+)
+
+// Skill layouts
+const (
+	SkillsInline = "inline" // One paragraph, "Go (Expert) · SQL (Advanced)"
+	SkillsBars   = "bars"   // A row per skill with a level meter
+)
+
+type Color struct {
+	R, G, B int
+}
+
+// Theme is the template a PDF is laid out by. Everything that differs
+// between themes lives here; the renderer has no per-theme code.
+type Theme struct {
+	Name     string
+	Sections []string // Printed in this order; sections with no entries are skipped
+	Header   string
+	Skills   string
+	Font     string  // Family from the fonts embedded by the renderer
+	Margin   float64 // mm
+
+	NameSize    float64 // pt
+	HeadingSize float64
+	BodySize    float64
+
+	Text   Color
+	Muted  Color
+	Accent Color
+	// Draw a rule under each section heading
+	HeadingRule bool
+	// Print section headings in capitals
+	HeadingCaps bool
+}
+
+// Fonts embedded in every PDF; all are Go fonts, which cover Latin,
+// Greek and Cyrillic
+const (
+	FontSans = "go"
+	FontMono = "gomono"
+)
+
+const DefaultTheme = "classic"
+
+// Themes available for PDF output, by name
+var Themes = map[string]Theme{
+	"classic": {
+		Name:        "classic",
+		Sections:    []string{SectionSummary, SectionWork, SectionEducation, SectionSkills, SectionProjects, SectionCertificates, SectionAwards},
+		Header:      HeaderCentered,
+		Skills:      SkillsInline,
+		Font:        FontSans,
+		Margin:      18,
+		NameSize:    24,
+		HeadingSize: 12,
+		BodySize:    9.5,
+		Text:        Color{20, 20, 20},
+		Muted:       Color{95, 95, 95},
+		Accent:      Color{20, 20, 20},
+		HeadingRule: true,
+		HeadingCaps: true,
+	},
+	"modern": {
+		Name:        "modern",
+		Sections:    []string{SectionSummary, SectionSkills, SectionWork, SectionProjects, SectionEducation, SectionCertificates, SectionAwards},
+		Header:      HeaderBanner,
+		Skills:      SkillsBars,
+		Font:        FontSans,
+		Margin:      16,
+		NameSize:    26,
+		HeadingSize: 13,
+		BodySize:    9.5,
+		Text:        Color{33, 37, 41},
+		Muted:       Color{108, 117, 125},
+		Accent:      Color{37, 99, 235},
+	},
+	"mono": {
+		Name:        "mono",
+		Sections:    []string{SectionSummary, SectionWork, SectionProjects, SectionSkills, SectionEducation, SectionCertificates, SectionAwards},
+		Header:      HeaderCentered,
+		Skills:      SkillsBars,
+		Font:        FontMono,
+		Margin:      16,
+		NameSize:    20,
+		HeadingSize: 11,
+		BodySize:    8.5,
+		Text:        Color{0, 0, 0},
+		Muted:       Color{90, 90, 90},
+		Accent:      Color{0, 128, 96},
+		HeadingRule: true,
+	},
+}
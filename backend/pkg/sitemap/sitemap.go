@@ -0,0 +1,95 @@
+// Package sitemap encodes sitemaps and sitemap indexes per sitemaps.org 0.9.
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs is the protocol limit on entries in one sitemap or index file
+const MaxURLs = 50000
+
+const (
+	namespace      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	xhtmlNamespace = "http://www.w3.org/1999/xhtml"
+)
+
+// XDefault is the hreflang of the page for languages without their own
+const XDefault = "x-default"
+
+type URL struct {
+	Loc     string
+	LastMod time.Time
+	// The page in every language, Loc's included, linked with hreflang
+	Alternates []Alternate
+}
+
+type Alternate struct {
+	Language string
+	Loc      string
+}
+
+type urlSet struct {
+	XMLName xml.Name  `xml:"urlset"`
+	NS      string    `xml:"xmlns,attr"`
+	XHTMLNS string    `xml:"xmlns:xhtml,attr,omitempty"`
+	URLs    []xmlLink `xml:"url"`
+}
+
+type index struct {
+	XMLName  xml.Name  `xml:"sitemapindex"`
+	NS       string    `xml:"xmlns,attr"`
+	Sitemaps []xmlLink `xml:"sitemap"`
+}
+
+type xmlLink struct {
+	Loc        string      `xml:"loc"`
+	LastMod    string      `xml:"lastmod,omitempty"`
+	Alternates []xhtmlLink `xml:"xhtml:link"`
+}
+
+type xhtmlLink struct {
+	Rel      string `xml:"rel,attr"`
+	HrefLang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+// EncodeURLSet renders a <urlset> document, declaring the xhtml namespace
+// when any URL has alternates
+func EncodeURLSet(urls []URL) ([]byte, error) {
+	set := urlSet{NS: namespace, URLs: links(urls)}
+	for _, u := range set.URLs {
+		if len(u.Alternates) > 0 {
+			set.XHTMLNS = xhtmlNamespace
+			break
+		}
+	}
+	return marshal(set)
+}
+
+// EncodeIndex renders a <sitemapindex> pointing at the given sitemaps
+func EncodeIndex(sitemaps []URL) ([]byte, error) {
+	return marshal(index{NS: namespace, Sitemaps: links(sitemaps)})
+}
+
+func links(urls []URL) []xmlLink {
+	out := make([]xmlLink, len(urls))
+	for i, u := range urls {
+		out[i] = xmlLink{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			out[i].LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		for _, a := range u.Alternates {
+			out[i].Alternates = append(out[i].Alternates, xhtmlLink{Rel: "alternate", HrefLang: a.Language, Href: a.Loc})
+		}
+	}
+	return out
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
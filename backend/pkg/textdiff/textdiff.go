@@ -0,0 +1,198 @@
+// Package textdiff computes line-oriented diffs using Myers' O(ND) algorithm.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operation kinds
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Line is one line of a diff. OldLine/NewLine are 1-based and zero when the
+// line does not exist on that side.
+type Line struct {
+	Op      string `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+}
+
+// Stats summarises a diff
+type Stats struct {
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+}
+
+// Lines diffs a and b line by line
+func Lines(a, b string) []Line {
+	return diff(splitLines(a), splitLines(b))
+}
+
+// Summarize counts inserted and deleted lines
+func Summarize(lines []Line) Stats {
+	var s Stats
+	for _, l := range lines {
+		switch l.Op {
+		case OpInsert:
+			s.Insertions++
+		case OpDelete:
+			s.Deletions++
+		}
+	}
+	return s
+}
+
+// Unified renders the diff in unified format with the given context lines
+func Unified(lines []Line, context int) string {
+	var b strings.Builder
+	for start := 0; start < len(lines); {
+		// Find the next change
+		for start < len(lines) && lines[start].Op == OpEqual {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context of each other
+		from := max(start-context, 0)
+		end := start
+		for end < len(lines) {
+			if lines[end].Op != OpEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].Op == OpEqual {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		to := min(end+context, len(lines))
+
+		oldStart, newStart, oldCount, newCount := hunkRange(lines[from:to])
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, l := range lines[from:to] {
+			switch l.Op {
+			case OpEqual:
+				b.WriteString(" ")
+			case OpInsert:
+				b.WriteString("+")
+			case OpDelete:
+				b.WriteString("-")
+			}
+			b.WriteString(l.Text)
+			b.WriteString("\n")
+		}
+		start = to
+	}
+	return b.String()
+}
+
+func hunkRange(lines []Line) (oldStart, newStart, oldCount, newCount int) {
+	for _, l := range lines {
+		if l.OldLine > 0 {
+			if oldStart == 0 {
+				oldStart = l.OldLine
+			}
+			oldCount++
+		}
+		if l.NewLine > 0 {
+			if newStart == 0 {
+				newStart = l.NewLine
+			}
+			newCount++
+		}
+	}
+	return
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(s, "\r\n", "\n"), "\n"), "\n")
+}
+
+// diff runs the greedy Myers search, keeping the frontier of every round so
+// the edit script can be recovered by walking the trace backwards. Round d
+// only ever reads diagonals -d-1..d+1, so that window is all we snapshot.
+func diff(a, b []string) []Line {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+search:
+	for d := 0; d <= maxD; d++ {
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Backtrack from (n, m) to (0, 0)
+	var reversed []Line
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, Line{Op: OpEqual, Text: a[x-1], OldLine: x, NewLine: y})
+			x--
+			y--
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			reversed = append(reversed, Line{Op: OpInsert, Text: b[y-1], NewLine: y})
+		} else {
+			reversed = append(reversed, Line{Op: OpDelete, Text: a[x-1], OldLine: x})
+		}
+		x, y = prevX, prevY
+	}
+
+	lines := make([]Line, len(reversed))
+	for i, l := range reversed {
+		lines[len(reversed)-1-i] = l
+	}
+	return lines
+}
@@ -0,0 +1,218 @@
+package textdiff
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func eq(text string, oldLine, newLine int) Line {
+	return Line{Op: OpEqual, Text: text, OldLine: oldLine, NewLine: newLine}
+}
+
+func ins(text string, newLine int) Line {
+	return Line{Op: OpInsert, Text: text, NewLine: newLine}
+}
+
+func del(text string, oldLine int) Line {
+	return Line{Op: OpDelete, Text: text, OldLine: oldLine}
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Line
+	}{
+		{name: "both empty", a: "", b: ""},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "one\ntwo\n",
+			want: []Line{ins("one", 1), ins("two", 2)},
+		},
+		{
+			name: "to empty",
+			a:    "one\ntwo",
+			b:    "",
+			want: []Line{del("one", 1), del("two", 2)},
+		},
+		{
+			name: "identical",
+			a:    "one\ntwo\nthree\n",
+			b:    "one\ntwo\nthree\n",
+			want: []Line{eq("one", 1, 1), eq("two", 2, 2), eq("three", 3, 3)},
+		},
+		{
+			name: "line endings and final newline don't count",
+			a:    "one\r\ntwo\r\n",
+			b:    "one\ntwo",
+			want: []Line{eq("one", 1, 1), eq("two", 2, 2)},
+		},
+		{
+			name: "insert only",
+			a:    "one\nthree",
+			b:    "zero\none\ntwo\nthree\nfour",
+			want: []Line{ins("zero", 1), eq("one", 1, 2), ins("two", 3), eq("three", 2, 4), ins("four", 5)},
+		},
+		{
+			name: "delete only",
+			a:    "zero\none\ntwo\nthree\nfour",
+			b:    "one\nthree",
+			want: []Line{del("zero", 1), eq("one", 2, 1), del("two", 3), eq("three", 4, 2), del("four", 5)},
+		},
+		{
+			name: "replace a line",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			want: []Line{eq("one", 1, 1), del("two", 2), ins("2", 2), eq("three", 3, 3)},
+		},
+		{
+			name: "mixed",
+			a:    "# Title\nintro\nold paragraph\nshared\nend",
+			b:    "# New title\nintro\nshared\nnew paragraph\nend",
+			want: []Line{
+				del("# Title", 1), ins("# New title", 1),
+				eq("intro", 2, 2),
+				del("old paragraph", 3),
+				eq("shared", 4, 3),
+				ins("new paragraph", 4),
+				eq("end", 5, 5),
+			},
+		},
+		{
+			name: "blank lines are lines",
+			a:    "a\n\nb",
+			b:    "a\nb",
+			want: []Line{eq("a", 1, 1), del("", 2), eq("b", 3, 2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lines(tt.a, tt.b)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+// lcs is the length of the longest common subsequence, by dynamic programming
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// Random edits must reproduce both sides, number every line in order and be
+// as short as possible
+func TestLinesRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	words := []string{"a", "b", "c", "d"}
+	randomText := func() []string {
+		lines := make([]string, r.Intn(12))
+		for i := range lines {
+			lines[i] = words[r.Intn(len(words))]
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomText(), randomText()
+		got := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+		var oldSide, newSide []string
+		for _, l := range got {
+			if l.Op != OpInsert {
+				oldSide = append(oldSide, l.Text)
+				if l.OldLine != len(oldSide) {
+					t.Fatalf("%q -> %q: line %+v has old number %d, want %d", a, b, l, l.OldLine, len(oldSide))
+				}
+			}
+			if l.Op != OpDelete {
+				newSide = append(newSide, l.Text)
+				if l.NewLine != len(newSide) {
+					t.Fatalf("%q -> %q: line %+v has new number %d, want %d", a, b, l, l.NewLine, len(newSide))
+				}
+			}
+		}
+		if strings.Join(oldSide, "\n") != strings.Join(a, "\n") || strings.Join(newSide, "\n") != strings.Join(b, "\n") {
+			t.Fatalf("%q -> %q: diff reproduces %q -> %q", a, b, oldSide, newSide)
+		}
+
+		stats := Summarize(got)
+		if edits, want := stats.Insertions+stats.Deletions, len(a)+len(b)-2*lcs(a, b); edits != want {
+			t.Fatalf("%q -> %q: %d edits, want the minimum %d", a, b, edits, want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	got := Summarize(Lines("a\nb\nc\nd", "a\nx\nc\ny\nz"))
+	if want := (Stats{Insertions: 3, Deletions: 2}); got != want {
+		t.Errorf("Summarize = %+v, want %+v", got, want)
+	}
+	if got := Summarize(nil); got != (Stats{}) {
+		t.Errorf("Summarize(nil) = %+v", got)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13"
+
+	want := "" +
+		"@@ -2,3 +2,3 @@\n" +
+		" 2\n" +
+		"-3\n" +
+		"+three\n" +
+		" 4\n" +
+		"@@ -12,1 +12,2 @@\n" +
+		" 12\n" +
+		"+13\n"
+	if got := Unified(Lines(a, b), 1); got != want {
+		t.Errorf("Unified =\n%s\nwant\n%s", got, want)
+	}
+
+	// Changes within twice the context share a hunk
+	want = "" +
+		"@@ -1,12 +1,13 @@\n" +
+		" 1\n" +
+		" 2\n" +
+		"-3\n" +
+		"+three\n" +
+		" 4\n" +
+		" 5\n" +
+		" 6\n" +
+		" 7\n" +
+		" 8\n" +
+		" 9\n" +
+		" 10\n" +
+		" 11\n" +
+		" 12\n" +
+		"+13\n"
+	if got := Unified(Lines(a, b), 5); got != want {
+		t.Errorf("Unified with context 5 =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Unified(Lines(a, a), 3); got != "" {
+		t.Errorf("Unified of identical texts = %q, want empty", got)
+	}
+}
@@ -0,0 +1,438 @@
+// Package webp encodes images as lossless WebP (VP8L).
+//
+// The encoder is deliberately small: it applies the subtract-green and
+// predictor transforms, finds LZ77 backward references against the previous
+// pixel, the row above and a hash of recent pixels, then Huffman-codes the
+// result. Output is comparable to PNG and decodes in every browser that
+// supports WebP.
+package webp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+)
+
+// MaxDimension is the largest width or height VP8L can describe
+const MaxDimension = 1 << 14
+
+var ErrTooLarge = errors.New("webp: image dimensions out of range")
+
+const (
+	numLiterals      = 256
+	numLengthCodes   = 24
+	numDistanceCodes = 40
+	maxCodeLength    = 15
+
+	// Code length codes are stored in 3 bits each
+	maxCodeLengthCodeLength = 7
+
+	minMatch               = 3
+	maxMatch               = 4096
+	hashBits               = 16
+	transformPredictor     = 0
+	transformSubtractGreen = 2
+
+	// Predictor modes are chosen per 16x16 tile
+	predictorBits = 4
+)
+
+// Predictor modes tried for each tile. Modes that read the top-right pixel
+// are skipped; they wrap around at the right edge.
+var predictorModes = []int{1, 2, 7, 11, 12, 13}
+
+// Order in which code length code lengths are written (VP8L spec 3.7.2.1.2)
+var codeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// symbol is one entropy-coded token: either a literal ARGB pixel or a
+// backward reference of length pixels to distance code dist
+type symbol struct {
+	argb   uint32
+	length int
+	dist   int
+}
+
+// Encode writes m to w as a lossless WebP file
+func Encode(w io.Writer, m image.Image) error {
+	b := m.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > MaxDimension || height > MaxDimension {
+		return ErrTooLarge
+	}
+
+	pixels, opaque := argbPixels(m)
+
+	bw := &bitWriter{}
+	bw.writeBits(0x2f, 8)
+	bw.writeBits(uint32(width-1), 14)
+	bw.writeBits(uint32(height-1), 14)
+	if opaque {
+		bw.writeBits(0, 1)
+	} else {
+		bw.writeBits(1, 1)
+	}
+	bw.writeBits(0, 3) // version
+
+	// Transforms are listed in the order the encoder applies them; the
+	// decoder undoes them in reverse
+	bw.writeBits(1, 1)
+	bw.writeBits(transformSubtractGreen, 2)
+
+	modes, residuals := predict(pixels, width, height)
+	bw.writeBits(1, 1)
+	bw.writeBits(transformPredictor, 2)
+	bw.writeBits(predictorBits-2, 3)
+	writeEntropyImage(bw, modes, tiles(width))
+
+	bw.writeBits(0, 1) // no further transforms
+	bw.writeBits(0, 1) // no color cache
+	bw.writeBits(0, 1) // a single prefix code group for the whole image
+	writeImageData(bw, backwardReferences(residuals, width))
+
+	data := bw.bytes()
+	return writeRIFF(w, data)
+}
+
+// argbPixels flattens m to non-premultiplied ARGB with green already
+// subtracted from red and blue
+func argbPixels(m image.Image) ([]uint32, bool) {
+	b := m.Bounds()
+	nrgba, ok := m.(*image.NRGBA)
+	if !ok || nrgba.Rect.Min != (image.Point{}) {
+		nrgba = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(nrgba, nrgba.Rect, m, b.Min, draw.Src)
+	}
+
+	width, height := nrgba.Rect.Dx(), nrgba.Rect.Dy()
+	pixels := make([]uint32, 0, width*height)
+	opaque := true
+	for y := 0; y < height; y++ {
+		row := nrgba.Pix[y*nrgba.Stride : y*nrgba.Stride+width*4]
+		for x := 0; x < len(row); x += 4 {
+			r, g, bl, a := row[x], row[x+1], row[x+2], row[x+3]
+			if a != 0xff {
+				opaque = false
+			}
+			pixels = append(pixels, uint32(a)<<24|uint32(r-g)<<16|uint32(g)<<8|uint32(bl-g))
+		}
+	}
+	return pixels, opaque
+}
+
+func tiles(size int) int {
+	return (size + 1<<predictorBits - 1) >> predictorBits
+}
+
+// predict picks the predictor mode with the smallest residuals for each tile
+// and returns the tile modes as a sub-image along with the residual image
+func predict(pixels []uint32, width, height int) ([]uint32, []uint32) {
+	tilesX, tilesY := tiles(width), tiles(height)
+	modes := make([]uint32, tilesX*tilesY)
+	residuals := make([]uint32, len(pixels))
+
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			x0, y0 := tx<<predictorBits, ty<<predictorBits
+			x1, y1 := min(x0+1<<predictorBits, width), min(y0+1<<predictorBits, height)
+
+			best, bestCost := predictorModes[0], -1
+			for _, mode := range predictorModes {
+				cost := 0
+				for y := y0; y < y1; y++ {
+					for x := x0; x < x1; x++ {
+						cost += residualCost(sub(pixels[y*width+x], predictor(pixels, width, x, y, mode)))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+
+			modes[ty*tilesX+tx] = 0xff000000 | uint32(best)<<8
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					residuals[y*width+x] = sub(pixels[y*width+x], predictor(pixels, width, x, y, best))
+				}
+			}
+		}
+	}
+	return modes, residuals
+}
+
+// predictor returns the prediction for the pixel at (x, y). The first row
+// and column use fixed modes regardless of the tile's choice.
+func predictor(pixels []uint32, width, x, y, mode int) uint32 {
+	i := y*width + x
+	switch {
+	case x == 0 && y == 0:
+		return 0xff000000
+	case y == 0:
+		return pixels[i-1]
+	case x == 0:
+		return pixels[i-width]
+	}
+
+	l, t, tl := pixels[i-1], pixels[i-width], pixels[i-width-1]
+	switch mode {
+	case 1:
+		return l
+	case 2:
+		return t
+	case 7:
+		return average2(l, t)
+	case 11:
+		return selectPredictor(l, t, tl)
+	case 12:
+		return perChannel(l, t, tl, func(a, b, c int) int { return clamp(a + b - c) })
+	case 13:
+		return perChannel(average2(l, t), tl, 0, func(a, b, _ int) int { return clamp(a + (a-b)/2) })
+	}
+	return 0xff000000
+}
+
+func channel(p uint32, shift uint) int {
+	return int(p >> shift & 0xff)
+}
+
+func perChannel(a, b, c uint32, f func(a, b, c int) int) uint32 {
+	var out uint32
+	for shift := uint(0); shift < 32; shift += 8 {
+		out |= uint32(f(channel(a, shift), channel(b, shift), channel(c, shift))) << shift
+	}
+	return out
+}
+
+func average2(a, b uint32) uint32 {
+	return perChannel(a, b, 0, func(a, b, _ int) int { return (a + b) / 2 })
+}
+
+func selectPredictor(l, t, tl uint32) uint32 {
+	pl, pt := 0, 0
+	for shift := uint(0); shift < 32; shift += 8 {
+		pl += abs(channel(tl, shift) - channel(t, shift))
+		pt += abs(channel(tl, shift) - channel(l, shift))
+	}
+	if pl < pt {
+		return l
+	}
+	return t
+}
+
+// sub subtracts each channel of b from a, modulo 256
+func sub(a, b uint32) uint32 {
+	return perChannel(a, b, 0, func(a, b, _ int) int { return (a - b) & 0xff })
+}
+
+// residualCost estimates how expensive a residual is to code: small signed
+// values are cheap
+func residualCost(r uint32) int {
+	cost := 0
+	for shift := uint(0); shift < 32; shift += 8 {
+		cost += abs(int(int8(channel(r, shift))))
+	}
+	return cost
+}
+
+func clamp(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return v
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// writeEntropyImage codes a transform sub-image, which has no prefix code
+// groups of its own
+func writeEntropyImage(bw *bitWriter, pixels []uint32, width int) {
+	bw.writeBits(0, 1) // no color cache
+	writeImageData(bw, backwardReferences(pixels, width))
+}
+
+// backwardReferences greedily replaces runs of repeated pixels with copies
+func backwardReferences(pixels []uint32, width int) []symbol {
+	var head [1 << hashBits]int32
+	for i := range head {
+		head[i] = -1
+	}
+
+	symbols := make([]symbol, 0, len(pixels)/2)
+	for i := 0; i < len(pixels); {
+		bestLen, bestDist := 0, 0
+		try := func(dist int) {
+			if dist < 1 || dist > i {
+				return
+			}
+			n := matchLength(pixels, i-dist, i)
+			if n > bestLen {
+				bestLen, bestDist = n, dist
+			}
+		}
+
+		try(1)
+		try(width)
+		var h uint32
+		if i+minMatch <= len(pixels) {
+			h = hash(pixels[i:])
+			if prev := head[h]; prev >= 0 {
+				try(i - int(prev))
+			}
+		}
+
+		if bestLen < minMatch {
+			symbols = append(symbols, symbol{argb: pixels[i]})
+			if i+minMatch <= len(pixels) {
+				head[h] = int32(i)
+			}
+			i++
+			continue
+		}
+
+		symbols = append(symbols, symbol{length: bestLen, dist: distanceCode(bestDist, width)})
+		for end := i + bestLen; i < end; i++ {
+			if i+minMatch <= len(pixels) {
+				head[hash(pixels[i:])] = int32(i)
+			}
+		}
+	}
+	return symbols
+}
+
+func matchLength(pixels []uint32, from, to int) int {
+	n := 0
+	for to+n < len(pixels) && n < maxMatch && pixels[from+n] == pixels[to+n] {
+		n++
+	}
+	return n
+}
+
+func hash(p []uint32) uint32 {
+	v := p[0]*0x9e3779b1 ^ p[1]*0x85ebca6b ^ p[2]*0xc2b2ae35
+	return v >> (32 - hashBits)
+}
+
+// distanceCode maps a linear pixel distance to its VP8L distance code. The
+// two most common neighbours have short codes in the spec's distance map.
+func distanceCode(dist, width int) int {
+	switch dist {
+	case width:
+		return 1
+	case 1:
+		return 2
+	}
+	return dist + 120
+}
+
+// prefixEncode splits a length or distance into its prefix code and the
+// extra bits that follow it
+func prefixEncode(value int) (code, extraBits, extra int) {
+	d := value - 1
+	if d < 4 {
+		return d, 0, 0
+	}
+	hi := 31
+	for d>>hi == 0 {
+		hi--
+	}
+	second := (d >> (hi - 1)) & 1
+	extraBits = hi - 1
+	return 2*hi + second, extraBits, d & (1<<extraBits - 1)
+}
+
+func writeImageData(bw *bitWriter, symbols []symbol) {
+	green := make([]int, numLiterals+numLengthCodes)
+	red := make([]int, numLiterals)
+	blue := make([]int, numLiterals)
+	alpha := make([]int, numLiterals)
+	dist := make([]int, numDistanceCodes)
+
+	for _, s := range symbols {
+		if s.length == 0 {
+			green[s.argb>>8&0xff]++
+			red[s.argb>>16&0xff]++
+			blue[s.argb&0xff]++
+			alpha[s.argb>>24]++
+			continue
+		}
+		code, _, _ := prefixEncode(s.length)
+		green[numLiterals+code]++
+		code, _, _ = prefixEncode(s.dist)
+		dist[code]++
+	}
+
+	codes := [5]*prefixCode{}
+	for i, histogram := range [][]int{green, red, blue, alpha, dist} {
+		codes[i] = newPrefixCode(histogram, maxCodeLength)
+		codes[i].write(bw)
+	}
+
+	for _, s := range symbols {
+		if s.length == 0 {
+			codes[0].emit(bw, int(s.argb>>8&0xff))
+			codes[1].emit(bw, int(s.argb>>16&0xff))
+			codes[2].emit(bw, int(s.argb&0xff))
+			codes[3].emit(bw, int(s.argb>>24))
+			continue
+		}
+		code, n, extra := prefixEncode(s.length)
+		codes[0].emit(bw, numLiterals+code)
+		bw.writeBits(uint32(extra), n)
+		code, n, extra = prefixEncode(s.dist)
+		codes[4].emit(bw, code)
+		bw.writeBits(uint32(extra), n)
+	}
+}
+
+func writeRIFF(w io.Writer, data []byte) error {
+	padded := len(data) + len(data)&1
+	bw := bufio.NewWriter(w)
+
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(12+padded))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+
+	bw.Write(header)
+	bw.Write(data)
+	if len(data)&1 == 1 {
+		bw.WriteByte(0)
+	}
+	return bw.Flush()
+}
+
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nBits uint
+}
+
+// writeBits appends the low n bits of v, least significant bit first
+func (w *bitWriter) writeBits(v uint32, n int) {
+	w.acc |= uint64(v&(1<<uint(n)-1)) << w.nBits
+	w.nBits += uint(n)
+	for w.nBits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nBits -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nBits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nBits = 0, 0
+	}
+	return w.buf
+}
@@ -0,0 +1,167 @@
+package webp
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func flat(w, h int, c color.NRGBA) *image.NRGBA {
+	m := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(m.Pix); i += 4 {
+		m.Pix[i], m.Pix[i+1], m.Pix[i+2], m.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return m
+}
+
+func noise(w, h int, seed int64, opaque bool) *image.NRGBA {
+	r := rand.New(rand.NewSource(seed))
+	m := image.NewNRGBA(image.Rect(0, 0, w, h))
+	r.Read(m.Pix)
+	if opaque {
+		for i := 3; i < len(m.Pix); i += 4 {
+			m.Pix[i] = 0xff
+		}
+	}
+	return m
+}
+
+func gradient(w, h int) *image.NRGBA {
+	m := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			m.SetNRGBA(x, y, color.NRGBA{uint8(x * 7), uint8(y * 5), uint8(x + y), 0xff})
+		}
+	}
+	return m
+}
+
+// stripes repeats a short row pattern, so most pixels are backward references
+func stripes(w, h int) *image.NRGBA {
+	palette := []color.NRGBA{{0xff, 0, 0, 0xff}, {0, 0xff, 0, 0xff}, {0, 0, 0xff, 0xff}, {0x10, 0x20, 0x30, 0x80}}
+	m := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			m.SetNRGBA(x, y, palette[(x/3+y)%len(palette)])
+		}
+	}
+	return m
+}
+
+func roundTrip(t *testing.T, m image.Image) image.Image {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Encode(&buf, m); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded, err := webp.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	return decoded
+}
+
+// assertSame compares non-premultiplied pixels, so the colour of fully
+// transparent pixels counts too: lossless means lossless
+func assertSame(t *testing.T, want, got image.Image) {
+	t.Helper()
+	wb, gb := want.Bounds(), got.Bounds()
+	if wb.Dx() != gb.Dx() || wb.Dy() != gb.Dy() {
+		t.Fatalf("decoded %dx%d, want %dx%d", gb.Dx(), gb.Dy(), wb.Dx(), wb.Dy())
+	}
+	for y := 0; y < wb.Dy(); y++ {
+		for x := 0; x < wb.Dx(); x++ {
+			w := color.NRGBAModel.Convert(want.At(wb.Min.X+x, wb.Min.Y+y))
+			g := color.NRGBAModel.Convert(got.At(gb.Min.X+x, gb.Min.Y+y))
+			if w != g {
+				t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{"1x1 opaque", flat(1, 1, color.NRGBA{0x12, 0x34, 0x56, 0xff})},
+		{"1x1 transparent", flat(1, 1, color.NRGBA{0x12, 0x34, 0x56, 0})},
+		{"1x1 noise", noise(1, 1, 1, false)},
+		{"flat white", flat(64, 48, color.NRGBA{0xff, 0xff, 0xff, 0xff})},
+		{"flat black", flat(33, 17, color.NRGBA{0, 0, 0, 0xff})},
+		{"flat translucent", flat(20, 20, color.NRGBA{0x80, 0x40, 0x20, 0x7f})},
+		{"single row", noise(97, 1, 2, true)},
+		{"single column", noise(1, 97, 3, true)},
+		{"odd opaque noise", noise(17, 33, 4, true)},
+		{"odd noise with alpha", noise(31, 15, 5, false)},
+		{"tile boundaries", noise(16, 16, 6, true)},
+		{"just past a tile", noise(17, 17, 7, false)},
+		{"gradient", gradient(129, 65)},
+		{"stripes", stripes(203, 101)},
+		{"large noise", noise(257, 131, 8, true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSame(t, tt.img, roundTrip(t, tt.img))
+		})
+	}
+}
+
+func TestRoundTripOtherImageTypes(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(0, 0, 13, 9))
+	for i := range rgba.Pix {
+		rgba.Pix[i] = uint8(i * 31)
+	}
+	for i := 3; i < len(rgba.Pix); i += 4 {
+		rgba.Pix[i] = 0xff
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, 11, 7))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i * 13)
+	}
+
+	// A sub-image doesn't start at the origin
+	sub := noise(40, 40, 9, false).SubImage(image.Rect(5, 7, 28, 30))
+
+	for name, m := range map[string]image.Image{"rgba": rgba, "gray": gray, "sub-image": sub} {
+		t.Run(name, func(t *testing.T) {
+			assertSame(t, m, roundTrip(t, m))
+		})
+	}
+}
+
+func TestEncodeRIFF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, noise(5, 3, 10, false)); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	data := buf.Bytes()
+	if string(data[:4]) != "RIFF" || string(data[8:16]) != "WEBPVP8L" {
+		t.Fatalf("header = %q", data[:16])
+	}
+	if size := int(data[4]) | int(data[5])<<8 | int(data[6])<<16 | int(data[7])<<24; size != len(data)-8 {
+		t.Errorf("RIFF size = %d, want %d", size, len(data)-8)
+	}
+	if len(data)%2 != 0 {
+		t.Errorf("file length %d is odd; RIFF chunks are padded to even sizes", len(data))
+	}
+}
+
+func TestEncodeTooLarge(t *testing.T) {
+	for _, r := range []image.Rectangle{
+		image.Rect(0, 0, 0, 0),
+		image.Rect(0, 0, 10, 0),
+		image.Rect(0, 0, MaxDimension+1, 1),
+		image.Rect(0, 0, 1, MaxDimension+1),
+	} {
+		if err := Encode(&bytes.Buffer{}, image.NewNRGBA(r)); err != ErrTooLarge {
+			t.Errorf("Encode(%v) = %v, want ErrTooLarge", r, err)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package webp
+
+import "container/heap"
+
+// prefixCode is a canonical Huffman code over one alphabet
+type prefixCode struct {
+	lengths []int
+	codes   []uint32 // bit-reversed, ready to write least significant bit first
+	used    []int    // symbols with a non-zero count, ascending
+}
+
+// newPrefixCode builds a code for the given symbol counts with no code
+// longer than limit bits
+func newPrefixCode(histogram []int, limit int) *prefixCode {
+	pc := &prefixCode{lengths: make([]int, len(histogram)), codes: make([]uint32, len(histogram))}
+	for s, count := range histogram {
+		if count > 0 {
+			pc.used = append(pc.used, s)
+		}
+	}
+
+	switch len(pc.used) {
+	case 0:
+		// Nothing is coded with it, but the stream still needs a code
+		pc.used = []int{0}
+		return pc
+	case 1:
+		// A single symbol takes zero bits
+		return pc
+	}
+
+	pc.lengths = codeLengths(histogram, limit)
+	pc.codes = canonicalCodes(pc.lengths)
+	return pc
+}
+
+// emit writes the code for symbol s
+func (pc *prefixCode) emit(bw *bitWriter, s int) {
+	if pc.lengths[s] > 0 {
+		bw.writeBits(pc.codes[s], pc.lengths[s])
+	}
+}
+
+// write stores the code in the bitstream, using the compact "simple" form
+// when at most two symbols below 256 are used
+func (pc *prefixCode) write(bw *bitWriter) {
+	if len(pc.used) <= 2 && pc.used[len(pc.used)-1] < 256 {
+		bw.writeBits(1, 1)
+		bw.writeBits(uint32(len(pc.used)-1), 1)
+		first := pc.used[0]
+		if first < 2 {
+			bw.writeBits(0, 1)
+			bw.writeBits(uint32(first), 1)
+		} else {
+			bw.writeBits(1, 1)
+			bw.writeBits(uint32(first), 8)
+		}
+		if len(pc.used) == 2 {
+			bw.writeBits(uint32(pc.used[1]), 8)
+		}
+		// Simple codes assign one bit per symbol in the order given
+		if len(pc.used) == 2 {
+			pc.lengths[pc.used[0]], pc.codes[pc.used[0]] = 1, 0
+			pc.lengths[pc.used[1]], pc.codes[pc.used[1]] = 1, 1
+		}
+		return
+	}
+
+	// A single symbol above 255 still needs a normal code; any non-zero
+	// length makes the decoder treat it as a zero-bit code
+	lengths := pc.lengths
+	if len(pc.used) == 1 {
+		lengths = make([]int, len(pc.lengths))
+		lengths[pc.used[0]] = 1
+	}
+
+	tokens := runLengthTokens(lengths)
+	histogram := make([]int, len(codeLengthCodeOrder))
+	for _, t := range tokens {
+		histogram[t.code]++
+	}
+	clc := newPrefixCode(histogram, maxCodeLengthCodeLength)
+	clLengths := clc.lengths
+	if len(clc.used) == 1 {
+		clLengths = make([]int, len(histogram))
+		clLengths[clc.used[0]] = 1
+	}
+
+	n := len(codeLengthCodeOrder)
+	for n > 4 && clLengths[codeLengthCodeOrder[n-1]] == 0 {
+		n--
+	}
+
+	bw.writeBits(0, 1) // normal code
+	bw.writeBits(uint32(n-4), 4)
+	for _, s := range codeLengthCodeOrder[:n] {
+		bw.writeBits(uint32(clLengths[s]), 3)
+	}
+	bw.writeBits(0, 1) // lengths cover the whole alphabet
+
+	for _, t := range tokens {
+		clc.emit(bw, t.code)
+		if t.extraBits > 0 {
+			bw.writeBits(uint32(t.extra), t.extraBits)
+		}
+	}
+}
+
+type lengthToken struct {
+	code      int
+	extraBits int
+	extra     int
+}
+
+// runLengthTokens encodes code lengths, collapsing runs of zeros with the
+// repeat codes 17 (3-10 zeros) and 18 (11-138 zeros)
+func runLengthTokens(lengths []int) []lengthToken {
+	var tokens []lengthToken
+	for i := 0; i < len(lengths); {
+		if lengths[i] != 0 {
+			tokens = append(tokens, lengthToken{code: lengths[i]})
+			i++
+			continue
+		}
+
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == 0 {
+			run++
+		}
+		i += run
+		for run > 0 {
+			switch {
+			case run >= 11:
+				n := run
+				if n > 138 {
+					n = 138
+				}
+				tokens = append(tokens, lengthToken{code: 18, extraBits: 7, extra: n - 11})
+				run -= n
+			case run >= 3:
+				tokens = append(tokens, lengthToken{code: 17, extraBits: 3, extra: run - 3})
+				run = 0
+			default:
+				tokens = append(tokens, lengthToken{code: 0})
+				run--
+			}
+		}
+	}
+	return tokens
+}
+
+type node struct {
+	count  int
+	symbol int // -1 for internal nodes
+	left   *node
+	right  *node
+}
+
+type nodeHeap []*node
+
+func (h nodeHeap) Len() int            { return len(h) }
+func (h nodeHeap) Less(i, j int) bool  { return h[i].count < h[j].count }
+func (h nodeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x interface{}) { *h = append(*h, x.(*node)) }
+func (h *nodeHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// codeLengths builds Huffman code lengths no longer than limit. When the tree
+// is too deep the counts are flattened and the tree rebuilt, which converges
+// quickly and costs little compression in practice.
+func codeLengths(histogram []int, limit int) []int {
+	counts := append([]int(nil), histogram...)
+	for {
+		lengths := make([]int, len(counts))
+		h := nodeHeap{}
+		for s, count := range counts {
+			if count > 0 {
+				h = append(h, &node{count: count, symbol: s})
+			}
+		}
+		heap.Init(&h)
+		for h.Len() > 1 {
+			a := heap.Pop(&h).(*node)
+			b := heap.Pop(&h).(*node)
+			heap.Push(&h, &node{count: a.count + b.count, symbol: -1, left: a, right: b})
+		}
+
+		maxDepth := assignDepths(h[0], 0, lengths)
+		if maxDepth <= limit {
+			return lengths
+		}
+		for s, count := range counts {
+			if count > 0 {
+				counts[s] = count>>1 | 1
+			}
+		}
+	}
+}
+
+func assignDepths(n *node, depth int, lengths []int) int {
+	if n.symbol >= 0 {
+		lengths[n.symbol] = depth
+		return depth
+	}
+	l := assignDepths(n.left, depth+1, lengths)
+	r := assignDepths(n.right, depth+1, lengths)
+	if l > r {
+		return l
+	}
+	return r
+}
+
+// canonicalCodes assigns codes in order of length then symbol, as DEFLATE
+// does, and reverses them for the least-significant-bit-first stream
+func canonicalCodes(lengths []int) []uint32 {
+	var counts [maxCodeLength + 1]int
+	for _, l := range lengths {
+		counts[l]++
+	}
+	counts[0] = 0
+
+	var next [maxCodeLength + 1]uint32
+	code := uint32(0)
+	for l := 1; l <= maxCodeLength; l++ {
+		code = (code + uint32(counts[l-1])) << 1
+		next[l] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		codes[s] = reverse(next[l], l)
+		next[l]++
+	}
+	return codes
+}
+
+func reverse(v uint32, n int) uint32 {
+	var r uint32
+	for i := 0; i < n; i++ {
+		r = r<<1 | v&1
+		v >>= 1
+	}
+	return r
+}